@@ -0,0 +1,137 @@
+package main
+
+import (
+	contract "autonity-oracle/contract_binder/contract"
+	"autonity-oracle/server"
+	"autonity-oracle/types"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/namsral/flag"
+	"github.com/shopspring/decimal"
+)
+
+// The backtesting command replays the recorded samples of plugins through the price aggregation of the oracle server
+// and compares the produced prices with the historic medians of the oracle contract, per symbol and per strategy.
+func main() { //nolint
+	var samplesFile, mediansFile, confFile, outputFile, wsURL, symbols string
+	var fromRound, toRound uint64
+	flag.StringVar(&samplesFile, "samples", "", "The .csv file or the sample recorder's directory of the recorded samples of plugins")
+	flag.StringVar(&mediansFile, "medians", "", "The .csv file of the historic medians, they are queried from -ws if not set")
+	flag.StringVar(&confFile, "config", "", "The .yml file of the strategies, the routes and the outlier threshold to be replayed")
+	flag.StringVar(&outputFile, "output", "", "The .json file to write the results in, besides the table printed")
+	flag.StringVar(&wsURL, "ws", "", "The web socket URL of an Autonity node to query the historic medians")
+	flag.StringVar(&symbols, "symbols", "", "The comma separated symbols of the historic medians to be queried")
+	flag.Uint64Var(&fromRound, "from", 0, "The first round of the historic medians to be queried")
+	flag.Uint64Var(&toRound, "to", 0, "The last round of the historic medians to be queried")
+	flag.Parse()
+
+	if len(samplesFile) == 0 {
+		log.Fatal("the recorded samples are required, please set -samples")
+	}
+
+	conf, err := server.LoadBacktestConfig(confFile)
+	if err != nil {
+		log.Fatalf("invalid backtest config: %s", err.Error())
+	}
+
+	var samples []server.BacktestSample
+	if info, e := os.Stat(samplesFile); e == nil && info.IsDir() {
+		samples, err = server.LoadRecordedSamples(samplesFile)
+	} else {
+		samples, err = server.LoadBacktestSamples(samplesFile)
+	}
+	if err != nil {
+		log.Fatalf("cannot load samples: %s", err.Error())
+	}
+
+	var medians []server.RoundMedian
+	if len(mediansFile) != 0 {
+		medians, err = server.LoadRoundMedians(mediansFile)
+	} else {
+		medians, err = queryMedians(wsURL, strings.Split(symbols, ","), fromRound, toRound)
+	}
+	if err != nil {
+		log.Fatalf("cannot load historic medians: %s", err.Error())
+	}
+
+	results, err := server.Backtest(conf, samples, medians)
+	if err != nil {
+		log.Fatalf("backtest failed: %s", err.Error())
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STRATEGY\tSYMBOL\tROUNDS\tMISSING\tOUTLIERS\tOUTLIER RATE\tMEAN CONFIDENCE\tMEAN(bps)\tP50(bps)\tP90(bps)\tP99(bps)\tMAX(bps)")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.4f\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\n", r.Strategy, r.Symbol, r.Rounds,
+			r.Missing, r.Outliers, r.OutlierRate, r.MeanConfidence, r.Mean, r.P50, r.P90, r.P99, r.Max)
+	}
+	w.Flush()
+
+	if len(outputFile) != 0 {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			log.Fatalf("cannot encode results: %s", err.Error())
+		}
+		if err = os.WriteFile(outputFile, data, 0600); err != nil {
+			log.Fatalf("cannot write results: %s", err.Error())
+		}
+	}
+}
+
+// queryMedians queries the historic medians of the rounds from the oracle contract. The reports aggregated in the
+// median of a round are committed in the previous round, thus they are sampled at the start of the previous round,
+// which is the timestamp of the round data two rounds before.
+func queryMedians(wsURL string, symbols []string, from, to uint64) ([]server.RoundMedian, error) {
+	if len(wsURL) == 0 || len(symbols) == 0 || len(symbols[0]) == 0 {
+		return nil, fmt.Errorf("either -medians or -ws with -symbols is required")
+	}
+	if from < 2 || to < from {
+		return nil, fmt.Errorf("invalid round range [%d, %d], the first round should be at least 2", from, to)
+	}
+
+	dialer := &types.L1Dialer{}
+	client, err := dialer.Dial(wsURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	oc, err := contract.NewOracle(types.OracleContractAddress, client)
+	if err != nil {
+		return nil, err
+	}
+
+	precision := int32(-int(server.OracleDecimals))
+	var medians []server.RoundMedian
+	for round := from; round <= to; round++ {
+		for _, s := range symbols {
+			symbol := strings.TrimSpace(s)
+			sampled, err := oc.GetRoundData(&bind.CallOpts{}, new(big.Int).SetUint64(round-2), symbol)
+			if err != nil {
+				return nil, err
+			}
+			data, err := oc.GetRoundData(&bind.CallOpts{}, new(big.Int).SetUint64(round), symbol)
+			if err != nil {
+				return nil, err
+			}
+			// the round that no one reported carries over the last median, it is not a measurement of the round.
+			if !data.Success {
+				continue
+			}
+			medians = append(medians, server.RoundMedian{
+				Round:  round,
+				TS:     sampled.Timestamp.Int64(),
+				Symbol: symbol,
+				Price:  decimal.NewFromBigInt(data.Price, precision),
+			})
+		}
+	}
+	return medians, nil
+}
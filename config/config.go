@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -21,41 +22,116 @@ var (
 	defaultPluginDir              = "./plugins"
 	defaultProfileDir             = "."
 	defaultVoteBufferAfterPenalty = uint64(3600 * 24) // The buffering time window in blocks to continue vote after the last penalty event.
+	defaultMaxSampleAge           = 0                 // The max age in seconds of a sample's quote TS against the round's sampling TS, 0 means no limit.
+	defaultAnchorDeviation        = 500               // The deviation in basis points of an aggregated price from an anchor to be warned.
+	defaultPreSamplingRange       = uint64(6)         // The pre-sampling starts in 6 blocks in advance of the next round.
+	defaultSamplingInterval       = 1                 // 1s, the sampling interval during the pre-sampling period.
+	defaultHealthCheckInterval    = 10                // 10s, the interval to check L1 connectivity and to gc round data.
+	defaultSampleTTL              = 30                // 30s, the TTL of a sample before GC it.
 
 	ConfidenceStrategyLinear  = 0
 	ConfidenceStrategyFixed   = 1
 	defaultConfidenceStrategy = ConfidenceStrategyLinear // 0: linear, 1: fixed.
+
+	AggregationStrategyTWAP = 0
+	AggregationStrategyVWAP = 1
 )
 
 // Version number of the oracle server in uint8. It is required
 // for data reporting interface to collect oracle clients version.
 const Version uint8 = 27
 
-const PreSamplingRange = 6 // pre-sampling starts in 6s in advance
-
 // MetricsNameSpace is the name space of oracle-server's metrics in influxDB.
 const MetricsNameSpace = "autoracle."
 const MetricsInterval = time.Second * 10
 
 // DefaultConfig are values to be taken when the specific configs are omitted from config file.
 var DefaultConfig = ServerConfig{
-	LoggingLevel:       defaultLogVerbosity,
-	GasTipCap:          defaultGasTipCap,
-	VoteBuffer:         defaultVoteBufferAfterPenalty,
-	KeyFile:            defaultKeyFile,
-	KeyPassword:        defaultKeyPassword,
-	AutonityWSUrl:      defaultAutonityWSUrl,
-	PluginDir:          defaultPluginDir,
-	ProfileDir:         defaultProfileDir,
-	ConfidenceStrategy: defaultConfidenceStrategy,
-	PluginConfigs:      nil,
-	MetricConfigs:      DefaultMetricConfig,
+	LoggingLevel:        defaultLogVerbosity,
+	GasTipCap:           defaultGasTipCap,
+	VoteBuffer:          defaultVoteBufferAfterPenalty,
+	KeyFile:             defaultKeyFile,
+	KeyPassword:         defaultKeyPassword,
+	AutonityWSUrl:       defaultAutonityWSUrl,
+	PluginDir:           defaultPluginDir,
+	ProfileDir:          defaultProfileDir,
+	ConfidenceStrategy:  defaultConfidenceStrategy,
+	MaxSampleAge:        defaultMaxSampleAge,
+	AnchorDeviation:     defaultAnchorDeviation,
+	PreSamplingRange:    defaultPreSamplingRange,
+	SamplingInterval:    defaultSamplingInterval,
+	HealthCheckInterval: defaultHealthCheckInterval,
+	AMMSampling:         DefaultAMMSamplingConfig,
+	CEXSampling:         DefaultSamplingConfig,
+	SymbolRoutes:        DefaultSymbolRoutes,
+	SampleRecorder:      DefaultSampleRecorderConfig,
+	PluginConfigs:       nil,
+	MetricConfigs:       DefaultMetricConfig,
+	Tracing:             DefaultTracingConfig,
+	Alerting:            DefaultAlertingConfig,
+}
+
+// DefaultSamplingConfig is the default sampling config of a data source type, it takes the nearest sample of the round.
+var DefaultSamplingConfig = SamplingConfig{
+	SampleTTL: defaultSampleTTL,
+}
+
+// DefaultAMMSamplingConfig is the default sampling config of AMM data sources, it takes the TWAP of the samples in the
+// recent window, thus a single manipulated swap near the round boundary is much less effective.
+var DefaultAMMSamplingConfig = SamplingConfig{
+	SampleTTL: defaultSampleTTL,
+	Window:    defaultSampleTTL,
+	Strategy:  AggregationStrategyTWAP,
+}
+
+// SamplingConfig contains the configuration of the samples buffered from the plugins of a data source type.
+type SamplingConfig struct {
+	SampleTTL int `json:"sampleTTL" yaml:"sampleTTL"` // The TTL in seconds of a sample before GC it.
+	Window    int `json:"window" yaml:"window"`       // The window in seconds of samples before the round to be aggregated, 0 takes the nearest sample.
+	Strategy  int `json:"strategy" yaml:"strategy"`   // The aggregation strategy of the samples in the window, 0: TWAP, 1: VWAP.
+}
+
+// DefaultSampleRecorderConfig is the default config of the sample recorder, it is disabled by default.
+var DefaultSampleRecorderConfig = SampleRecorderConfig{
+	MaxSize:  64,
+	MaxFiles: 8,
+}
+
+// SampleRecorderConfig contains the configuration of the recorder of the plugins' price reports, the reports are logged
+// in the rotated gzip files under the samples directory of the profile directory.
+type SampleRecorderConfig struct {
+	Enabled  bool `json:"enabled" yaml:"enabled"`   // The flag to enable the recording.
+	MaxSize  int  `json:"maxSize" yaml:"maxSize"`   // The size in MB of a log file before it is rotated.
+	MaxFiles int  `json:"maxFiles" yaml:"maxFiles"` // The number of the log files kept, the oldest one is removed on rotation.
+}
+
+// DefaultSymbolRoutes are the conversion paths of the protocol symbols which are not quoted by the data sources directly.
+var DefaultSymbolRoutes = []SymbolRoute{
+	{Symbol: "USDC-USD", Paths: [][]string{{"USDC-USD"}, {"USDC-USDT", "USDT-USD"}}, Merge: true},
+	{Symbol: "BTC-USD", Paths: [][]string{{"BTC-USD"}, {"BTC-USDT", "USDT-USD"}}, Merge: true},
+	{Symbol: "ETH-USD", Paths: [][]string{{"ETH-USD"}, {"ETH-USDT", "USDT-USD"}}, Merge: true},
+	{Symbol: "ATN-USD", Paths: [][]string{{"ATN-USDC", "USDC-USD"}}},
+	{Symbol: "NTN-USD", Paths: [][]string{{"NTN-USDC", "USDC-USD"}}},
+	{Symbol: "NTN-ATN", Paths: [][]string{{"NTN-ATN"}, {"NTN-USD", "ATN-USD"}}},
+}
+
+// SymbolRoute declares the conversion paths to derive a symbol's price from the prices of other symbols. Each path is
+// a chain of symbols from the base to the quote currency of the derived symbol, e.g. NTN-ATN: [NTN-USD, ATN-USD], a
+// leg whose base currency meets the chain from the quote side is taken in reciprocal. The paths are tried in order,
+// the first one with all of its legs priced is taken, a path with the symbol itself stands for its direct quotes. With
+// merge, the prices of all the priced paths are aggregated together instead, e.g. the USD quotes with the USDT ones.
+type SymbolRoute struct {
+	Symbol string     `json:"symbol" yaml:"symbol"`
+	Paths  [][]string `json:"paths" yaml:"paths"`
+	Merge  bool       `json:"merge" yaml:"merge"`
 }
 
 // DefaultMetricConfig is the default config for metrics used in oracle-server.
 var DefaultMetricConfig = MetricConfig{
 	// Prometheus metrics exposer configs.
 	EnablePrometheusExp: false,
+	EnablePrometheus:    false,
+	PrometheusPath:      "/metrics",
 	HTTP:                "127.0.0.1",
 	Port:                6061,
 
@@ -76,10 +152,86 @@ var DefaultMetricConfig = MetricConfig{
 	InfluxDBOrganization: "autonity",
 }
 
+// The exporters of the traces.
+const (
+	TraceExporterOTLP   = "otlp"   // exports the traces to an OTLP collector over HTTP.
+	TraceExporterStdout = "stdout" // prints the traces to the standard output.
+	TraceExporterFile   = "file"   // appends the traces to a file for offline use.
+)
+
+// DefaultTracingConfig is the default config of the round tracing, it is disabled by default.
+var DefaultTracingConfig = TracingConfig{
+	Exporter: TraceExporterOTLP,
+	Endpoint: "localhost:4318",
+	File:     "traces.json",
+}
+
+// TracingConfig contains the configuration of the OpenTelemetry tracing of the rounds, a trace covers a round from its
+// event to the receipt of the vote, with the plugins' price fetching linked to it.
+type TracingConfig struct {
+	Enabled  bool   `json:"enabled" yaml:"enabled"`   // The flag to enable the tracing.
+	Exporter string `json:"exporter" yaml:"exporter"` // The exporter of the traces: otlp, stdout or file.
+	Endpoint string `json:"endpoint" yaml:"endpoint"` // The host:port of the OTLP HTTP collector.
+	Insecure bool   `json:"insecure" yaml:"insecure"` // The flag to connect the OTLP collector without TLS.
+	File     string `json:"file" yaml:"file"`         // The file of the traces of the file exporter, relative to the profile dir.
+}
+
+// The sinks of the alerts.
+const (
+	AlertSinkWebhook = "webhook" // posts the alert in JSON to the URL.
+	AlertSinkScript  = "script"  // executes the command with the alert in JSON on its stdin.
+	AlertSinkSlack   = "slack"   // posts the alert as a message to the Slack compatible incoming webhook URL.
+	AlertSinkSMTP    = "smtp"    // mails the alert over SMTP.
+)
+
+// DefaultAlertingConfig is the default config of the alerting, it is disabled by default. The threshold of the low
+// balance is the balance in wei at or below which it alerts, the one of the lost sync is the seconds of the lost
+// L1 connectivity, the one of the no-reveal is the missed reveals, and the ones of the others are the occurrences.
+var DefaultAlertingConfig = AlertingConfig{
+	LowBalance:     AlertCondition{Threshold: 2000000000000, Dedup: 3600}, // 2000 Gwei.
+	OutlierPenalty: AlertCondition{Threshold: 1},
+	InvalidVote:    AlertCondition{Threshold: 1, Dedup: 600},
+	NoReveal:       AlertCondition{Threshold: 1, Dedup: 600},
+	LostSync:       AlertCondition{Threshold: 60, Dedup: 600},
+}
+
+// AlertingConfig contains the configuration of the alerts on the critical conditions of the oracle server, and the
+// sinks that they are sent to.
+type AlertingConfig struct {
+	Enabled        bool           `json:"enabled" yaml:"enabled"` // The flag to enable the alerting.
+	LowBalance     AlertCondition `json:"lowBalance" yaml:"lowBalance"`
+	OutlierPenalty AlertCondition `json:"outlierPenalty" yaml:"outlierPenalty"`
+	InvalidVote    AlertCondition `json:"invalidVote" yaml:"invalidVote"`
+	NoReveal       AlertCondition `json:"noReveal" yaml:"noReveal"`
+	LostSync       AlertCondition `json:"lostSync" yaml:"lostSync"`
+	Sinks          []AlertSink    `json:"sinks" yaml:"sinks"`
+}
+
+// AlertCondition contains the configuration of the alert of a condition.
+type AlertCondition struct {
+	Disabled  bool   `json:"disabled" yaml:"disabled"`   // The flag to disable the alert of the condition.
+	Threshold uint64 `json:"threshold" yaml:"threshold"` // The threshold of the condition to alert.
+	Dedup     int    `json:"dedup" yaml:"dedup"`         // The seconds in which the repeated alerts of the condition are suppressed.
+}
+
+// AlertSink contains the configuration of a sink of the alerts.
+type AlertSink struct {
+	Type     string   `json:"type" yaml:"type"`         // The type of the sink: webhook, script, slack or smtp.
+	URL      string   `json:"url" yaml:"url"`           // The URL of the webhook and the slack sinks.
+	Command  string   `json:"command" yaml:"command"`   // The command of the script sink.
+	Host     string   `json:"host" yaml:"host"`         // The host:port of the SMTP server.
+	Username string   `json:"username" yaml:"username"` // The username of the SMTP server, the auth is skipped if it is empty.
+	Password string   `json:"password" yaml:"password"` // The password of the SMTP server.
+	From     string   `json:"from" yaml:"from"`         // The sender address of the mails.
+	To       []string `json:"to" yaml:"to"`             // The recipient addresses of the mails.
+}
+
 // MetricConfig contains the configuration for the metric collection of oracle-server.
 type MetricConfig struct {
 	// Prometheus metrics exposer configs
 	EnablePrometheusExp bool   `json:"enablePrometheusExp" yaml:"enablePrometheusExp"`
+	EnablePrometheus    bool   `json:"enablePrometheus" yaml:"enablePrometheus"` // The native prometheus exposition with labels.
+	PrometheusPath      string `json:"prometheusPath" yaml:"prometheusPath"`     // The HTTP path of the native prometheus exposition.
 	HTTP                string `json:"http" yaml:"http"`
 	Port                int    `json:"port" yaml:"port"`
 
@@ -102,17 +254,28 @@ type MetricConfig struct {
 
 // ServerConfig is the schema of oracle-server's config.
 type ServerConfig struct {
-	LoggingLevel       int            `json:"logLevel" yaml:"logLevel"`
-	GasTipCap          uint64         `json:"gasTipCap" yaml:"gasTipCap"`
-	VoteBuffer         uint64         `json:"voteBuffer" yaml:"voteBuffer"`
-	KeyFile            string         `json:"keyFile" yaml:"keyFile"`
-	KeyPassword        string         `json:"keyPassword" yaml:"keyPassword"`
-	AutonityWSUrl      string         `json:"autonityWSUrl" yaml:"autonityWSUrl"`
-	PluginDir          string         `json:"pluginDir" yaml:"pluginDir"`
-	ProfileDir         string         `json:"profileDir" yaml:"profileDir"`
-	ConfidenceStrategy int            `json:"confidenceStrategy" yaml:"confidenceStrategy"`
-	PluginConfigs      []PluginConfig `json:"pluginConfigs" yaml:"pluginConfigs"`
-	MetricConfigs      MetricConfig   `json:"metricConfigs" yaml:"metricConfigs"`
+	LoggingLevel        int                  `json:"logLevel" yaml:"logLevel"`
+	GasTipCap           uint64               `json:"gasTipCap" yaml:"gasTipCap"`
+	VoteBuffer          uint64               `json:"voteBuffer" yaml:"voteBuffer"`
+	KeyFile             string               `json:"keyFile" yaml:"keyFile"`
+	KeyPassword         string               `json:"keyPassword" yaml:"keyPassword"`
+	AutonityWSUrl       string               `json:"autonityWSUrl" yaml:"autonityWSUrl"`
+	PluginDir           string               `json:"pluginDir" yaml:"pluginDir"`
+	ProfileDir          string               `json:"profileDir" yaml:"profileDir"`
+	ConfidenceStrategy  int                  `json:"confidenceStrategy" yaml:"confidenceStrategy"`
+	MaxSampleAge        int                  `json:"maxSampleAge" yaml:"maxSampleAge"`
+	AnchorDeviation     int                  `json:"anchorDeviation" yaml:"anchorDeviation"`
+	PreSamplingRange    uint64               `json:"preSamplingRange" yaml:"preSamplingRange"`
+	SamplingInterval    int                  `json:"samplingInterval" yaml:"samplingInterval"`
+	HealthCheckInterval int                  `json:"healthCheckInterval" yaml:"healthCheckInterval"`
+	AMMSampling         SamplingConfig       `json:"ammSampling" yaml:"ammSampling"`
+	CEXSampling         SamplingConfig       `json:"cexSampling" yaml:"cexSampling"`
+	SymbolRoutes        []SymbolRoute        `json:"symbolRoutes" yaml:"symbolRoutes"`
+	SampleRecorder      SampleRecorderConfig `json:"sampleRecorder" yaml:"sampleRecorder"`
+	PluginConfigs       []PluginConfig       `json:"pluginConfigs" yaml:"pluginConfigs"`
+	MetricConfigs       MetricConfig         `json:"metricConfigs" yaml:"metricConfigs"`
+	Tracing             TracingConfig        `json:"tracing" yaml:"tracing"`
+	Alerting            AlertingConfig       `json:"alerting" yaml:"alerting"`
 }
 
 // PluginConfig is the schema of plugins' config.
@@ -124,11 +287,43 @@ type PluginConfig struct {
 	Endpoint           string `json:"endpoint" yaml:"endpoint"` // The data service endpoint url of the data provider.
 	Timeout            int    `json:"timeout" yaml:"timeout"`   // The timeout period in seconds that an API request is lasting for.
 	DataUpdateInterval int    `json:"refresh" yaml:"refresh"`   // The interval in seconds to fetch data from data provider due to rate limit.
+	// Below configurations are applied by the oracle server on the spawned plugin process.
+	MaxMemory int `json:"maxMemory" yaml:"maxMemory"` // The upper limit in MB of the plugin process' data segment, 0 means no limit.
+	Niceness  int `json:"nice" yaml:"nice"`           // The scheduling niceness of the plugin process from -20 to 19, 0 keeps the default.
+	// The max age in seconds of the plugin's data points, it overrides the server's maxSampleAge, e.g. for the daily
+	// reference rates of central banks, 0 takes the server's one and a negative value means no limit.
+	MaxSampleAge int `json:"maxSampleAge" yaml:"maxSampleAge"`
+	// Below configurations are applied on the HTTP requests of the plugin to the data provider.
+	RateLimit        float64 `json:"rateLimit" yaml:"rateLimit"`               // The max requests per second to the data provider, 0 means no limit.
+	RateBurst        int     `json:"rateBurst" yaml:"rateBurst"`               // The max burst of requests allowed by the rate limit, default 1.
+	MaxRetries       int     `json:"maxRetries" yaml:"maxRetries"`             // The max retries of a request on 5xx, 429 and timeout errors, 0 means no retry.
+	BreakerThreshold int     `json:"breakerThreshold" yaml:"breakerThreshold"` // The consecutive failed requests to stop calling the data provider, 0 disables the circuit breaker.
+	BreakerCooldown  int     `json:"breakerCooldown" yaml:"breakerCooldown"`   // The cooldown period in seconds before calling the data provider again once the circuit breaker is open, default 60s.
+	// Below configurations are applied on the connections of the plugin to the data provider.
+	ProxyURL   string `json:"proxy" yaml:"proxy"`           // The HTTP(S) or SOCKS5 proxy, e.g. socks5://127.0.0.1:1080, the proxy of the environment variables is taken if it is empty.
+	CACert     string `json:"caCert" yaml:"caCert"`         // The PEM file of the extra CA certificates trusted on top of the system ones, e.g. of a TLS inspecting proxy.
+	ClientCert string `json:"clientCert" yaml:"clientCert"` // The PEM file of the client certificate for mTLS.
+	ClientKey  string `json:"clientKey" yaml:"clientKey"`   // The PEM file of the client private key for mTLS.
 	// Below configurations are reserved only for on-chain AMM marketplaces.
+	Pairs          []PairConfig `json:"pairs" yaml:"pairs"`                   // The ERC20 token pairs to be priced from the marketplace.
+	SwapAddress    string       `json:"swapAddress" yaml:"swapAddress"`       // The UniSwap factory contract address or AirSwap SwapERC20 contract address on the target blockchain.
+	BackfillBlocks int          `json:"backfillBlocks" yaml:"backfillBlocks"` // The number of recent blocks to backfill the order book from the historical swap events.
+	PoolVersion    int          `json:"poolVersion" yaml:"poolVersion"`       // The UniSwap version of the pools, 2 for V2 pairs, 3 for V3 pools.
+	FeeTier        int          `json:"feeTier" yaml:"feeTier"`               // The fee tier in hundredths of a bip of the UniSwap V3 pools, e.g. 500, 3000 or 10000.
+	TWAPWindow     int          `json:"twapWindow" yaml:"twapWindow"`         // The window in seconds of the TWAP read from UniSwap V3 pools' observations, 0 reads the spot price.
+	// Deprecated: the token addresses of the ATN-USDC and NTN-USDC pairs, they are migrated into the pairs by the plugins.
 	NTNTokenAddress  string `json:"ntnTokenAddress" yaml:"ntnTokenAddress"`   // The NTN erc20 token address on the target blockchain.
 	ATNTokenAddress  string `json:"atnTokenAddress" yaml:"atnTokenAddress"`   // The Wrapped ATN erc20 token address on the target blockchain.
 	USDCTokenAddress string `json:"usdcTokenAddress" yaml:"usdcTokenAddress"` // The USDC erc20 token address on the target blockchain.
-	SwapAddress      string `json:"swapAddress" yaml:"swapAddress"`           // The UniSwap factory contract address or AirSwap SwapERC20 contract address on the target blockchain.
+}
+
+// PairConfig is an ERC20 token pair priced from the on-chain AMM marketplaces.
+type PairConfig struct {
+	Symbol        string `json:"symbol" yaml:"symbol"`               // The symbol of the pair, e.g. NTN-USDC.
+	BaseToken     string `json:"baseToken" yaml:"baseToken"`         // The base erc20 token address on the target blockchain.
+	QuoteToken    string `json:"quoteToken" yaml:"quoteToken"`       // The quote erc20 token address on the target blockchain.
+	BaseDecimals  int    `json:"baseDecimals" yaml:"baseDecimals"`   // The decimals of the base token, it is read from the token contract if omitted.
+	QuoteDecimals int    `json:"quoteDecimals" yaml:"quoteDecimals"` // The decimals of the quote token, it is read from the token contract if omitted.
 }
 
 func (pc *PluginConfig) Diff(other *PluginConfig) bool {
@@ -139,25 +334,53 @@ func (pc *PluginConfig) Diff(other *PluginConfig) bool {
 		pc.Endpoint != other.Endpoint ||
 		pc.Timeout != other.Timeout ||
 		pc.DataUpdateInterval != other.DataUpdateInterval ||
+		pc.MaxMemory != other.MaxMemory ||
+		pc.Niceness != other.Niceness ||
+		pc.MaxSampleAge != other.MaxSampleAge ||
+		pc.RateLimit != other.RateLimit ||
+		pc.RateBurst != other.RateBurst ||
+		pc.MaxRetries != other.MaxRetries ||
+		pc.BreakerThreshold != other.BreakerThreshold ||
+		pc.BreakerCooldown != other.BreakerCooldown ||
+		pc.ProxyURL != other.ProxyURL ||
+		pc.CACert != other.CACert ||
+		pc.ClientCert != other.ClientCert ||
+		pc.ClientKey != other.ClientKey ||
+		!slices.Equal(pc.Pairs, other.Pairs) ||
 		pc.NTNTokenAddress != other.NTNTokenAddress ||
 		pc.ATNTokenAddress != other.ATNTokenAddress ||
 		pc.USDCTokenAddress != other.USDCTokenAddress ||
-		pc.SwapAddress != other.SwapAddress
+		pc.SwapAddress != other.SwapAddress ||
+		pc.BackfillBlocks != other.BackfillBlocks ||
+		pc.PoolVersion != other.PoolVersion ||
+		pc.FeeTier != other.FeeTier ||
+		pc.TWAPWindow != other.TWAPWindow
 }
 
 // Config is the resolved configuration of the oracle-server.
 type Config struct {
-	ConfigFile         string
-	LoggingLevel       hclog.Level
-	GasTipCap          uint64
-	VoteBuffer         uint64
-	Key                *keystore.Key
-	AutonityWSUrl      string
-	PluginDIR          string
-	ProfileDir         string
-	ConfidenceStrategy int
-	PluginConfigs      map[string]PluginConfig
-	MetricConfigs      MetricConfig
+	ConfigFile          string
+	LoggingLevel        hclog.Level
+	GasTipCap           uint64
+	VoteBuffer          uint64
+	Key                 *keystore.Key
+	AutonityWSUrl       string
+	PluginDIR           string
+	ProfileDir          string
+	ConfidenceStrategy  int
+	MaxSampleAge        int
+	AnchorDeviation     int
+	PreSamplingRange    uint64
+	SamplingInterval    time.Duration
+	HealthCheckInterval time.Duration
+	AMMSampling         SamplingConfig
+	CEXSampling         SamplingConfig
+	SymbolRoutes        []SymbolRoute
+	SampleRecorder      SampleRecorderConfig
+	PluginConfigs       map[string]PluginConfig
+	MetricConfigs       MetricConfig
+	Tracing             TracingConfig
+	Alerting            AlertingConfig
 }
 
 func MakeConfig() *Config {
@@ -198,12 +421,60 @@ func MakeConfig() *Config {
 		os.Exit(1)
 	}
 
+	if err = ValidateSamplingConfigs(config); err != nil {
+		log.SetFlags(0)
+		log.Printf("invalid sampling configs, err: %s", err.Error())
+		os.Exit(1)
+	}
+
+	if config.SampleRecorder.Enabled && (config.SampleRecorder.MaxSize <= 0 || config.SampleRecorder.MaxFiles <= 0) {
+		log.SetFlags(0)
+		log.Println("sampleRecorder.maxSize and sampleRecorder.maxFiles should be greater than 0")
+		os.Exit(1)
+	}
+
 	if config.MetricConfigs.EnableInfluxDB && config.MetricConfigs.EnableInfluxDBV2 {
 		log.SetFlags(0)
 		log.Println("There are two metrics engine enabled, please select one: influxDB or influxDBV2")
 		os.Exit(1)
 	}
 
+	if config.MetricConfigs.EnablePrometheusExp && config.MetricConfigs.EnablePrometheus {
+		log.SetFlags(0)
+		log.Println("There are two prometheus exposers enabled on the same port, please select one: enablePrometheusExp or enablePrometheus")
+		os.Exit(1)
+	}
+
+	if config.MetricConfigs.EnablePrometheus && !strings.HasPrefix(config.MetricConfigs.PrometheusPath, "/") {
+		log.SetFlags(0)
+		log.Println("metricConfigs.prometheusPath should start with /")
+		os.Exit(1)
+	}
+
+	if config.Tracing.Enabled {
+		switch config.Tracing.Exporter {
+		case TraceExporterOTLP, TraceExporterStdout:
+		case TraceExporterFile:
+			if len(config.Tracing.File) == 0 {
+				log.SetFlags(0)
+				log.Println("tracing.file should be set for the file exporter")
+				os.Exit(1)
+			}
+		default:
+			log.SetFlags(0)
+			log.Printf("unknown tracing exporter: %s, please select one: otlp, stdout or file", config.Tracing.Exporter)
+			os.Exit(1)
+		}
+	}
+
+	if config.Alerting.Enabled {
+		if err = ValidateAlertSinks(config.Alerting.Sinks); err != nil {
+			log.SetFlags(0)
+			log.Printf("invalid alerting sinks, err: %s", err.Error())
+			os.Exit(1)
+		}
+	}
+
 	pluginConfigs := make(map[string]PluginConfig)
 	for _, conf := range config.PluginConfigs {
 		c := conf
@@ -211,20 +482,69 @@ func MakeConfig() *Config {
 	}
 
 	return &Config{
-		VoteBuffer:         config.VoteBuffer,
-		GasTipCap:          config.GasTipCap,
-		Key:                key,
-		AutonityWSUrl:      config.AutonityWSUrl,
-		PluginDIR:          config.PluginDir,
-		ProfileDir:         config.ProfileDir,
-		LoggingLevel:       hclog.Level(config.LoggingLevel), //nolint
-		ConfidenceStrategy: config.ConfidenceStrategy,
-		ConfigFile:         oracleConfFile,
-		PluginConfigs:      pluginConfigs,
-		MetricConfigs:      config.MetricConfigs,
+		VoteBuffer:          config.VoteBuffer,
+		GasTipCap:           config.GasTipCap,
+		Key:                 key,
+		AutonityWSUrl:       config.AutonityWSUrl,
+		PluginDIR:           config.PluginDir,
+		ProfileDir:          config.ProfileDir,
+		LoggingLevel:        hclog.Level(config.LoggingLevel), //nolint
+		ConfidenceStrategy:  config.ConfidenceStrategy,
+		MaxSampleAge:        config.MaxSampleAge,
+		AnchorDeviation:     config.AnchorDeviation,
+		PreSamplingRange:    config.PreSamplingRange,
+		SamplingInterval:    time.Duration(config.SamplingInterval) * time.Second,
+		HealthCheckInterval: time.Duration(config.HealthCheckInterval) * time.Second,
+		AMMSampling:         config.AMMSampling,
+		CEXSampling:         config.CEXSampling,
+		SymbolRoutes:        config.SymbolRoutes,
+		SampleRecorder:      config.SampleRecorder,
+		ConfigFile:          oracleConfFile,
+		PluginConfigs:       pluginConfigs,
+		MetricConfigs:       config.MetricConfigs,
+		Tracing:             config.Tracing,
+		Alerting:            config.Alerting,
 	}
 }
 
+// ValidateSamplingConfigs checks the static sampling configs, the pre-sampling range is validated again by the oracle
+// server against the on-chain vote period once it is synced.
+func ValidateSamplingConfigs(config *ServerConfig) error {
+	if config.PreSamplingRange == 0 {
+		return fmt.Errorf("preSamplingRange should be greater than 0")
+	}
+
+	if config.SamplingInterval <= 0 {
+		return fmt.Errorf("samplingInterval should be greater than 0")
+	}
+
+	if config.HealthCheckInterval <= 0 {
+		return fmt.Errorf("healthCheckInterval should be greater than 0")
+	}
+
+	if err := validateSamplingConfig("ammSampling", config.AMMSampling, config.SamplingInterval); err != nil {
+		return err
+	}
+	return validateSamplingConfig("cexSampling", config.CEXSampling, config.SamplingInterval)
+}
+
+func validateSamplingConfig(name string, conf SamplingConfig, samplingInterval int) error {
+	// the samples should live long enough to cover the sampling interval, otherwise there is nothing to aggregate.
+	if conf.SampleTTL < samplingInterval {
+		return fmt.Errorf("%s.sampleTTL should not be less than samplingInterval", name)
+	}
+
+	// the samples out of TTL are GCed, thus the window cannot be longer than the TTL.
+	if conf.Window < 0 || conf.Window > conf.SampleTTL {
+		return fmt.Errorf("%s.window should be in the range of [0, sampleTTL]", name)
+	}
+
+	if conf.Strategy != AggregationStrategyTWAP && conf.Strategy != AggregationStrategyVWAP {
+		return fmt.Errorf("%s.strategy should be 0: TWAP or 1: VWAP", name)
+	}
+	return nil
+}
+
 func LoadKey(keyFile, password string) (*keystore.Key, error) {
 	keyJson, err := os.ReadFile(keyFile)
 	if err != nil {
@@ -254,6 +574,33 @@ func LoadServerConfig(file string) (*ServerConfig, error) {
 	return &config, nil
 }
 
+// ValidateAlertSinks checks that the sinks of the alerts are known and have their destinations set.
+func ValidateAlertSinks(sinks []AlertSink) error {
+	if len(sinks) == 0 {
+		return fmt.Errorf("no alert sinks configured")
+	}
+
+	for i, sink := range sinks {
+		switch sink.Type {
+		case AlertSinkWebhook, AlertSinkSlack:
+			if len(sink.URL) == 0 {
+				return fmt.Errorf("url is required by the %s sink at index %d", sink.Type, i)
+			}
+		case AlertSinkScript:
+			if len(sink.Command) == 0 {
+				return fmt.Errorf("command is required by the script sink at index %d", i)
+			}
+		case AlertSinkSMTP:
+			if len(sink.Host) == 0 || len(sink.From) == 0 || len(sink.To) == 0 {
+				return fmt.Errorf("host, from and to are required by the smtp sink at index %d", i)
+			}
+		default:
+			return fmt.Errorf("unknown alert sink type %q at index %d", sink.Type, i)
+		}
+	}
+	return nil
+}
+
 func LoadPluginsConfig(file string) (map[string]PluginConfig, error) {
 	serverConf, err := LoadServerConfig(file)
 	if err != nil {
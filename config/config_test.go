@@ -36,3 +36,42 @@ func TestFormatVersion(t *testing.T) {
 	require.Equal(t, "v1.2.5", VersionString(125))
 	require.Equal(t, "v2.5.5", VersionString(255))
 }
+
+func TestValidateSamplingConfigs(t *testing.T) {
+	conf := DefaultConfig
+	require.NoError(t, ValidateSamplingConfigs(&conf))
+
+	conf.PreSamplingRange = 0
+	require.Error(t, ValidateSamplingConfigs(&conf))
+
+	conf = DefaultConfig
+	conf.SamplingInterval = 0
+	require.Error(t, ValidateSamplingConfigs(&conf))
+
+	conf = DefaultConfig
+	conf.HealthCheckInterval = -1
+	require.Error(t, ValidateSamplingConfigs(&conf))
+
+	conf = DefaultConfig
+	conf.SamplingInterval = 5
+	conf.AMMSampling.SampleTTL = 60
+	conf.CEXSampling.SampleTTL = 3
+	require.Error(t, ValidateSamplingConfigs(&conf))
+
+	conf.CEXSampling.SampleTTL = 5
+	require.NoError(t, ValidateSamplingConfigs(&conf))
+}
+
+func TestValidateAlertSinks(t *testing.T) {
+	require.Error(t, ValidateAlertSinks(nil))
+	require.NoError(t, ValidateAlertSinks([]AlertSink{
+		{Type: AlertSinkWebhook, URL: "http://localhost:8080/alerts"},
+		{Type: AlertSinkSlack, URL: "https://hooks.slack.com/services/T/B/X"},
+		{Type: AlertSinkScript, Command: "/usr/local/bin/page-oncall"},
+		{Type: AlertSinkSMTP, Host: "localhost:25", From: "oracle@example.com", To: []string{"oncall@example.com"}},
+	}))
+	require.Error(t, ValidateAlertSinks([]AlertSink{{Type: AlertSinkWebhook}}))
+	require.Error(t, ValidateAlertSinks([]AlertSink{{Type: AlertSinkScript}}))
+	require.Error(t, ValidateAlertSinks([]AlertSink{{Type: AlertSinkSMTP, Host: "localhost:25"}}))
+	require.Error(t, ValidateAlertSinks([]AlertSink{{Type: "pager", URL: "http://localhost"}}))
+}
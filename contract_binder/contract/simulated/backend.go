@@ -0,0 +1,632 @@
+// Package simulated implements an in-memory Autonity L1 with the oracle protocol contract, it serves both the
+// contract.ContractAPI and the types.Blockchain of the oracle server for the scenario tests without L1 nodes.
+package simulated
+
+import (
+	contract "autonity-oracle/contract_binder/contract"
+	"autonity-oracle/types"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	tp "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	ErrConnectionDropped = errors.New("simulated L1 connection dropped")
+	ErrNotSupported      = errors.New("not supported by the simulated L1")
+	ErrNonce             = errors.New("invalid nonce")
+	ErrFeeCapTooLow      = errors.New("max fee per gas less than block base fee")
+	ErrInsufficientFunds = errors.New("insufficient funds for gas * price + value")
+
+	oracleCode = []byte{0x60, 0x80} // a non-empty code to mark the oracle contract as deployed.
+)
+
+const (
+	DefaultVoteGas = uint64(150_000)
+	transferGas    = params.TxGas
+	blockGasLimit  = uint64(30_000_000)
+)
+
+// CommitmentHasher computes the commitment hash of the reports, the salt and the sender, it is implemented by the
+// CommitmentHashComputer of the oracle server.
+type CommitmentHasher interface {
+	CommitmentHash(args ...interface{}) (common.Hash, error)
+}
+
+// Config is the genesis of the simulated L1 and its oracle contract.
+type Config struct {
+	ChainID    *big.Int
+	Symbols    []string
+	VotePeriod uint64
+	Voters     []common.Address
+	Balances   map[common.Address]*big.Int
+
+	BaseFee   *big.Int // the base fee of the blocks.
+	GasTipCap *big.Int // the suggested gas tip cap.
+	VoteGas   uint64   // the gas used by a vote transaction.
+
+	NonRevealThreshold uint64 // the missed reveals before a voter gets the no reveal penalty.
+	// the outlier thresholds in basis points of the deviation from the median, a report which deviates more than the
+	// detection threshold is penalized, and it is slashed if it deviates more than the slashing threshold.
+	OutlierDetectionThreshold int64
+	OutlierSlashingThreshold  int64
+	BaseSlashingRate          int64    // the slashing rate in basis points of the stake at the slashing threshold.
+	SlashingRateCap           int64    // the cap of the slashing rate in basis points.
+	Stake                     *big.Int // the stake of each voter that the slashing rate applies on.
+
+	NTNReward *big.Int // the NTN rewards distributed per round, nothing is distributed if it is nil.
+	ATNReward *big.Int // the ATN rewards distributed per round.
+
+	Clock func() time.Time // the clock of the block timestamps, time.Now if it is nil.
+}
+
+// DefaultConfig returns a config of the piccadilly chain ID with the default symbols, and with the outlier thresholds
+// of the protocol.
+func DefaultConfig(voters ...common.Address) Config {
+	balances := make(map[common.Address]*big.Int)
+	for _, v := range voters {
+		balances[v] = new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+	}
+	return Config{
+		ChainID:                   big.NewInt(65_100_004),
+		Symbols:                   []string{"AUD-USD", "CAD-USD", "EUR-USD", "GBP-USD", "JPY-USD", "SEK-USD", "ATN-USD", "NTN-USD", "NTN-ATN"},
+		VotePeriod:                30,
+		Voters:                    voters,
+		Balances:                  balances,
+		BaseFee:                   big.NewInt(params.GWei),
+		GasTipCap:                 big.NewInt(params.GWei),
+		VoteGas:                   DefaultVoteGas,
+		NonRevealThreshold:        3,
+		OutlierDetectionThreshold: 300,  // 3%
+		OutlierSlashingThreshold:  1000, // 10%
+		BaseSlashingRate:          10,   // 0.1%
+		SlashingRateCap:           1000, // 10%
+		Stake:                     new(big.Int).Mul(big.NewInt(10_000), big.NewInt(params.Ether)),
+		Clock:                     time.Now,
+	}
+}
+
+// receipt is a mined transaction.
+type receipt struct {
+	tx      *tp.Transaction
+	receipt *tp.Receipt
+}
+
+// Backend is the simulated L1, blocks are mined on demand by Mine or periodically by StartMining, and the pending
+// transactions are executed in the mined block. The oracle round is rotated every vote period blocks.
+type Backend struct {
+	conf     Config
+	hasher   CommitmentHasher
+	abi      abi.ABI
+	signer   tp.Signer
+	filterer *contract.OracleFilterer
+
+	mutex    sync.RWMutex
+	headers  []*tp.Header
+	logs     [][]*tp.Log // the logs per block.
+	pending  []*tp.Transaction
+	mined    map[common.Hash]*receipt
+	balances map[common.Address]*big.Int
+	nonces   map[common.Address]uint64
+	syncing  bool
+	dropCh   chan struct{}
+
+	oracle *oracleState
+
+	logFeed  event.Feed
+	headFeed event.Feed
+
+	miningMutex sync.Mutex
+	stopMining  chan struct{}
+	miningWG    sync.WaitGroup
+}
+
+// NewBackend creates the simulated L1 with its genesis block, the oracle contract starts at round 1 on block 0.
+func NewBackend(conf Config, hasher CommitmentHasher) (*Backend, error) {
+	if conf.ChainID == nil || conf.VotePeriod == 0 || len(conf.Symbols) == 0 {
+		return nil, fmt.Errorf("invalid config, chain ID, vote period and symbols are required")
+	}
+	if conf.BaseFee == nil {
+		conf.BaseFee = new(big.Int)
+	}
+	if conf.GasTipCap == nil {
+		conf.GasTipCap = new(big.Int)
+	}
+	if conf.VoteGas == 0 {
+		conf.VoteGas = DefaultVoteGas
+	}
+	if conf.Stake == nil {
+		conf.Stake = new(big.Int)
+	}
+	if conf.Clock == nil {
+		conf.Clock = time.Now
+	}
+
+	oracleABI, err := abi.JSON(strings.NewReader(contract.OracleMetaData.ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Backend{
+		conf:     conf,
+		hasher:   hasher,
+		abi:      oracleABI,
+		signer:   tp.LatestSignerForChainID(conf.ChainID),
+		mined:    make(map[common.Hash]*receipt),
+		balances: make(map[common.Address]*big.Int),
+		nonces:   make(map[common.Address]uint64),
+		dropCh:   make(chan struct{}),
+		oracle:   newOracleState(conf),
+	}
+	for addr, balance := range conf.Balances {
+		b.balances[addr] = new(big.Int).Set(balance)
+	}
+
+	b.filterer, err = contract.NewOracleFilterer(types.OracleContractAddress, b)
+	if err != nil {
+		return nil, err
+	}
+
+	genesis := &tp.Header{
+		Number:   new(big.Int),
+		Time:     uint64(conf.Clock().Unix()),
+		GasLimit: blockGasLimit,
+		BaseFee:  new(big.Int).Set(conf.BaseFee),
+	}
+	b.headers = append(b.headers, genesis)
+	b.logs = append(b.logs, nil)
+	return b, nil
+}
+
+// Mine mines a block with the pending transactions, and it rotates the oracle round at the end of the vote period.
+func (b *Backend) Mine() *tp.Header {
+	b.mutex.Lock()
+	parent := b.headers[len(b.headers)-1]
+	header := &tp.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		Time:       uint64(b.conf.Clock().Unix()),
+		GasLimit:   blockGasLimit,
+		BaseFee:    new(big.Int).Set(b.conf.BaseFee),
+	}
+	if header.Time <= parent.Time {
+		header.Time = parent.Time + 1
+	}
+
+	var logs []*tp.Log
+	var receipts []*receipt
+	for i, tx := range b.pending {
+		r := b.applyTransaction(header, tx, uint(i))
+		header.GasUsed += r.receipt.GasUsed
+		r.receipt.CumulativeGasUsed = header.GasUsed
+		logs = append(logs, r.receipt.Logs...)
+		receipts = append(receipts, r)
+	}
+	b.pending = nil
+
+	if header.Number.Uint64() >= b.oracle.lastRoundBlock+b.oracle.votePeriod {
+		logs = append(logs, b.rotateRound(header)...)
+	}
+
+	// the logs and receipts are sealed with the hash and the index of the block.
+	hash := header.Hash()
+	for i, l := range logs {
+		l.BlockNumber = header.Number.Uint64()
+		l.BlockHash = hash
+		l.Index = uint(i)
+	}
+	for _, r := range receipts {
+		r.receipt.BlockHash = hash
+		r.receipt.BlockNumber = header.Number
+		b.mined[r.tx.Hash()] = r
+	}
+	b.headers = append(b.headers, header)
+	b.logs = append(b.logs, logs)
+	b.mutex.Unlock()
+
+	// the events are delivered out of the lock as the subscribers could call into the backend on handling them.
+	b.headFeed.Send(tp.CopyHeader(header))
+	for _, l := range logs {
+		b.logFeed.Send(*l)
+	}
+	return header
+}
+
+// StartMining mines a block per period in the background until StopMining is called.
+func (b *Backend) StartMining(period time.Duration) {
+	b.miningMutex.Lock()
+	defer b.miningMutex.Unlock()
+	if b.stopMining != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	b.stopMining = stop
+	b.miningWG.Add(1)
+	go func() {
+		defer b.miningWG.Done()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				b.Mine()
+			}
+		}
+	}()
+}
+
+func (b *Backend) StopMining() {
+	b.miningMutex.Lock()
+	defer b.miningMutex.Unlock()
+	if b.stopMining == nil {
+		return
+	}
+	close(b.stopMining)
+	b.miningWG.Wait()
+	b.stopMining = nil
+}
+
+// DropSubscriptions terminates the live subscriptions with ErrConnectionDropped, as if the L1 connection was lost.
+func (b *Backend) DropSubscriptions() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	close(b.dropCh)
+	b.dropCh = make(chan struct{})
+}
+
+// SetSyncing sets the block synchronization state reported by SyncProgress.
+func (b *Backend) SetSyncing(syncing bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.syncing = syncing
+}
+
+func (b *Backend) SetBalance(account common.Address, balance *big.Int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.balances[account] = new(big.Int).Set(balance)
+}
+
+// applyTransaction executes the transaction and charges its fee, the state is locked by the caller.
+func (b *Backend) applyTransaction(header *tp.Header, tx *tp.Transaction, index uint) *receipt {
+	sender, _ := tp.Sender(b.signer, tx) // the sender was verified on submission.
+	status := tp.ReceiptStatusSuccessful
+	gasUsed := transferGas
+	var logs []*tp.Log
+
+	if to := tx.To(); to != nil && *to == types.OracleContractAddress {
+		gasUsed = b.conf.VoteGas
+		var err error
+		logs, err = b.applyOracleCall(header, tx, sender)
+		if err != nil {
+			status = tp.ReceiptStatusFailed
+			logs = nil
+		}
+		for _, l := range logs {
+			l.TxHash = tx.Hash()
+			l.TxIndex = index
+		}
+	} else if to != nil && tx.Value().Sign() > 0 {
+		b.balance(sender).Sub(b.balance(sender), tx.Value())
+		b.balance(*to).Add(b.balance(*to), tx.Value())
+	}
+
+	price := effectiveGasPrice(tx, header.BaseFee)
+	fee := new(big.Int).Mul(price, new(big.Int).SetUint64(gasUsed))
+	b.balance(sender).Sub(b.balance(sender), fee)
+	b.nonces[sender] = tx.Nonce() + 1
+
+	return &receipt{tx: tx, receipt: &tp.Receipt{
+		Type:             tx.Type(),
+		Status:           status,
+		Logs:             logs,
+		TxHash:           tx.Hash(),
+		GasUsed:          gasUsed,
+		BlockNumber:      new(big.Int).Set(header.Number),
+		TransactionIndex: index,
+	}}
+}
+
+func (b *Backend) balance(account common.Address) *big.Int {
+	if _, ok := b.balances[account]; !ok {
+		b.balances[account] = new(big.Int)
+	}
+	return b.balances[account]
+}
+
+func (b *Backend) pendingNonce(account common.Address) uint64 {
+	nonce := b.nonces[account]
+	for _, tx := range b.pending {
+		if sender, _ := tp.Sender(b.signer, tx); sender == account {
+			nonce++
+		}
+	}
+	return nonce
+}
+
+func effectiveGasPrice(tx *tp.Transaction, baseFee *big.Int) *big.Int {
+	tip := new(big.Int).Sub(tx.GasFeeCap(), baseFee)
+	if tip.Cmp(tx.GasTipCap()) > 0 {
+		tip = tx.GasTipCap()
+	}
+	return new(big.Int).Add(baseFee, tip)
+}
+
+func (b *Backend) subscription(ch chan<- tp.Log, query ethereum.FilterQuery) ethereum.Subscription {
+	b.mutex.RLock()
+	drop := b.dropCh
+	b.mutex.RUnlock()
+
+	logs := make(chan tp.Log, 128)
+	sub := b.logFeed.Subscribe(logs)
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case l := <-logs:
+				if !matchLog(&l, query) {
+					continue
+				}
+				select {
+				case ch <- l:
+				case <-drop:
+					return ErrConnectionDropped
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-drop:
+				return ErrConnectionDropped
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
+
+func matchLog(l *tp.Log, query ethereum.FilterQuery) bool {
+	if len(query.Addresses) > 0 {
+		var found bool
+		for _, addr := range query.Addresses {
+			if addr == l.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(query.Topics) > len(l.Topics) {
+		return false
+	}
+	for i, sub := range query.Topics {
+		if len(sub) == 0 {
+			continue
+		}
+		var found bool
+		for _, topic := range sub {
+			if topic == l.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Below functions implement the types.Blockchain interface.
+
+func (b *Backend) BalanceAt(_ context.Context, account common.Address, _ *big.Int) (*big.Int, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if balance, ok := b.balances[account]; ok {
+		return new(big.Int).Set(balance), nil
+	}
+	return new(big.Int), nil
+}
+
+func (b *Backend) CodeAt(_ context.Context, contract common.Address, _ *big.Int) ([]byte, error) {
+	if contract == types.OracleContractAddress {
+		return oracleCode, nil
+	}
+	return nil, nil
+}
+
+func (b *Backend) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func (b *Backend) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
+	return b.CodeAt(ctx, contract, nil)
+}
+
+func (b *Backend) PendingCallContract(_ context.Context, _ ethereum.CallMsg) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func (b *Backend) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.pendingNonce(account), nil
+}
+
+func (b *Backend) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return new(big.Int).Add(b.conf.BaseFee, b.conf.GasTipCap), nil
+}
+
+func (b *Backend) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return new(big.Int).Set(b.conf.GasTipCap), nil
+}
+
+func (b *Backend) EstimateGas(_ context.Context, call ethereum.CallMsg) (uint64, error) {
+	if call.To != nil && *call.To == types.OracleContractAddress {
+		return b.conf.VoteGas, nil
+	}
+	return transferGas, nil
+}
+
+// SendTransaction validates the transaction as the tx pool does, and queues it for the next block.
+func (b *Backend) SendTransaction(_ context.Context, tx *tp.Transaction) error {
+	sender, err := tp.Sender(b.signer, tx)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if nonce := b.pendingNonce(sender); tx.Nonce() != nonce {
+		return fmt.Errorf("%w: address %s, tx: %d state: %d", ErrNonce, sender, tx.Nonce(), nonce)
+	}
+	if tx.GasFeeCap().Cmp(b.conf.BaseFee) < 0 {
+		return ErrFeeCapTooLow
+	}
+	if b.balance(sender).Cmp(tx.Cost()) < 0 {
+		return ErrInsufficientFunds
+	}
+	b.pending = append(b.pending, tx)
+	return nil
+}
+
+// FilterLogs filters the logs of the mined blocks, a nil from or to block is the latest block.
+func (b *Backend) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]tp.Log, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	latest := uint64(len(b.headers) - 1)
+	from, to := latest, latest
+	if query.BlockHash != nil {
+		for n, h := range b.headers {
+			if h.Hash() == *query.BlockHash {
+				from, to = uint64(n), uint64(n)
+			}
+		}
+	} else {
+		if query.FromBlock != nil {
+			from = query.FromBlock.Uint64()
+		}
+		if query.ToBlock != nil {
+			to = query.ToBlock.Uint64()
+		}
+	}
+
+	var result []tp.Log
+	for n := from; n <= to && n <= latest; n++ {
+		for _, l := range b.logs[n] {
+			if matchLog(l, query) {
+				result = append(result, *l)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (b *Backend) SubscribeFilterLogs(_ context.Context, query ethereum.FilterQuery, ch chan<- tp.Log) (ethereum.Subscription, error) {
+	return b.subscription(ch, query), nil
+}
+
+func (b *Backend) TransactionReceipt(_ context.Context, txHash common.Hash) (*tp.Receipt, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if r, ok := b.mined[txHash]; ok {
+		return r.receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (b *Backend) SubscribeNewHead(_ context.Context, ch chan<- *tp.Header) (ethereum.Subscription, error) {
+	b.mutex.RLock()
+	drop := b.dropCh
+	b.mutex.RUnlock()
+
+	heads := make(chan *tp.Header, 16)
+	sub := b.headFeed.Subscribe(heads)
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case h := <-heads:
+				select {
+				case ch <- h:
+				case <-drop:
+					return ErrConnectionDropped
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-drop:
+				return ErrConnectionDropped
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+func (b *Backend) TransactionByHash(_ context.Context, txHash common.Hash) (*tp.Transaction, bool, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if r, ok := b.mined[txHash]; ok {
+		return r.tx, false, nil
+	}
+	for _, tx := range b.pending {
+		if tx.Hash() == txHash {
+			return tx, true, nil
+		}
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func (b *Backend) BlockNumber(_ context.Context) (uint64, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return uint64(len(b.headers) - 1), nil
+}
+
+func (b *Backend) HeaderByNumber(_ context.Context, number *big.Int) (*tp.Header, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if number == nil {
+		return tp.CopyHeader(b.headers[len(b.headers)-1]), nil
+	}
+	if !number.IsUint64() || number.Uint64() >= uint64(len(b.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return tp.CopyHeader(b.headers[number.Uint64()]), nil
+}
+
+// Close keeps the simulated L1 running, as it is shared by the oracle servers of a scenario.
+func (b *Backend) Close() {}
+
+func (b *Backend) ChainID(_ context.Context) (*big.Int, error) {
+	return new(big.Int).Set(b.conf.ChainID), nil
+}
+
+func (b *Backend) SyncProgress(_ context.Context) (*ethereum.SyncProgress, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if !b.syncing {
+		return nil, nil
+	}
+	current := uint64(len(b.headers) - 1)
+	return &ethereum.SyncProgress{CurrentBlock: current, HighestBlock: current + 1}, nil
+}
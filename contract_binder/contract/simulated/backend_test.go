@@ -0,0 +1,265 @@
+package simulated
+
+import (
+	contract "autonity-oracle/contract_binder/contract"
+	"autonity-oracle/server"
+	"autonity-oracle/types"
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	tp "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+var testSymbols = []string{"NTN-USD", "ATN-USD"}
+
+type testVoter struct {
+	key  *ecdsa.PrivateKey
+	addr common.Address
+	salt *big.Int
+}
+
+func newTestBackend(t *testing.T, voters int, customize func(conf *Config)) (*Backend, []*testVoter) {
+	var vs []*testVoter
+	var addresses []common.Address
+	for i := 0; i < voters; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		v := &testVoter{key: key, addr: crypto.PubkeyToAddress(key.PublicKey), salt: big.NewInt(int64(i + 1))}
+		vs = append(vs, v)
+		addresses = append(addresses, v.addr)
+	}
+
+	conf := DefaultConfig(addresses...)
+	conf.Symbols = testSymbols
+	conf.VotePeriod = 3
+	if customize != nil {
+		customize(&conf)
+	}
+	hasher, err := server.NewCommitmentHashComputer()
+	require.NoError(t, err)
+	backend, err := NewBackend(conf, hasher)
+	require.NoError(t, err)
+	return backend, vs
+}
+
+func reportsOf(prices ...int64) []contract.IOracleReport {
+	var reports []contract.IOracleReport
+	for _, p := range prices {
+		reports = append(reports, contract.IOracleReport{Price: big.NewInt(p), Confidence: 100})
+	}
+	return reports
+}
+
+// vote commits the reports of this round and reveals the reports of the last round.
+func vote(t *testing.T, b *Backend, v *testVoter, commitReports, revealReports []contract.IOracleReport) *tp.Transaction {
+	hasher, err := server.NewCommitmentHashComputer()
+	require.NoError(t, err)
+	commit, err := hasher.CommitmentHash(commitReports, v.salt, v.addr)
+	require.NoError(t, err)
+
+	opts, err := bind.NewKeyedTransactorWithChainID(v.key, b.conf.ChainID)
+	require.NoError(t, err)
+	tx, err := b.Vote(opts, commit.Big(), revealReports, v.salt, 1)
+	require.NoError(t, err)
+	return tx
+}
+
+func mineRound(b *Backend) {
+	round := b.oracle.round
+	for b.oracle.round == round {
+		b.Mine()
+	}
+}
+
+func filterLogs(t *testing.T, b *Backend, name string) []tp.Log {
+	logs, err := b.FilterLogs(context.Background(), ethereumQuery(b, name))
+	require.NoError(t, err)
+	return logs
+}
+
+func TestRoundRotation(t *testing.T) {
+	b, _ := newTestBackend(t, 1, nil)
+	sub, err := b.SubscribeFilterLogs(context.Background(), ethereumQuery(b, "NewRound"), make(chan tp.Log, 10))
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	for i := 0; i < 7; i++ {
+		b.Mine()
+	}
+	round, err := b.GetRound(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), round.Uint64())
+	lastRoundBlock, err := b.GetLastRoundBlock(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), lastRoundBlock.Uint64())
+
+	logs := filterLogs(t, b, "NewRound")
+	require.Len(t, logs, 2)
+	require.Equal(t, uint64(3), logs[0].BlockNumber)
+
+	// a dropped connection terminates the subscriptions.
+	b.DropSubscriptions()
+	require.ErrorIs(t, <-sub.Err(), ErrConnectionDropped)
+}
+
+func TestCommitReveal(t *testing.T) {
+	b, voters := newTestBackend(t, 3, nil)
+	prices := [][]contract.IOracleReport{reportsOf(100, 10), reportsOf(101, 10), reportsOf(150, 10)}
+
+	// round 1, the voters commit the reports.
+	var txs []*tp.Transaction
+	for i, v := range voters {
+		txs = append(txs, vote(t, b, v, prices[i], nil))
+	}
+	mineRound(b)
+	for _, tx := range txs {
+		receipt, err := b.TransactionReceipt(context.Background(), tx.Hash())
+		require.NoError(t, err)
+		require.Equal(t, tp.ReceiptStatusSuccessful, receipt.Status)
+	}
+	require.Len(t, filterLogs(t, b, "SuccessfulVote"), 3)
+
+	// round 2, the voters reveal the reports of round 1.
+	for i, v := range voters {
+		vote(t, b, v, prices[i], prices[i])
+	}
+	mineRound(b)
+
+	data, err := b.GetRoundData(nil, big.NewInt(2), "NTN-USD")
+	require.NoError(t, err)
+	require.True(t, data.Success)
+	require.Equal(t, int64(101), data.Price.Int64())
+	latest, err := b.LatestRoundData(nil, "ATN-USD")
+	require.NoError(t, err)
+	require.Equal(t, int64(10), latest.Price.Int64())
+
+	// the 3rd voter deviates from the median by 48%, it is penalized and slashed.
+	penalized := filterLogs(t, b, "Penalized")
+	require.Len(t, penalized, 1)
+	event, err := b.filterer.ParsePenalized(penalized[0])
+	require.NoError(t, err)
+	require.Equal(t, voters[2].addr, event.Participant)
+	require.Equal(t, "NTN-USD", event.Symbol)
+	require.True(t, event.SlashingAmount.Sign() > 0)
+
+	// a second vote in the same round is reverted.
+	tx := vote(t, b, voters[0], prices[0], nil)
+	tx2 := vote(t, b, voters[0], prices[0], nil)
+	b.Mine()
+	receipt, err := b.TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, tp.ReceiptStatusSuccessful, receipt.Status)
+	receipt, err = b.TransactionReceipt(context.Background(), tx2.Hash())
+	require.NoError(t, err)
+	require.Equal(t, tp.ReceiptStatusFailed, receipt.Status)
+}
+
+func TestInvalidReveal(t *testing.T) {
+	b, voters := newTestBackend(t, 2, nil)
+	for _, v := range voters {
+		vote(t, b, v, reportsOf(100, 10), nil)
+	}
+	mineRound(b)
+
+	// the first voter reveals the reports with another salt, and the second one reveals a wrong length of reports.
+	voters[0].salt = big.NewInt(100)
+	vote(t, b, voters[0], reportsOf(100, 10), reportsOf(100, 10))
+	vote(t, b, voters[1], reportsOf(100, 10), reportsOf(100))
+	mineRound(b)
+
+	invalid := filterLogs(t, b, "InvalidVote")
+	require.Len(t, invalid, 2)
+	causes := make(map[common.Address]string)
+	for _, l := range invalid {
+		event, err := b.filterer.ParseInvalidVote(l)
+		require.NoError(t, err)
+		causes[event.Reporter] = event.Cause
+	}
+	require.Equal(t, reasonCommitment, causes[voters[0].addr])
+	require.Equal(t, reasonLength, causes[voters[1].addr])
+
+	// the invalid reveals are missed reveals, and the round failed.
+	require.Equal(t, uint64(1), b.NonRevealCount(voters[0].addr))
+	require.Equal(t, uint64(1), b.NonRevealCount(voters[1].addr))
+	data, err := b.GetRoundData(nil, big.NewInt(2), "NTN-USD")
+	require.NoError(t, err)
+	require.False(t, data.Success)
+}
+
+func TestNoRevealPenalty(t *testing.T) {
+	b, voters := newTestBackend(t, 1, func(conf *Config) {
+		conf.NonRevealThreshold = 2
+	})
+	v := voters[0]
+	vote(t, b, v, reportsOf(100, 10), nil)
+	mineRound(b)
+	vote(t, b, v, reportsOf(100, 10), []contract.IOracleReport{})
+	mineRound(b)
+	require.Len(t, filterLogs(t, b, "CommitRevealMissed"), 1)
+	require.Len(t, filterLogs(t, b, "NoRevealPenalty"), 0)
+
+	// no vote in round 3 misses the reveal again, which reaches the threshold.
+	mineRound(b)
+	require.Len(t, filterLogs(t, b, "CommitRevealMissed"), 2)
+	require.Len(t, filterLogs(t, b, "NoRevealPenalty"), 1)
+	require.Equal(t, uint64(2), b.NonRevealCount(v.addr))
+}
+
+func TestTransactionFees(t *testing.T) {
+	b, voters := newTestBackend(t, 1, nil)
+	outsider, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	outsiderAddr := crypto.PubkeyToAddress(outsider.PublicKey)
+
+	// a transaction beyond the funds is rejected.
+	_, err = b.Vote(mustTransactor(t, b, outsider), common.Big1, nil, common.Big1, 1)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// a vote of a non voter is reverted, and the fee is charged.
+	b.SetBalance(outsiderAddr, big.NewInt(1e18))
+	tx, err := b.Vote(mustTransactor(t, b, outsider), common.Big1, nil, common.Big1, 1)
+	require.NoError(t, err)
+	voteTx := vote(t, b, voters[0], reportsOf(100, 10), nil)
+	before, err := b.BalanceAt(context.Background(), voters[0].addr, nil)
+	require.NoError(t, err)
+	b.Mine()
+
+	receipt, err := b.TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, tp.ReceiptStatusFailed, receipt.Status)
+
+	receipt, err = b.TransactionReceipt(context.Background(), voteTx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, tp.ReceiptStatusSuccessful, receipt.Status)
+	after, err := b.BalanceAt(context.Background(), voters[0].addr, nil)
+	require.NoError(t, err)
+	// the fee is the vote gas of the base fee plus the tip.
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(DefaultVoteGas), big.NewInt(2e9))
+	require.Equal(t, fee, new(big.Int).Sub(before, after))
+}
+
+func TestMedian(t *testing.T) {
+	require.Equal(t, int64(2), median([]*big.Int{big.NewInt(3), big.NewInt(1), big.NewInt(2)}).Int64())
+	require.Equal(t, int64(3), median([]*big.Int{big.NewInt(4), big.NewInt(1), big.NewInt(2), big.NewInt(10)}).Int64())
+}
+
+func mustTransactor(t *testing.T, b *Backend, key *ecdsa.PrivateKey) *bind.TransactOpts {
+	opts, err := bind.NewKeyedTransactorWithChainID(key, b.conf.ChainID)
+	require.NoError(t, err)
+	return opts
+}
+
+func ethereumQuery(b *Backend, event string) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: new(big.Int),
+		Addresses: []common.Address{types.OracleContractAddress},
+		Topics:    [][]common.Hash{{b.abi.Events[event].ID}},
+	}
+}
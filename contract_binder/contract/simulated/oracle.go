@@ -0,0 +1,487 @@
+package simulated
+
+import (
+	contract "autonity-oracle/contract_binder/contract"
+	"autonity-oracle/types"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	tp "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+const (
+	decimals         = uint8(18)
+	rateDenominator  = int64(10_000) // the denominator of the thresholds and the rates in basis points.
+	reasonLength     = "invalid reports length"
+	reasonCommitment = "commit mismatch"
+)
+
+var (
+	errRestricted   = errors.New("restricted to the voters")
+	errAlreadyVoted = errors.New("already voted")
+	errUnknownCall  = errors.New("unknown oracle method")
+)
+
+// voterInfo is the last vote of a voter.
+type voterInfo struct {
+	round          uint64
+	commit         *big.Int
+	nonRevealCount uint64
+}
+
+// oracleState is the storage of the simulated oracle contract, it is protected by the mutex of the backend.
+type oracleState struct {
+	round          uint64
+	lastRoundBlock uint64
+	votePeriod     uint64
+
+	symbols     []string
+	lastSymbols []string // the symbols of the last round that the reveals of this round are reported for.
+	newSymbols  []string // the symbols that take effect in the next round.
+	voters      []common.Address
+	newVoters   []common.Address // the voters that take effect in the next round.
+
+	voterInfo map[common.Address]*voterInfo
+	expected  map[common.Address]bool // the voters who committed in the last round and are expected to reveal.
+	reports   map[common.Address][]contract.IOracleReport
+
+	roundData map[uint64]map[string]contract.IOracleRoundData
+	latest    map[string]contract.IOracleRoundData
+}
+
+func newOracleState(conf Config) *oracleState {
+	return &oracleState{
+		round:       1,
+		votePeriod:  conf.VotePeriod,
+		symbols:     append([]string(nil), conf.Symbols...),
+		lastSymbols: append([]string(nil), conf.Symbols...),
+		voters:      append([]common.Address(nil), conf.Voters...),
+		voterInfo:   make(map[common.Address]*voterInfo),
+		expected:    make(map[common.Address]bool),
+		reports:     make(map[common.Address][]contract.IOracleReport),
+		roundData:   make(map[uint64]map[string]contract.IOracleRoundData),
+		latest:      make(map[string]contract.IOracleRoundData),
+	}
+}
+
+func (o *oracleState) isVoter(account common.Address) bool {
+	for _, v := range o.voters {
+		if v == account {
+			return true
+		}
+	}
+	return false
+}
+
+// SetVoters sets the voters of the oracle contract, they take effect from the next round.
+func (b *Backend) SetVoters(voters []common.Address) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.oracle.newVoters = append([]common.Address{}, voters...)
+}
+
+// NonRevealCount returns the missed reveals of the voter.
+func (b *Backend) NonRevealCount(voter common.Address) uint64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if info, ok := b.oracle.voterInfo[voter]; ok {
+		return info.nonRevealCount
+	}
+	return 0
+}
+
+// applyOracleCall executes a call to the oracle contract, an error reverts the transaction.
+func (b *Backend) applyOracleCall(header *tp.Header, tx *tp.Transaction, sender common.Address) ([]*tp.Log, error) {
+	if len(tx.Data()) < 4 {
+		return nil, errUnknownCall
+	}
+	method, err := b.abi.MethodById(tx.Data()[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case "vote":
+		reports := *abi.ConvertType(args[1], new([]contract.IOracleReport)).(*[]contract.IOracleReport)
+		return b.vote(sender, args[0].(*big.Int), reports, args[2].(*big.Int), args[3].(uint8))
+	case "setSymbols":
+		symbols := args[0].([]string)
+		if len(symbols) == 0 {
+			return nil, fmt.Errorf("symbols can't be empty")
+		}
+		b.oracle.newSymbols = symbols
+		return []*tp.Log{b.newLog("NewSymbols", symbols, new(big.Int).SetUint64(b.oracle.round+1))}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownCall, method.Name)
+	}
+}
+
+// vote checks the reveal of the last round commitment, and it records the commitment of this round.
+func (b *Backend) vote(sender common.Address, commit *big.Int, reports []contract.IOracleReport, salt *big.Int,
+	extra uint8) ([]*tp.Log, error) {
+	o := b.oracle
+	if !o.isVoter(sender) && !o.expected[sender] {
+		return nil, errRestricted
+	}
+	info, ok := o.voterInfo[sender]
+	if !ok {
+		info = &voterInfo{}
+		o.voterInfo[sender] = info
+	}
+	if info.round == o.round {
+		return nil, errAlreadyVoted
+	}
+
+	var logs []*tp.Log
+	if o.expected[sender] && len(reports) > 0 {
+		if len(reports) != len(o.lastSymbols) {
+			logs = append(logs, b.newLog("InvalidVote", reasonLength, sender,
+				big.NewInt(int64(len(o.lastSymbols))), big.NewInt(int64(len(reports))), extra))
+		} else if hash, err := b.hasher.CommitmentHash(reports, salt, sender); err != nil || hash.Big().Cmp(info.commit) != 0 {
+			logs = append(logs, b.newLog("InvalidVote", reasonCommitment, sender, info.commit, hash.Big(), extra))
+		} else {
+			o.reports[sender] = reports
+		}
+	}
+
+	info.round = o.round
+	info.commit = new(big.Int).Set(commit)
+	if len(logs) == 0 {
+		logs = append(logs, b.newLog("SuccessfulVote", sender, extra))
+	}
+	return logs, nil
+}
+
+// rotateRound aggregates the reveals of the round, penalizes the outliers and the missed reveals, and starts the
+// next round on the block.
+func (b *Backend) rotateRound(header *tp.Header) []*tp.Log {
+	o := b.oracle
+	var logs []*tp.Log
+	timestamp := new(big.Int).SetUint64(header.Time)
+	round := new(big.Int).SetUint64(o.round)
+
+	// the voters are iterated in a fixed order to keep the logs deterministic.
+	reporters := make([]common.Address, 0, len(o.reports))
+	for voter := range o.reports {
+		reporters = append(reporters, voter)
+	}
+	sort.Slice(reporters, func(i, j int) bool {
+		return reporters[i].Hex() < reporters[j].Hex()
+	})
+
+	data := make(map[string]contract.IOracleRoundData)
+	for i, symbol := range o.lastSymbols {
+		var prices []*big.Int
+		for _, voter := range reporters {
+			if p := o.reports[voter][i].Price; p != nil && p.Sign() > 0 {
+				prices = append(prices, p)
+			}
+		}
+
+		rd := contract.IOracleRoundData{Round: round, Timestamp: timestamp, Price: new(big.Int)}
+		if len(prices) == 0 {
+			// the price of the last round is carried over.
+			if latest, ok := o.latest[symbol]; ok {
+				rd.Price = new(big.Int).Set(latest.Price)
+			}
+		} else {
+			rd.Price = median(prices)
+			rd.Success = true
+			o.latest[symbol] = rd
+			for _, voter := range reporters {
+				logs = append(logs, b.checkOutlier(voter, symbol, rd.Price, o.reports[voter][i].Price)...)
+			}
+		}
+		data[symbol] = rd
+		logs = append(logs, b.newLog("PriceUpdated", rd.Price, round, symbol, rd.Success, timestamp))
+	}
+	o.roundData[o.round] = data
+
+	// the voters who committed in the last round but didn't reveal in this round miss the reveal.
+	var missed []common.Address
+	for voter := range o.expected {
+		if _, ok := o.reports[voter]; !ok {
+			missed = append(missed, voter)
+		}
+	}
+	sort.Slice(missed, func(i, j int) bool {
+		return missed[i].Hex() < missed[j].Hex()
+	})
+	for _, voter := range missed {
+		info := o.voterInfo[voter]
+		info.nonRevealCount++
+		count := new(big.Int).SetUint64(info.nonRevealCount)
+		logs = append(logs, b.newLog("CommitRevealMissed", voter, round, count))
+		if b.conf.NonRevealThreshold > 0 && info.nonRevealCount >= b.conf.NonRevealThreshold {
+			logs = append(logs, b.newLog("NoRevealPenalty", voter, round, count))
+		}
+	}
+
+	if b.conf.NTNReward != nil || b.conf.ATNReward != nil {
+		ntn, atn := new(big.Int), new(big.Int)
+		if b.conf.NTNReward != nil {
+			ntn.Set(b.conf.NTNReward)
+		}
+		if b.conf.ATNReward != nil {
+			atn.Set(b.conf.ATNReward)
+		}
+		logs = append(logs, b.newLog("TotalOracleRewards", ntn, atn))
+	}
+
+	// start the next round.
+	o.expected = make(map[common.Address]bool)
+	for voter, info := range o.voterInfo {
+		if info.round == o.round && info.commit != nil && info.commit.Sign() != 0 {
+			o.expected[voter] = true
+		}
+	}
+	o.reports = make(map[common.Address][]contract.IOracleReport)
+	o.lastSymbols = o.symbols
+	if o.newSymbols != nil {
+		o.symbols, o.newSymbols = o.newSymbols, nil
+	}
+	if o.newVoters != nil {
+		o.voters, o.newVoters = o.newVoters, nil
+	}
+	o.round++
+	o.lastRoundBlock = header.Number.Uint64()
+	logs = append(logs, b.newLog("NewRound", new(big.Int).SetUint64(o.round), timestamp,
+		new(big.Int).SetUint64(o.votePeriod)))
+	return logs
+}
+
+// checkOutlier penalizes the report which deviates from the median over the detection threshold, the slashing
+// rate grows linearly with the deviation over the slashing threshold up to the rate cap.
+func (b *Backend) checkOutlier(voter common.Address, symbol string, median, reported *big.Int) []*tp.Log {
+	if median.Sign() == 0 || b.conf.OutlierDetectionThreshold <= 0 {
+		return nil
+	}
+	diff := new(big.Int).Abs(new(big.Int).Sub(reported, median))
+	deviation := new(big.Int).Div(new(big.Int).Mul(diff, big.NewInt(rateDenominator)), median)
+	if deviation.Cmp(big.NewInt(b.conf.OutlierDetectionThreshold)) <= 0 {
+		return nil
+	}
+
+	slashed := new(big.Int)
+	if b.conf.OutlierSlashingThreshold > 0 && deviation.Cmp(big.NewInt(b.conf.OutlierSlashingThreshold)) > 0 {
+		rate := new(big.Int).Mul(big.NewInt(b.conf.BaseSlashingRate), deviation)
+		rate.Div(rate, big.NewInt(b.conf.OutlierSlashingThreshold))
+		if b.conf.SlashingRateCap > 0 && rate.Cmp(big.NewInt(b.conf.SlashingRateCap)) > 0 {
+			rate.SetInt64(b.conf.SlashingRateCap)
+		}
+		slashed.Mul(b.conf.Stake, rate)
+		slashed.Div(slashed, big.NewInt(rateDenominator))
+	}
+	return []*tp.Log{b.newLog("Penalized", voter, slashed, symbol, median, reported)}
+}
+
+// median returns the median of the prices, it is the mean of the two middle prices for an even count of prices.
+func median(prices []*big.Int) *big.Int {
+	sorted := append([]*big.Int(nil), prices...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return new(big.Int).Set(sorted[mid])
+	}
+	sum := new(big.Int).Add(sorted[mid-1], sorted[mid])
+	return sum.Div(sum, common.Big2)
+}
+
+// newLog packs the event of the oracle contract with its arguments in the order of the ABI.
+func (b *Backend) newLog(name string, args ...interface{}) *tp.Log {
+	ev := b.abi.Events[name]
+	topics := []common.Hash{ev.ID}
+	var data []interface{}
+	for i, input := range ev.Inputs {
+		if !input.Indexed {
+			data = append(data, args[i])
+			continue
+		}
+		topic, err := abi.MakeTopics([]interface{}{args[i]})
+		if err != nil {
+			panic(fmt.Sprintf("cannot make topic of event %s: %v", name, err))
+		}
+		topics = append(topics, topic[0][0])
+	}
+	packed, err := ev.Inputs.NonIndexed().Pack(data...)
+	if err != nil {
+		panic(fmt.Sprintf("cannot pack event %s: %v", name, err))
+	}
+	return &tp.Log{Address: types.OracleContractAddress, Topics: topics, Data: packed}
+}
+
+// transact signs and submits a call to the oracle contract as the bound contract does.
+func (b *Backend) transact(opts *bind.TransactOpts, method string, args ...interface{}) (*tp.Transaction, error) {
+	input, err := b.abi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var nonce uint64
+	if opts.Nonce == nil {
+		if nonce, err = b.PendingNonceAt(ctx, opts.From); err != nil {
+			return nil, err
+		}
+	} else {
+		nonce = opts.Nonce.Uint64()
+	}
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		gasLimit = b.conf.VoteGas
+	}
+	tip := opts.GasTipCap
+	if tip == nil {
+		tip = new(big.Int).Set(b.conf.GasTipCap)
+	}
+	feeCap := opts.GasFeeCap
+	if feeCap == nil {
+		feeCap = new(big.Int).Add(tip, new(big.Int).Mul(b.conf.BaseFee, common.Big2))
+	}
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	to := types.OracleContractAddress
+	tx := tp.NewTx(&tp.DynamicFeeTx{
+		ChainID:   b.conf.ChainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       gasLimit,
+		To:        &to,
+		Value:     value,
+		Data:      input,
+	})
+	if opts.Signer == nil {
+		return nil, errors.New("no signer to authorize the transaction with")
+	}
+	signed, err := opts.Signer(opts.From, tx)
+	if err != nil {
+		return nil, err
+	}
+	if opts.NoSend {
+		return signed, nil
+	}
+	if err = b.SendTransaction(ctx, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// Below functions implement the contract.ContractAPI interface.
+
+func (b *Backend) SetSymbols(opts *bind.TransactOpts, symbols []string) (*tp.Transaction, error) {
+	return b.transact(opts, "setSymbols", symbols)
+}
+
+func (b *Backend) GetSymbols(_ *bind.CallOpts) ([]string, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return append([]string(nil), b.oracle.symbols...), nil
+}
+
+func (b *Backend) Vote(opts *bind.TransactOpts, commit *big.Int, reports []contract.IOracleReport, salt *big.Int,
+	extra uint8) (*tp.Transaction, error) {
+	return b.transact(opts, "vote", commit, reports, salt, extra)
+}
+
+func (b *Backend) GetVotePeriod(_ *bind.CallOpts) (*big.Int, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return new(big.Int).SetUint64(b.oracle.votePeriod), nil
+}
+
+func (b *Backend) GetVoters(_ *bind.CallOpts) ([]common.Address, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return append([]common.Address(nil), b.oracle.voters...), nil
+}
+
+func (b *Backend) GetRound(_ *bind.CallOpts) (*big.Int, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return new(big.Int).SetUint64(b.oracle.round), nil
+}
+
+func (b *Backend) GetLastRoundBlock(_ *bind.CallOpts) (*big.Int, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return new(big.Int).SetUint64(b.oracle.lastRoundBlock), nil
+}
+
+func (b *Backend) WatchNewRound(opts *bind.WatchOpts, sink chan<- *contract.OracleNewRound) (event.Subscription, error) {
+	return b.filterer.WatchNewRound(opts, sink)
+}
+
+func (b *Backend) WatchNewSymbols(opts *bind.WatchOpts, sink chan<- *contract.OracleNewSymbols) (event.Subscription, error) {
+	return b.filterer.WatchNewSymbols(opts, sink)
+}
+
+func (b *Backend) WatchPenalized(opts *bind.WatchOpts, sink chan<- *contract.OraclePenalized,
+	participant []common.Address) (event.Subscription, error) {
+	return b.filterer.WatchPenalized(opts, sink, participant)
+}
+
+func (b *Backend) WatchNoRevealPenalty(opts *bind.WatchOpts, sink chan<- *contract.OracleNoRevealPenalty,
+	voter []common.Address) (event.Subscription, error) {
+	return b.filterer.WatchNoRevealPenalty(opts, sink, voter)
+}
+
+func (b *Backend) WatchSuccessfulVote(opts *bind.WatchOpts, sink chan<- *contract.OracleSuccessfulVote,
+	reporter []common.Address) (event.Subscription, error) {
+	return b.filterer.WatchSuccessfulVote(opts, sink, reporter)
+}
+
+func (b *Backend) WatchInvalidVote(opts *bind.WatchOpts, sink chan<- *contract.OracleInvalidVote,
+	reporter []common.Address) (event.Subscription, error) {
+	return b.filterer.WatchInvalidVote(opts, sink, reporter)
+}
+
+func (b *Backend) WatchTotalOracleRewards(opts *bind.WatchOpts, sink chan<- *contract.OracleTotalOracleRewards) (event.Subscription, error) {
+	return b.filterer.WatchTotalOracleRewards(opts, sink)
+}
+
+func (b *Backend) GetRoundData(_ *bind.CallOpts, round *big.Int, symbol string) (contract.IOracleRoundData, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if data, ok := b.oracle.roundData[round.Uint64()][symbol]; ok {
+		return data, nil
+	}
+	return contract.IOracleRoundData{Round: new(big.Int), Price: new(big.Int), Timestamp: new(big.Int)}, nil
+}
+
+func (b *Backend) LatestRoundData(_ *bind.CallOpts, symbol string) (contract.IOracleRoundData, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if data, ok := b.oracle.latest[symbol]; ok {
+		return data, nil
+	}
+	return contract.IOracleRoundData{Round: new(big.Int), Price: new(big.Int), Timestamp: new(big.Int)}, nil
+}
+
+func (b *Backend) GetDecimals(_ *bind.CallOpts) (uint8, error) {
+	return decimals, nil
+}
+
+var (
+	_ contract.ContractAPI = (*Backend)(nil)
+	_ types.Blockchain     = (*Backend)(nil)
+)
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"autonity-oracle/data_source_simulator/generators"
+	"fmt"
 	"github.com/namsral/flag"
 	"github.com/shopspring/decimal"
 	"strings"
+	"time"
 )
 
 var (
@@ -14,7 +17,11 @@ var (
 	DefSimulatorConf = "ATN-USDC:1.0:0.0003|NTN-USDC:10.0:0.002|NTN-ATN:10.0:0.001"
 	DefSimulatorPort = 50991 // default port bind with the http service in the simulator.
 	DefPlaybook      = ""    // the default playbook file used to replay data points in the generator.
+	DefScenario      = ""    // the default scenario file used to script the data points and faults in the generator.
 	DefTimeout       = 0     // the default timeout simulated when processing a http request.
+	DefRateLimit     = 0     // the default requests per minute allowed by each emulated data provider, 0 for no limit.
+	DefSeed          = 0     // the default seed of the data generators, 0 seeds them with the start time.
+	DefModel         = generators.ModelUniform
 )
 
 type RandGeneratorConfig struct {
@@ -25,8 +32,12 @@ type RandGeneratorConfig struct {
 type SimulatorConfig struct {
 	Port            int
 	Playbook        string
+	Scenario        *Scenario
 	SimulatorConf   map[string]*RandGeneratorConfig
 	SimulateTimeOut int
+	RateLimit       int
+	Seed            int64 // the seed of the simulation, a run is replayed with the same seed and configuration.
+	Model           generators.ModelParams
 }
 
 func MakeSimulatorConfig() *SimulatorConfig {
@@ -34,10 +45,22 @@ func MakeSimulatorConfig() *SimulatorConfig {
 	var port int
 	var simulatorConf string
 	var playbook string
+	var scenarioFile string
+	var rateLimit int
+	var seed int64
+	var model generators.ModelParams
 
 	flag.IntVar(&simulateTimeOut, "sim_timeout", DefTimeout, "The timeout in seconds to be simulated in processing http request")
+	flag.IntVar(&rateLimit, "sim_rate_limit", DefRateLimit, "The requests per minute allowed by each emulated data provider, 0 for no limit")
 	flag.IntVar(&port, "sim_http_port", DefSimulatorPort, "The HTTP rpc port to be bind for binance_simulator simulator")
 	flag.StringVar(&playbook, "sim_playbook_file", DefPlaybook, "The .csv file which contains datapoint for symbols.")
+	flag.StringVar(&scenarioFile, "sim_scenario_file", DefScenario, "The .yml file which scripts the price paths of symbols and the faults.")
+	flag.Int64Var(&seed, "sim_seed", int64(DefSeed), "The seed of the data generators, 0 seeds them with the start time")
+	flag.StringVar(&model.Model, "sim_model", DefModel, "The model of the data points: uniform, gbm or mean_reversion")
+	flag.Float64Var(&model.Drift, "sim_drift", 0, "The drift rate of each data point of the gbm model")
+	flag.Float64Var(&model.Reversion, "sim_reversion", 0.1, "The speed in range (0, 1] reverting to the reference point of the mean_reversion model")
+	flag.Float64Var(&model.JumpRate, "sim_jump_rate", 0, "The probability of a jump on each data point of the gbm and mean_reversion models")
+	flag.Float64Var(&model.JumpSize, "sim_jump_size", 0, "The standard deviation of the log size of the jumps")
 	flag.StringVar(&simulatorConf, "sim_symbol_config", DefSimulatorConf,
 		"The list of data items with the pattern of SYMBOL:StartingDataPoint:DataDistributionRateRange with each separated by a \"|\"")
 
@@ -45,14 +68,37 @@ func MakeSimulatorConfig() *SimulatorConfig {
 
 	conf := ParseSimulatorConf(simulatorConf)
 
+	if err := generators.ValidateModel(model); err != nil {
+		panic(fmt.Sprintf("invalid data model: %s", err.Error()))
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	// print the seed, thus the run can be replayed by setting it back.
+	println("\tRunning simulator with seed: ", seed)
+
 	println("\n\n\n\tRunning simulator with conf: ", simulatorConf)
 	println("\tRunning simulator only with playbook if playbook is configured: ", playbook)
 
+	var scenario *Scenario
+	if len(scenarioFile) != 0 {
+		var err error
+		scenario, err = LoadScenario(scenarioFile)
+		if err != nil {
+			panic(fmt.Sprintf("invalid scenario file %s: %s", scenarioFile, err.Error()))
+		}
+		println("\tRunning simulator with scenario: ", scenarioFile)
+	}
+
 	return &SimulatorConfig{
 		Port:            port,
 		Playbook:        playbook,
+		Scenario:        scenario,
 		SimulatorConf:   conf,
 		SimulateTimeOut: simulateTimeOut,
+		RateLimit:       rateLimit,
+		Seed:            seed,
+		Model:           model,
 	}
 }
 
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"autonity-oracle/data_source_simulator/generators"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The scripted faults of the data feed.
+const (
+	FaultHTTPError = "http_error" // respond the requests with the status code of value by the rate of the requests.
+	FaultLatency   = "latency"    // delay the responses by value seconds.
+)
+
+// ScenarioStep is a timed action of a scenario, the times are in seconds since the scenario starts.
+type ScenarioStep struct {
+	At       int     `json:"at" yaml:"at"`
+	Action   string  `json:"action" yaml:"action"`
+	Value    float64 `json:"value" yaml:"value"`       // the rate of a price move, the lag of stale, the status code or the latency.
+	Duration int     `json:"duration" yaml:"duration"` // the seconds that the action lasts for.
+	Rate     float64 `json:"rate" yaml:"rate"`         // the rate of the requests that fail with the http error, 0 fails all.
+}
+
+// SymbolScenario is the scripted price path of a symbol.
+type SymbolScenario struct {
+	Start float64        `json:"start" yaml:"start"` // the starting data point.
+	Noise float64        `json:"noise" yaml:"noise"` // the data distribution rate range around the path.
+	Steps []ScenarioStep `json:"steps" yaml:"steps"`
+}
+
+// Scenario scripts the price paths of symbols and the faults of the data feed.
+type Scenario struct {
+	Interval int                        `json:"interval" yaml:"interval"` // the seconds between data points, 0 takes the default.
+	Symbols  map[string]*SymbolScenario `json:"symbols" yaml:"symbols"`
+	Faults   []ScenarioStep             `json:"faults" yaml:"faults"`
+}
+
+// Step converts the step into a price path step of the data generator.
+func (s ScenarioStep) Step() generators.Step {
+	return generators.Step{
+		At:       time.Duration(s.At) * time.Second,
+		Action:   s.Action,
+		Value:    s.Value,
+		Duration: time.Duration(s.Duration) * time.Second,
+	}
+}
+
+// ValidateFault checks if the step is a valid fault of the data feed.
+func ValidateFault(s ScenarioStep) error {
+	switch s.Action {
+	case FaultHTTPError:
+		if s.Value < 400 || s.Value > 599 {
+			return fmt.Errorf("http_error requires an error status code in value, got %v", s.Value)
+		}
+		if s.Rate < 0 || s.Rate > 1 {
+			return fmt.Errorf("http_error rate should be in range [0, 1], got %v", s.Rate)
+		}
+	case FaultLatency:
+		if s.Value <= 0 {
+			return fmt.Errorf("latency requires the positive seconds in value")
+		}
+	default:
+		return fmt.Errorf("unknown fault action: %s", s.Action)
+	}
+	if s.At < 0 || s.Duration <= 0 {
+		return fmt.Errorf("%s requires a positive duration", s.Action)
+	}
+	return nil
+}
+
+// LoadScenario reads the scenario from a yaml file and validates it.
+func LoadScenario(file string) (*Scenario, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenario Scenario
+	if err = yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, err
+	}
+
+	if scenario.Interval < 0 {
+		return nil, fmt.Errorf("negative interval of scenario")
+	}
+	if len(scenario.Symbols) == 0 {
+		return nil, fmt.Errorf("no symbol is scripted in scenario")
+	}
+	for symbol, s := range scenario.Symbols {
+		if s == nil || s.Start <= 0 {
+			return nil, fmt.Errorf("symbol %s requires a positive starting data point", symbol)
+		}
+		for _, step := range s.Steps {
+			if err = generators.ValidateStep(step.Step()); err != nil {
+				return nil, fmt.Errorf("symbol %s: %w", symbol, err)
+			}
+		}
+	}
+	for _, f := range scenario.Faults {
+		if err = ValidateFault(f); err != nil {
+			return nil, err
+		}
+	}
+	return &scenario, nil
+}
@@ -22,6 +22,8 @@ var (
 type RandGeneratorManager struct {
 	logger       hclog.Logger
 	conf         map[string]*config.RandGeneratorConfig
+	model        generators.ModelParams
+	seed         int64
 	mutex        sync.RWMutex
 	prices       map[string]decimal.Decimal
 	generators   map[string]data_source_simulator.DataGenerator
@@ -33,16 +35,19 @@ type RandGeneratorManager struct {
 	writer       *csv.Writer
 }
 
-func NewRandGeneratorManager(conf map[string]*config.RandGeneratorConfig) *RandGeneratorManager {
+func NewRandGeneratorManager(conf map[string]*config.RandGeneratorConfig, model generators.ModelParams,
+	seed int64) *RandGeneratorManager {
 	gm := &RandGeneratorManager{
 		conf:       conf,
+		model:      model,
+		seed:       seed,
 		doneCh:     make(chan struct{}),
 		jobTicker:  time.NewTicker(DataGenInterval),
 		prices:     make(map[string]decimal.Decimal),
 		generators: make(map[string]data_source_simulator.DataGenerator),
 	}
 	for k, v := range conf {
-		gm.generators[k] = generators.NewRandDataGenerator(v.ReferenceDataPoint, v.DistributionRate)
+		gm.generators[k] = gm.newGenerator(k, v.ReferenceDataPoint, v.DistributionRate)
 		gm.symbols = append(gm.symbols, k)
 	}
 
@@ -59,6 +64,18 @@ func NewRandGeneratorManager(conf map[string]*config.RandGeneratorConfig) *RandG
 	return gm
 }
 
+// newGenerator creates the generator of the model for a symbol, it is seeded by the symbol thus the data points of a
+// symbol are reproducible regardless of the other symbols.
+func (gm *RandGeneratorManager) newGenerator(symbol string, ref, rate decimal.Decimal) data_source_simulator.DataGenerator {
+	seed := generators.SymbolSeed(gm.seed, symbol)
+	switch gm.model.Model {
+	case generators.ModelGBM, generators.ModelMeanReversion:
+		return generators.NewModelDataGenerator(ref, rate, gm.model, seed)
+	default:
+		return generators.NewRandDataGenerator(ref, rate, seed)
+	}
+}
+
 func (gm *RandGeneratorManager) createDataPointLog() error {
 	// create data point log and write header
 	gm.dataPointLog = fmt.Sprintf(".data-point-%d.csv", os.Getpid())
@@ -108,9 +125,12 @@ func (gm *RandGeneratorManager) AdjustParams(params types.GeneratorParams, metho
 			return fmt.Errorf("InavlidSymbol")
 		}
 		gm.logger.Debug("handle method: ", method)
+		if err := validateTarget(v.Value, method); err != nil {
+			return err
+		}
 		switch method {
 		case "new_simulation":
-			gm.generators[v.Symbol] = generators.NewRandDataGenerator(decimal.NewFromFloat(v.Value), decimal.NewFromFloat(DefaultDistributionRate))
+			gm.generators[v.Symbol] = gm.newGenerator(v.Symbol, decimal.NewFromFloat(v.Value), decimal.NewFromFloat(DefaultDistributionRate))
 			gm.symbols = append(gm.symbols, v.Symbol)
 		case "move_to":
 			gm.generators[v.Symbol].MoveTo(decimal.NewFromFloat(v.Value))
@@ -125,6 +145,22 @@ func (gm *RandGeneratorManager) AdjustParams(params types.GeneratorParams, metho
 	return nil
 }
 
+// validateTarget checks if the data points stay positive after the move, the model data generators work in the log
+// space of the data points thus they cannot start from a non-positive target.
+func validateTarget(value float64, method string) error {
+	switch method {
+	case "new_simulation", "move_to":
+		if value <= 0 {
+			return fmt.Errorf("InvalidTarget")
+		}
+	case "move_by":
+		if value <= -1 {
+			return fmt.Errorf("InvalidPercentage")
+		}
+	}
+	return nil
+}
+
 func (gm *RandGeneratorManager) UpdatePrices() {
 	gm.mutex.Lock()
 	defer gm.mutex.Unlock()
@@ -0,0 +1,213 @@
+package generator_manager
+
+import (
+	"autonity-oracle/data_source_simulator/binance_simulator/config"
+	"autonity-oracle/data_source_simulator/binance_simulator/types"
+	"autonity-oracle/data_source_simulator/generators"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ScenarioGeneratorManager generates the data points of symbols along the scripted price paths of a scenario, and it
+// injects the scripted faults into the data feed. Both can be scripted further during running via the JSON-RPC.
+type ScenarioGeneratorManager struct {
+	logger     hclog.Logger
+	mutex      sync.RWMutex
+	prices     map[string]decimal.Decimal
+	generators map[string]*generators.ScenarioDataGenerator
+	symbols    []string
+	faults     []config.ScenarioStep
+	seed       int64
+	rng        *rand.Rand // the random source of the http errors.
+	clock      func() time.Time
+	start      time.Time
+	doneCh     chan struct{}
+	jobTicker  *time.Ticker
+}
+
+func NewScenarioGeneratorManager(scenario *config.Scenario, seed int64) *ScenarioGeneratorManager {
+	return newScenarioGeneratorManager(scenario, time.Now, seed)
+}
+
+func newScenarioGeneratorManager(scenario *config.Scenario, clock func() time.Time,
+	seed int64) *ScenarioGeneratorManager {
+	interval := DataGenInterval
+	if scenario.Interval > 0 {
+		interval = time.Duration(scenario.Interval) * time.Second
+	}
+
+	sm := &ScenarioGeneratorManager{
+		prices:     make(map[string]decimal.Decimal),
+		generators: make(map[string]*generators.ScenarioDataGenerator),
+		faults:     append([]config.ScenarioStep(nil), scenario.Faults...),
+		seed:       seed,
+		rng:        rand.New(rand.NewSource(seed)), // nolint
+		clock:      clock,
+		start:      clock(),
+		doneCh:     make(chan struct{}),
+		jobTicker:  time.NewTicker(interval),
+	}
+	for symbol, s := range scenario.Symbols {
+		var steps []generators.Step
+		for _, step := range s.Steps {
+			steps = append(steps, step.Step())
+		}
+		sm.generators[symbol] = generators.NewScenarioDataGenerator(decimal.NewFromFloat(s.Start),
+			decimal.NewFromFloat(s.Noise), steps, clock, generators.SymbolSeed(seed, symbol))
+		sm.symbols = append(sm.symbols, symbol)
+	}
+	sort.Strings(sm.symbols)
+
+	sm.logger = hclog.New(&hclog.LoggerOptions{
+		Name:   "BinanceSimulator-Scenario",
+		Level:  hclog.Debug,
+		Output: os.Stdout,
+	})
+
+	// the data points are available from the start of the scenario.
+	sm.UpdatePrices()
+	return sm
+}
+
+func (sm *ScenarioGeneratorManager) GetSymbolPrice(symbols []string) (types.Prices, error) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	var result types.Prices
+
+	// if no symbols specified, return all the symbols' price.
+	syms := symbols
+	if len(symbols) == 0 {
+		syms = sm.symbols
+	}
+
+	for _, s := range syms {
+		p, ok := sm.prices[s]
+		if !ok {
+			return result, fmt.Errorf("InvalidSymbols")
+		}
+		result = append(result, types.Price{
+			Symbol: s,
+			Price:  p.String(),
+		})
+	}
+	return result, nil
+}
+
+// InjectFault resolves the fault of a data request from the active faults of the scenario.
+func (sm *ScenarioGeneratorManager) InjectFault() types.Fault {
+	// the random source of the faults is not safe for concurrent use.
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	var fault types.Fault
+	now := sm.clock().Sub(sm.start)
+	for _, f := range sm.faults {
+		from := time.Duration(f.At) * time.Second
+		if now < from || now >= from+time.Duration(f.Duration)*time.Second {
+			continue
+		}
+		switch f.Action {
+		case config.FaultLatency:
+			fault.Latency += time.Duration(f.Value * float64(time.Second))
+		case config.FaultHTTPError:
+			if f.Rate == 0 || sm.rng.Float64() < f.Rate {
+				fault.Status = int(f.Value)
+			}
+		}
+	}
+	return fault
+}
+
+func (sm *ScenarioGeneratorManager) AdjustParams(params types.GeneratorParams, method string) error {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	for _, v := range params {
+		sm.logger.Debug("handle method: ", method)
+		if err := validateTarget(v.Value, method); err != nil {
+			return err
+		}
+		switch method {
+		case config.FaultHTTPError, config.FaultLatency:
+			// the faults apply on the data feed rather than on a symbol.
+			fault := config.ScenarioStep{
+				At:       int(sm.clock().Sub(sm.start) / time.Second),
+				Action:   method,
+				Value:    v.Value,
+				Duration: v.Duration,
+				Rate:     v.Rate,
+			}
+			if err := config.ValidateFault(fault); err != nil {
+				return err
+			}
+			sm.faults = append(sm.faults, fault)
+			continue
+		case "new_simulation":
+			if _, ok := sm.generators[v.Symbol]; !ok {
+				sm.symbols = append(sm.symbols, v.Symbol)
+			}
+			sm.generators[v.Symbol] = generators.NewScenarioDataGenerator(decimal.NewFromFloat(v.Value),
+				decimal.NewFromFloat(DefaultDistributionRate), nil, sm.clock, generators.SymbolSeed(sm.seed, v.Symbol))
+			continue
+		}
+
+		gen, ok := sm.generators[v.Symbol]
+		if !ok {
+			return fmt.Errorf("InavlidSymbol")
+		}
+		switch method {
+		case "move_to":
+			gen.MoveTo(decimal.NewFromFloat(v.Value))
+		case "move_by":
+			gen.MoveBy(decimal.NewFromFloat(v.Value))
+		case "set_distribution_rate":
+			gen.SetDistributionRate(decimal.NewFromFloat(v.Value))
+		case generators.ActionJump, generators.ActionDrift, generators.ActionCrash, generators.ActionFreeze,
+			generators.ActionStale:
+			step := generators.Step{
+				At:       gen.Elapsed(),
+				Action:   method,
+				Value:    v.Value,
+				Duration: time.Duration(v.Duration) * time.Second,
+			}
+			if err := generators.ValidateStep(step); err != nil {
+				return err
+			}
+			gen.Schedule(step)
+		default:
+			return fmt.Errorf("InvalidMeothd")
+		}
+	}
+	return nil
+}
+
+func (sm *ScenarioGeneratorManager) UpdatePrices() {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	for k, gen := range sm.generators {
+		sm.prices[k] = gen.NextDataPoint()
+		sm.logger.Debug("simulator generates price: ", k, sm.prices[k].String())
+	}
+}
+
+func (sm *ScenarioGeneratorManager) Start() {
+	for {
+		select {
+		case <-sm.doneCh:
+			sm.jobTicker.Stop()
+			sm.logger.Info("the jobTicker jobs of binance_simulator simulator is stopped")
+			return
+		case <-sm.jobTicker.C:
+			sm.UpdatePrices()
+		}
+	}
+}
+
+func (sm *ScenarioGeneratorManager) Stop() {
+	sm.doneCh <- struct{}{}
+}
@@ -0,0 +1,75 @@
+package generator_manager
+
+import (
+	"autonity-oracle/data_source_simulator/binance_simulator/config"
+	"autonity-oracle/data_source_simulator/binance_simulator/types"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenarioGeneratorManager(t *testing.T) {
+	scenario, err := config.LoadScenario("../test_data/scenario-sample.yml")
+	require.NoError(t, err)
+	require.Equal(t, 1, scenario.Interval)
+	require.Len(t, scenario.Symbols, 3)
+	require.Len(t, scenario.Faults, 2)
+
+	now := time.Unix(0, 0)
+	clock := func() time.Time { return now }
+	sm := newScenarioGeneratorManager(scenario, clock, 1)
+	defer sm.jobTicker.Stop()
+
+	prices, err := sm.GetSymbolPrice(nil)
+	require.NoError(t, err)
+	require.Len(t, prices, 3)
+	_, err = sm.GetSymbolPrice([]string{"BTC-USD"})
+	require.Error(t, err)
+
+	// the scripted faults.
+	require.Equal(t, types.Fault{}, sm.InjectFault())
+	now = time.Unix(245, 0)
+	require.Equal(t, types.Fault{Latency: 3 * time.Second}, sm.InjectFault())
+	now = time.Unix(125, 0)
+	var failed int
+	for i := 0; i < 100; i++ {
+		if f := sm.InjectFault(); f.Status == http.StatusServiceUnavailable {
+			failed++
+		}
+	}
+	require.Greater(t, failed, 0)
+	require.Less(t, failed, 100)
+
+	// the steps and the faults scripted during running.
+	require.NoError(t, sm.AdjustParams(types.GeneratorParams{{Symbol: "NTN-ATN", Value: 1}}, "jump"))
+	sm.UpdatePrices()
+	prices, err = sm.GetSymbolPrice([]string{"NTN-ATN"})
+	require.NoError(t, err)
+	require.Equal(t, "NTN-ATN", prices[0].Symbol)
+	// the price doubles from 10 with the noise of 0.1%.
+	require.InDelta(t, 20, decimal.RequireFromString(prices[0].Price).InexactFloat64(), 0.05)
+
+	require.NoError(t, sm.AdjustParams(types.GeneratorParams{{Value: 500, Duration: 10}}, config.FaultHTTPError))
+	require.Equal(t, http.StatusInternalServerError, sm.InjectFault().Status)
+	now = time.Unix(136, 0)
+	require.Equal(t, types.Fault{}, sm.InjectFault())
+
+	require.NoError(t, sm.AdjustParams(types.GeneratorParams{{Symbol: "BTC-USD", Value: 60000}}, "new_simulation"))
+	sm.UpdatePrices()
+	_, err = sm.GetSymbolPrice([]string{"BTC-USD"})
+	require.NoError(t, err)
+
+	require.Error(t, sm.AdjustParams(types.GeneratorParams{{Symbol: "ETH-USD", Value: 1}}, "jump"))
+	require.Error(t, sm.AdjustParams(types.GeneratorParams{{Symbol: "NTN-ATN"}}, "stale"))
+	require.Error(t, sm.AdjustParams(types.GeneratorParams{{Value: 200, Duration: 10}}, config.FaultHTTPError))
+	require.Error(t, sm.AdjustParams(types.GeneratorParams{{Symbol: "NTN-ATN"}}, "unknown"))
+
+	// the targets should stay positive.
+	require.Error(t, sm.AdjustParams(types.GeneratorParams{{Symbol: "NTN-ATN"}}, "move_to"))
+	require.Error(t, sm.AdjustParams(types.GeneratorParams{{Symbol: "NTN-ATN", Value: -1}}, "move_by"))
+	require.Error(t, sm.AdjustParams(types.GeneratorParams{{Symbol: "ETH-USD", Value: -5}}, "new_simulation"))
+	require.NoError(t, sm.AdjustParams(types.GeneratorParams{{Symbol: "NTN-ATN", Value: -0.5}}, "move_by"))
+}
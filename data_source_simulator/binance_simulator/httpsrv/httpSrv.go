@@ -23,13 +23,15 @@ type BinanceSimulatorHTTPServer struct {
 	generators data_source_simulator.GeneratorManager
 	port       int
 	timeout    int
+	rateLimit  int // the requests per minute allowed by each data provider, 0 for no limit.
 }
 
-func NewHttpServer(gen data_source_simulator.GeneratorManager, port int, timeout int) *BinanceSimulatorHTTPServer {
+func NewHttpServer(gen data_source_simulator.GeneratorManager, port int, timeout int, rateLimit int) *BinanceSimulatorHTTPServer {
 	hs := &BinanceSimulatorHTTPServer{
 		generators: gen,
 		port:       port,
 		timeout:    timeout,
+		rateLimit:  rateLimit,
 	}
 	router := hs.createRouter()
 	hs.logger = hclog.New(&hclog.LoggerOptions{
@@ -57,39 +59,10 @@ func (bs *BinanceSimulatorHTTPServer) createRouter() *gin.Engine {
 	gin.SetMode("release")
 	router := gin.Default()
 
-	// data reader handler
-	router.GET("/api/v3/ticker/price", func(c *gin.Context) {
-
-		// if the simulator is configured to simulate timeout.
-		if bs.timeout != 0 {
-			HttpRequestCounter++
-			if HttpRequestCounter%5 == 0 {
-				time.Sleep(time.Second * time.Duration(bs.timeout))
-			}
-		}
-
-		s := c.Query("symbols")
-		var symbols []string
-
-		if s != "" {
-			if err := json.Unmarshal([]byte(s), &symbols); err != nil {
-				c.JSON(http.StatusBadRequest, types2.BadRequest{
-					Code: 400,
-					Msg:  "Invalid parameters",
-				})
-			}
-		}
-
-		prices, err := bs.generators.GetSymbolPrice(symbols)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, types2.BadRequest{
-				Code: 400,
-				Msg:  err.Error(),
-			})
-		}
-
-		c.JSON(http.StatusOK, prices)
-	})
+	// data reader handlers in the API formats of the emulated data providers.
+	for _, p := range providers() {
+		router.GET(p.path, bs.handler(p))
+	}
 
 	router.POST("/", func(c *gin.Context) {
 		var reqMsg types.JSONRPCMessage
@@ -103,6 +76,18 @@ func (bs *BinanceSimulatorHTTPServer) createRouter() *gin.Engine {
 	return router
 }
 
+// injectFault applies the faults scripted by the generators, it returns the http error status to respond with if there
+// is any.
+func (bs *BinanceSimulatorHTTPServer) injectFault() int {
+	injector, ok := bs.generators.(data_source_simulator.FaultInjector)
+	if !ok {
+		return 0
+	}
+	fault := injector.InjectFault()
+	time.Sleep(fault.Latency)
+	return fault.Status
+}
+
 func (bs *BinanceSimulatorHTTPServer) adjustSimulatorParam(reqMsg *types.JSONRPCMessage) (int, types.JSONRPCMessage) {
 	dec := json.NewDecoder(bytes.NewReader(reqMsg.Params))
 	var params types2.GeneratorParams
@@ -0,0 +1,444 @@
+package httpsrv
+
+import (
+	types2 "autonity-oracle/data_source_simulator/binance_simulator/types"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	usd             = "USD"
+	rateLimitWindow = time.Minute
+	simulatedVolume = "1000000"
+)
+
+// coingeckoIDs maps the coin IDs of coingecko to the symbol codes of the simulated data points.
+var coingeckoIDs = map[string]string{
+	"usd-coin": "USDC",
+	"tether":   "USDT",
+	"bitcoin":  "BTC",
+	"ethereum": "ETH",
+}
+
+// provider emulates the API of a data provider with the data points of the generators, the responses and the error
+// envelopes are in the format of the provider.
+type provider struct {
+	name  string
+	path  string
+	keyed bool // if the API key is required in the request.
+	// key resolves the API key from the request.
+	key func(c *gin.Context) string
+	// serve resolves the response of the request from the data points.
+	serve func(c *gin.Context, bs *BinanceSimulatorHTTPServer) (int, interface{})
+	// fail resolves the error envelope of the status code, the providers might return errors in a 200 response.
+	fail func(status int, msg string) (int, interface{})
+}
+
+// rateLimiter limits the requests in a fixed window of time.
+type rateLimiter struct {
+	mutex sync.Mutex
+	limit int
+	start time.Time
+	count int
+}
+
+// allow returns if the request is allowed, and the time to wait before the next window if it is not.
+func (rl *rateLimiter) allow(now time.Time) (bool, time.Duration) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	if rl.limit <= 0 {
+		return true, 0
+	}
+	if now.Sub(rl.start) >= rateLimitWindow {
+		rl.start, rl.count = now, 0
+	}
+	if rl.count >= rl.limit {
+		return false, rl.start.Add(rateLimitWindow).Sub(now)
+	}
+	rl.count++
+	return true, 0
+}
+
+// providers returns the emulated data providers, their paths are registered on the same router.
+func providers() []*provider {
+	return []*provider{
+		binanceProvider(),
+		openExchangeProvider(),
+		currencyFreaksProvider(),
+		currencyLayerProvider(),
+		exchangeRateProvider(),
+		krakenProvider(),
+		coinbaseProvider(),
+		coingeckoProvider(),
+	}
+}
+
+// handler wraps the provider with the simulated timeout, the scripted faults, the rate limit and the key checking.
+func (bs *BinanceSimulatorHTTPServer) handler(p *provider) gin.HandlerFunc {
+	limiter := &rateLimiter{limit: bs.rateLimit}
+	return func(c *gin.Context) {
+		// if the simulator is configured to simulate timeout.
+		if bs.timeout != 0 {
+			HttpRequestCounter++
+			if HttpRequestCounter%5 == 0 {
+				time.Sleep(time.Second * time.Duration(bs.timeout))
+			}
+		}
+
+		if status := bs.injectFault(); status != 0 {
+			c.JSON(p.fail(status, http.StatusText(status)))
+			return
+		}
+
+		if ok, retryAfter := limiter.allow(time.Now()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.JSON(p.fail(http.StatusTooManyRequests, "rate limit exceeded"))
+			return
+		}
+
+		if p.keyed && p.key(c) == "" {
+			c.JSON(p.fail(http.StatusUnauthorized, "missing API key"))
+			return
+		}
+
+		c.JSON(p.serve(c, bs))
+	}
+}
+
+// quotes returns the data points of the symbols, an unknown symbol fails the query.
+func (bs *BinanceSimulatorHTTPServer) quotes(symbols ...string) (map[string]decimal.Decimal, error) {
+	prices, err := bs.generators.GetSymbolPrice(symbols)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]decimal.Decimal, len(prices))
+	for _, p := range prices {
+		d, err := decimal.NewFromString(p.Price)
+		if err != nil {
+			return nil, err
+		}
+		result[p.Symbol] = d
+	}
+	return result, nil
+}
+
+// forexRates returns the rates of the currencies per USD, which are resolved from the data points of the "XXX-USD"
+// symbols.
+func (bs *BinanceSimulatorHTTPServer) forexRates() map[string]decimal.Decimal {
+	rates := map[string]decimal.Decimal{usd: decimal.NewFromInt(1)}
+	prices, err := bs.quotes()
+	if err != nil {
+		return rates
+	}
+	for symbol, p := range prices {
+		codes := strings.Split(symbol, "-")
+		if len(codes) != 2 || codes[1] != usd || len(codes[0]) != 3 || p.IsZero() {
+			continue
+		}
+		rates[codes[0]] = decimal.NewFromInt(1).Div(p)
+	}
+	return rates
+}
+
+func floats(rates map[string]decimal.Decimal) map[string]float64 {
+	result := make(map[string]float64, len(rates))
+	for code, r := range rates {
+		result[code] = r.InexactFloat64()
+	}
+	return result
+}
+
+func binanceProvider() *provider {
+	fail := func(status int, msg string) (int, interface{}) {
+		code := status
+		if status == http.StatusTooManyRequests {
+			code = -1003
+		}
+		return status, types2.BadRequest{Code: code, Msg: msg}
+	}
+	return &provider{
+		name: "binance",
+		path: "/api/v3/ticker/price",
+		fail: fail,
+		serve: func(c *gin.Context, bs *BinanceSimulatorHTTPServer) (int, interface{}) {
+			var symbols []string
+			if s := c.Query("symbols"); s != "" {
+				if err := json.Unmarshal([]byte(s), &symbols); err != nil {
+					return fail(http.StatusBadRequest, "Invalid parameters")
+				}
+			}
+			prices, err := bs.generators.GetSymbolPrice(symbols)
+			if err != nil {
+				return fail(http.StatusBadRequest, err.Error())
+			}
+			return http.StatusOK, prices
+		},
+	}
+}
+
+func openExchangeProvider() *provider {
+	fail := func(status int, msg string) (int, interface{}) {
+		return status, gin.H{"error": true, "status": status, "message": strings.ReplaceAll(
+			strings.ToLower(http.StatusText(status)), " ", "_"), "description": msg}
+	}
+	return &provider{
+		name:  "openexchangerates",
+		path:  "/api/latest.json",
+		keyed: true,
+		key:   func(c *gin.Context) string { return c.Query("app_id") },
+		fail:  fail,
+		serve: func(c *gin.Context, bs *BinanceSimulatorHTTPServer) (int, interface{}) {
+			if base := c.DefaultQuery("base", usd); base != usd {
+				return fail(http.StatusForbidden, "changing the base currency is not allowed")
+			}
+			return http.StatusOK, gin.H{
+				"disclaimer": "Simulated exchange rates",
+				"license":    "Simulated exchange rates",
+				"timestamp":  time.Now().Unix(),
+				"base":       usd,
+				"rates":      floats(bs.forexRates()),
+			}
+		},
+	}
+}
+
+func currencyFreaksProvider() *provider {
+	fail := func(status int, msg string) (int, interface{}) {
+		return status, gin.H{"success": false, "error": gin.H{"status": status, "message": msg}}
+	}
+	return &provider{
+		name:  "currencyfreaks",
+		path:  "/v2.0/rates/latest",
+		keyed: true,
+		key:   func(c *gin.Context) string { return c.Query("apikey") },
+		fail:  fail,
+		serve: func(c *gin.Context, bs *BinanceSimulatorHTTPServer) (int, interface{}) {
+			rates := make(map[string]string)
+			for code, r := range bs.forexRates() {
+				rates[code] = r.String()
+			}
+			return http.StatusOK, gin.H{
+				"date":  time.Now().UTC().Format("2006-01-02 15:04:05-07"),
+				"base":  usd,
+				"rates": rates,
+			}
+		},
+	}
+}
+
+func currencyLayerProvider() *provider {
+	errorTypes := map[int]string{
+		http.StatusUnauthorized:    "missing_access_key",
+		http.StatusTooManyRequests: "usage_limit_reached",
+	}
+	// currencylayer responds the errors with 200, only the server errors carry the status code.
+	fail := func(status int, msg string) (int, interface{}) {
+		code := status
+		if status < http.StatusInternalServerError {
+			code = http.StatusOK
+		}
+		errType, ok := errorTypes[status]
+		if !ok {
+			errType = strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+		}
+		return code, gin.H{"success": false, "error": gin.H{"code": status, "type": errType, "info": msg}}
+	}
+	return &provider{
+		name:  "currencylayer",
+		path:  "/live",
+		keyed: true,
+		key:   func(c *gin.Context) string { return c.Query("access_key") },
+		fail:  fail,
+		serve: func(c *gin.Context, bs *BinanceSimulatorHTTPServer) (int, interface{}) {
+			quotes := make(map[string]float64)
+			for code, r := range bs.forexRates() {
+				quotes[usd+code] = r.InexactFloat64()
+			}
+			return http.StatusOK, gin.H{
+				"success":   true,
+				"terms":     "Simulated exchange rates",
+				"privacy":   "Simulated exchange rates",
+				"timestamp": time.Now().Unix(),
+				"source":    usd,
+				"quotes":    quotes,
+			}
+		},
+	}
+}
+
+func exchangeRateProvider() *provider {
+	errorTypes := map[int]string{
+		http.StatusUnauthorized:    "invalid-key",
+		http.StatusNotFound:        "unsupported-code",
+		http.StatusTooManyRequests: "quota-reached",
+	}
+	fail := func(status int, msg string) (int, interface{}) {
+		errType, ok := errorTypes[status]
+		if !ok {
+			errType = strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "-")
+		}
+		return status, gin.H{"result": "error", "error-type": errType}
+	}
+	return &provider{
+		name:  "exchangerate-api",
+		path:  "/v6/:key/latest/:base",
+		keyed: true,
+		key:   func(c *gin.Context) string { return c.Param("key") },
+		fail:  fail,
+		serve: func(c *gin.Context, bs *BinanceSimulatorHTTPServer) (int, interface{}) {
+			if c.Param("base") != usd {
+				return fail(http.StatusNotFound, "unsupported base")
+			}
+			now := time.Now().UTC()
+			next := now.Add(time.Hour)
+			return http.StatusOK, gin.H{
+				"result":                "success",
+				"documentation":         "Simulated exchange rates",
+				"terms_of_use":          "Simulated exchange rates",
+				"time_last_update_unix": now.Unix(),
+				"time_last_update_utc":  now.Format(time.RFC1123Z),
+				"time_next_update_unix": next.Unix(),
+				"time_next_update_utc":  next.Format(time.RFC1123Z),
+				"base_code":             usd,
+				"conversion_rates":      floats(bs.forexRates()),
+			}
+		},
+	}
+}
+
+func krakenProvider() *provider {
+	errorCodes := map[int]string{
+		http.StatusTooManyRequests: "EAPI:Rate limit exceeded",
+		http.StatusNotFound:        "EQuery:Unknown asset pair",
+	}
+	// kraken responds the errors in the error list of a 200 response, only the server errors carry the status code.
+	fail := func(status int, msg string) (int, interface{}) {
+		code := status
+		if status < http.StatusInternalServerError {
+			code = http.StatusOK
+		}
+		errMsg, ok := errorCodes[status]
+		if !ok {
+			errMsg = "EGeneral:" + msg
+		}
+		return code, gin.H{"error": []string{errMsg}, "result": gin.H{}}
+	}
+	return &provider{
+		name: "kraken",
+		path: "/0/public/Ticker",
+		fail: fail,
+		serve: func(c *gin.Context, bs *BinanceSimulatorHTTPServer) (int, interface{}) {
+			prices, err := bs.quotes()
+			if err != nil {
+				return fail(http.StatusInternalServerError, err.Error())
+			}
+			result := make(map[string]interface{})
+			for _, pair := range strings.Split(c.Query("pair"), ",") {
+				var found bool
+				for symbol, p := range prices {
+					if strings.ReplaceAll(symbol, "-", "") != pair {
+						continue
+					}
+					s := p.String()
+					result[pair] = gin.H{
+						"a": []string{s, "1", "1.000"},
+						"b": []string{s, "1", "1.000"},
+						"c": []string{s, "1.00000000"},
+						"v": []string{simulatedVolume, simulatedVolume},
+						"p": []string{s, s},
+						"t": []int64{1, 1},
+						"l": []string{s, s},
+						"h": []string{s, s},
+						"o": s,
+					}
+					found = true
+				}
+				if !found {
+					return fail(http.StatusNotFound, fmt.Sprintf("unknown pair %s", pair))
+				}
+			}
+			return http.StatusOK, gin.H{"error": []string{}, "result": result}
+		},
+	}
+}
+
+func coinbaseProvider() *provider {
+	fail := func(status int, msg string) (int, interface{}) {
+		id := strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+		if status == http.StatusTooManyRequests {
+			id = "rate_limit_exceeded"
+		}
+		return status, gin.H{"errors": []gin.H{{"id": id, "message": msg}}}
+	}
+	return &provider{
+		name: "coinbase",
+		path: "/v2/prices/:pair/spot",
+		fail: fail,
+		serve: func(c *gin.Context, bs *BinanceSimulatorHTTPServer) (int, interface{}) {
+			pair := c.Param("pair")
+			prices, err := bs.quotes(pair)
+			if err != nil {
+				return fail(http.StatusNotFound, "Invalid currency")
+			}
+			codes := strings.Split(pair, "-")
+			if len(codes) != 2 {
+				return fail(http.StatusNotFound, "Invalid currency")
+			}
+			return http.StatusOK, gin.H{"data": gin.H{
+				"amount":   prices[pair].String(),
+				"base":     codes[0],
+				"currency": codes[1],
+			}}
+		},
+	}
+}
+
+func coingeckoProvider() *provider {
+	fail := func(status int, msg string) (int, interface{}) {
+		if status == http.StatusTooManyRequests {
+			msg = "You've exceeded the Rate Limit. Please visit https://www.coingecko.com/en/api/pricing to subscribe to our API plans for higher rate limits."
+		}
+		return status, gin.H{"status": gin.H{"error_code": status, "error_message": msg}}
+	}
+	return &provider{
+		name: "coingecko",
+		path: "/api/v3/simple/price",
+		fail: fail,
+		serve: func(c *gin.Context, bs *BinanceSimulatorHTTPServer) (int, interface{}) {
+			ids, currencies := c.Query("ids"), c.Query("vs_currencies")
+			if ids == "" || currencies == "" {
+				return http.StatusBadRequest, gin.H{"error": "Missing parameter ids or vs_currencies"}
+			}
+			prices, err := bs.quotes()
+			if err != nil {
+				return fail(http.StatusInternalServerError, err.Error())
+			}
+
+			// unknown coins or currencies are omitted from the response as coingecko does.
+			result := make(map[string]map[string]float64)
+			for _, id := range strings.Split(ids, ",") {
+				code, ok := coingeckoIDs[id]
+				if !ok {
+					continue
+				}
+				for _, vs := range strings.Split(currencies, ",") {
+					if p, ok := prices[code+"-"+strings.ToUpper(vs)]; ok {
+						if _, ok = result[id]; !ok {
+							result[id] = make(map[string]float64)
+						}
+						result[id][vs] = p.InexactFloat64()
+					}
+				}
+			}
+			return http.StatusOK, result
+		},
+	}
+}
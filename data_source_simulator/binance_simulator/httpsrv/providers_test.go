@@ -0,0 +1,123 @@
+package httpsrv
+
+import (
+	"autonity-oracle/data_source_simulator/binance_simulator/config"
+	"autonity-oracle/data_source_simulator/binance_simulator/generator_manager"
+	types2 "autonity-oracle/data_source_simulator/binance_simulator/types"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, rateLimit int) (*httptest.Server, *generator_manager.ScenarioGeneratorManager) {
+	gm := generator_manager.NewScenarioGeneratorManager(&config.Scenario{Symbols: map[string]*config.SymbolScenario{
+		"EUR-USD":  {Start: 1.25},
+		"JPY-USD":  {Start: 0.008},
+		"USDC-USD": {Start: 0.9999},
+		"NTN-USDC": {Start: 10},
+	}}, 0)
+	srv := httptest.NewServer(NewHttpServer(gm, 0, 0, rateLimit).Handler)
+	t.Cleanup(srv.Close)
+	return srv, gm
+}
+
+func get(t *testing.T, url string) (int, map[string]interface{}) {
+	res, err := http.Get(url) // nolint
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	var result map[string]interface{}
+	if err = json.Unmarshal(body, &result); err != nil {
+		// binance responds the prices in a list.
+		return res.StatusCode, map[string]interface{}{"list": string(body)}
+	}
+	return res.StatusCode, result
+}
+
+func TestProviders(t *testing.T) {
+	srv, _ := newTestServer(t, 0)
+
+	status, res := get(t, srv.URL+"/api/latest.json?base=USD&app_id=key")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 0.8, res["rates"].(map[string]interface{})["EUR"])
+	status, res = get(t, srv.URL+"/api/latest.json?base=USD")
+	require.Equal(t, http.StatusUnauthorized, status)
+	require.Equal(t, true, res["error"])
+
+	status, res = get(t, srv.URL+"/v2.0/rates/latest?apikey=key")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "125", res["rates"].(map[string]interface{})["JPY"])
+	require.NotEmpty(t, res["date"])
+
+	status, res = get(t, srv.URL+"/live?access_key=key")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, true, res["success"])
+	require.Equal(t, 0.8, res["quotes"].(map[string]interface{})["USDEUR"])
+	status, res = get(t, srv.URL+"/live")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, false, res["success"])
+
+	status, res = get(t, srv.URL+"/v6/key/latest/USD")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "success", res["result"])
+	status, res = get(t, srv.URL+"/v6/key/latest/EUR")
+	require.Equal(t, http.StatusNotFound, status)
+	require.Equal(t, "unsupported-code", res["error-type"])
+
+	status, res = get(t, srv.URL+"/0/public/Ticker?pair=USDCUSD")
+	require.Equal(t, http.StatusOK, status)
+	ticker := res["result"].(map[string]interface{})["USDCUSD"].(map[string]interface{})
+	require.Equal(t, "0.9999", ticker["p"].([]interface{})[0])
+	status, res = get(t, srv.URL+"/0/public/Ticker?pair=BTCUSD")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, []interface{}{"EQuery:Unknown asset pair"}, res["error"])
+
+	status, res = get(t, srv.URL+"/v2/prices/USDC-USD/spot")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "0.9999", res["data"].(map[string]interface{})["amount"])
+	status, res = get(t, srv.URL+"/v2/prices/BTC-USD/spot")
+	require.Equal(t, http.StatusNotFound, status)
+	require.NotEmpty(t, res["errors"])
+
+	status, res = get(t, srv.URL+"/api/v3/simple/price?ids=usd-coin,bitcoin&vs_currencies=usd")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, map[string]interface{}{"usd-coin": map[string]interface{}{"usd": 0.9999}}, res)
+
+	status, res = get(t, srv.URL+`/api/v3/ticker/price?symbols=["NTN-USDC"]`)
+	require.Equal(t, http.StatusOK, status)
+	var prices types2.Prices
+	require.NoError(t, json.Unmarshal([]byte(res["list"].(string)), &prices))
+	require.Equal(t, types2.Prices{{Symbol: "NTN-USDC", Price: "10"}}, prices)
+}
+
+func TestProvidersFaults(t *testing.T) {
+	srv, gm := newTestServer(t, 2)
+
+	// the rate limit applies on each provider.
+	for i := 0; i < 2; i++ {
+		status, _ := get(t, srv.URL+"/v2/prices/USDC-USD/spot")
+		require.Equal(t, http.StatusOK, status)
+	}
+	res, err := http.Get(srv.URL + "/v2/prices/USDC-USD/spot") // nolint
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	require.NotEmpty(t, res.Header.Get("Retry-After"))
+	status, body := get(t, srv.URL+"/0/public/Ticker?pair=USDCUSD")
+	require.Equal(t, http.StatusOK, status)
+	require.Empty(t, body["error"])
+
+	// the scripted faults are responded in the error envelopes of the providers.
+	require.NoError(t, gm.AdjustParams(types2.GeneratorParams{{Value: 503, Duration: 60}}, config.FaultHTTPError))
+	status, body = get(t, srv.URL+"/0/public/Ticker?pair=USDCUSD")
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, []interface{}{"EGeneral:Service Unavailable"}, body["error"])
+	status, body = get(t, srv.URL+"/api/v3/simple/price?ids=usd-coin&vs_currencies=usd")
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, float64(503), body["status"].(map[string]interface{})["error_code"])
+}
@@ -21,16 +21,19 @@ func main() { //nolint
 
 	// create simulators and start the ticker job to generate data points.
 	var genManager data_source_simulator.GeneratorManager
-	if len(conf.Playbook) == 0 {
-		genManager = generator_manager.NewRandGeneratorManager(conf.SimulatorConf)
-	} else {
+	switch {
+	case conf.Scenario != nil:
+		genManager = generator_manager.NewScenarioGeneratorManager(conf.Scenario, conf.Seed)
+	case len(conf.Playbook) != 0:
 		genManager = generator_manager.NewPlaybookGeneratorManager(conf.Playbook)
+	default:
+		genManager = generator_manager.NewRandGeneratorManager(conf.SimulatorConf, conf.Model, conf.Seed)
 	}
 
 	go genManager.Start()
 	defer genManager.Stop()
 	// create http service.
-	srv := httpsrv.NewHttpServer(genManager, conf.Port, conf.SimulateTimeOut)
+	srv := httpsrv.NewHttpServer(genManager, conf.Port, conf.SimulateTimeOut, conf.RateLimit)
 	srv.StartHTTPServer()
 
 	// Wait for interrupt signal to gracefully shut down the server with
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // Price is the basic data structure returned by Binance.
 type Price struct {
 	Symbol string `json:"symbol,omitempty"`
@@ -14,8 +16,16 @@ type BadRequest struct {
 }
 
 type GeneratorParameter struct {
-	Symbol string  `json:"symbol,omitempty"`
-	Value  float64 `json:"value,omitempty"`
+	Symbol   string  `json:"symbol,omitempty"`
+	Value    float64 `json:"value,omitempty"`
+	Duration int     `json:"duration,omitempty"` // the seconds that a scenario action lasts for.
+	Rate     float64 `json:"rate,omitempty"`     // the rate of the requests that fail with a scenario http error.
 }
 
 type GeneratorParams []GeneratorParameter
+
+// Fault is the fault injected into the handling of a data request.
+type Fault struct {
+	Latency time.Duration // the delay before responding the request.
+	Status  int           // the http error status code to respond with, 0 for no error.
+}
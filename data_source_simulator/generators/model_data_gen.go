@@ -0,0 +1,99 @@
+package generators
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/shopspring/decimal"
+)
+
+// The stochastic models of the data points.
+const (
+	ModelUniform       = "uniform"        // the data points distribute uniformly around the reference point.
+	ModelGBM           = "gbm"            // the data points follow a geometric brownian motion from the reference point.
+	ModelMeanReversion = "mean_reversion" // the data points wander around and revert to the reference point.
+)
+
+// ModelParams are the parameters of a stochastic model, the rates apply on each data point while the volatility is
+// taken from the data distribution rate of the generator.
+type ModelParams struct {
+	Model     string
+	Drift     float64 // the drift rate of the gbm model.
+	Reversion float64 // the speed in range (0, 1] reverting to the reference point of the mean reversion model.
+	JumpRate  float64 // the probability of a jump on the data point, it applies on both gbm and mean reversion.
+	JumpSize  float64 // the standard deviation of the log size of the jumps.
+}
+
+// ValidateModel checks if the parameters are valid for the model.
+func ValidateModel(p ModelParams) error {
+	switch p.Model {
+	case "", ModelUniform, ModelGBM:
+	case ModelMeanReversion:
+		if p.Reversion <= 0 || p.Reversion > 1 {
+			return fmt.Errorf("mean reversion speed should be in range (0, 1], got %v", p.Reversion)
+		}
+	default:
+		return fmt.Errorf("unknown data model: %s", p.Model)
+	}
+	if p.JumpRate < 0 || p.JumpRate > 1 {
+		return fmt.Errorf("jump rate should be in range [0, 1], got %v", p.JumpRate)
+	}
+	if p.JumpSize < 0 {
+		return fmt.Errorf("negative jump size")
+	}
+	return nil
+}
+
+// ModelDataGenerator generates the path of data points with the gbm or the mean reversion model in the log space of
+// the data points, while the uniform model is generated by RandDataGenerator. The reference point could be tuned
+// during running by calling MoveTo or MoveBy interface.
+type ModelDataGenerator struct {
+	params         ModelParams
+	referencePoint decimal.Decimal // the starting point of gbm, or the mean of the mean reversion.
+	volatility     float64         // the standard deviation of the log return of each data point.
+	last           float64         // the log value of the last data point.
+	rng            *rand.Rand
+}
+
+func NewModelDataGenerator(ref decimal.Decimal, volatility decimal.Decimal, params ModelParams,
+	seed int64) *ModelDataGenerator {
+	mg := &ModelDataGenerator{
+		params: params,
+		rng:    rand.New(rand.NewSource(seed)), // nolint
+	}
+	mg.SetDistributionRate(volatility)
+	mg.MoveTo(ref)
+	return mg
+}
+
+func (mg *ModelDataGenerator) SetDistributionRate(rate decimal.Decimal) {
+	mg.volatility = rate.InexactFloat64()
+}
+
+// MoveTo restarts the path from the target.
+func (mg *ModelDataGenerator) MoveTo(target decimal.Decimal) {
+	mg.referencePoint = target
+	mg.last = math.Log(target.InexactFloat64())
+}
+
+// MoveBy move the path by a percentage which could be negative as well.
+func (mg *ModelDataGenerator) MoveBy(percentage decimal.Decimal) {
+	mg.referencePoint = mg.referencePoint.Add(mg.referencePoint.Mul(percentage))
+	mg.last += math.Log1p(percentage.InexactFloat64())
+}
+
+func (mg *ModelDataGenerator) NextDataPoint() decimal.Decimal {
+	sigma := mg.volatility
+	if mg.params.Model == ModelMeanReversion {
+		mean := math.Log(mg.referencePoint.InexactFloat64())
+		mg.last += mg.params.Reversion*(mean-mg.last) + sigma*mg.rng.NormFloat64()
+	} else {
+		mg.last += mg.params.Drift - sigma*sigma/2 + sigma*mg.rng.NormFloat64()
+	}
+
+	if mg.params.JumpRate > 0 && mg.rng.Float64() < mg.params.JumpRate {
+		mg.last += mg.params.JumpSize * mg.rng.NormFloat64()
+	}
+	return decimal.NewFromFloat(math.Exp(mg.last))
+}
@@ -0,0 +1,44 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelDataGenerator_NextDataPoint(t *testing.T) {
+	path := func(params ModelParams, seed int64) []decimal.Decimal {
+		gen := NewModelDataGenerator(decimal.NewFromInt(100), decimal.RequireFromString("0.01"), params, seed)
+		var points []decimal.Decimal
+		for i := 0; i < 1000; i++ {
+			points = append(points, gen.NextDataPoint())
+		}
+		return points
+	}
+
+	// the same seed replays the same path.
+	gbm := ModelParams{Model: ModelGBM, JumpRate: 0.01, JumpSize: 0.1}
+	require.Equal(t, path(gbm, 1), path(gbm, 1))
+	require.NotEqual(t, path(gbm, 1), path(gbm, 2))
+
+	// the mean reversion keeps the path around the reference point.
+	for _, p := range path(ModelParams{Model: ModelMeanReversion, Reversion: 0.5}, 1) {
+		require.True(t, p.GreaterThan(decimal.NewFromInt(90)) && p.LessThan(decimal.NewFromInt(110)), p.String())
+	}
+
+	// the drift moves the path.
+	points := path(ModelParams{Model: ModelGBM, Drift: 0.01}, 1)
+	require.True(t, points[len(points)-1].GreaterThan(decimal.NewFromInt(1000)))
+
+	gen := NewModelDataGenerator(decimal.NewFromInt(100), decimal.Zero, ModelParams{Model: ModelGBM}, 1)
+	gen.MoveBy(decimal.RequireFromString("0.5"))
+	require.Equal(t, "150", gen.NextDataPoint().Round(8).String())
+	gen.MoveTo(decimal.NewFromInt(7))
+	require.Equal(t, "7", gen.NextDataPoint().Round(8).String())
+
+	require.NoError(t, ValidateModel(gbm))
+	require.Error(t, ValidateModel(ModelParams{Model: ModelMeanReversion}))
+	require.Error(t, ValidateModel(ModelParams{Model: "cauchy"}))
+	require.Error(t, ValidateModel(ModelParams{Model: ModelGBM, JumpRate: 2}))
+}
@@ -2,21 +2,31 @@ package generators
 
 import (
 	"github.com/shopspring/decimal"
+	"hash/fnv"
 	"math/rand"
-	"time"
 )
 
+// SymbolSeed derives the seed of a symbol's generator from the seed of a simulation, thus the data points of a symbol
+// are reproducible regardless of the order that the generators are called in.
+func SymbolSeed(seed int64, symbol string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(symbol)) // nolint
+	return seed ^ int64(h.Sum64())
+}
+
 // RandDataGenerator start to generate data point from a reference with data point drifting under a percentage range,
 // the reference point could be tuned during running by calling MoveTo or MoveBy interface.
 type RandDataGenerator struct {
 	referencePoint decimal.Decimal // the reference data point for the data generation within the percentage range.
 	distRateRange  decimal.Decimal // the data distribution percentage range base on the reference data point.
+	rng            *rand.Rand
 }
 
-func NewRandDataGenerator(ref decimal.Decimal, rateRange decimal.Decimal) *RandDataGenerator {
+func NewRandDataGenerator(ref decimal.Decimal, rateRange decimal.Decimal, seed int64) *RandDataGenerator {
 	return &RandDataGenerator{
 		referencePoint: ref,
 		distRateRange:  rateRange,
+		rng:            rand.New(rand.NewSource(seed)), // nolint
 	}
 }
 
@@ -35,10 +45,9 @@ func (rg *RandDataGenerator) MoveBy(percentage decimal.Decimal) {
 }
 
 func (rg *RandDataGenerator) NextDataPoint() decimal.Decimal {
-	rand.Seed(time.Now().UnixNano())
-	per := rg.distRateRange.Mul(decimal.NewFromFloat(rand.Float64())) // nolint
+	per := rg.distRateRange.Mul(decimal.NewFromFloat(rg.rng.Float64()))
 	delta := rg.referencePoint.Mul(per)
-	if rand.Int()%2 == 0 { // nolint
+	if rg.rng.Int()%2 == 0 {
 		return delta.Add(rg.referencePoint)
 	}
 	return rg.referencePoint.Sub(delta)
@@ -2,12 +2,13 @@ package generators
 
 import (
 	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
 func TestRandDataGenerator_NextDataPoint(t *testing.T) {
 	referPoint := decimal.RequireFromString("7.0")
-	gen := NewRandDataGenerator(referPoint, decimal.RequireFromString("0.01"))
+	gen := NewRandDataGenerator(referPoint, decimal.RequireFromString("0.01"), 1)
 
 	for i := 0; i < 100; i++ {
 		println(gen.NextDataPoint().String())
@@ -36,3 +37,16 @@ func TestRandDataGenerator_NextDataPoint(t *testing.T) {
 		println(gen.NextDataPoint().String())
 	}
 }
+
+func TestRandDataGenerator_Seed(t *testing.T) {
+	path := func(seed int64) []string {
+		gen := NewRandDataGenerator(decimal.RequireFromString("7.0"), decimal.RequireFromString("0.01"), seed)
+		var points []string
+		for i := 0; i < 100; i++ {
+			points = append(points, gen.NextDataPoint().String())
+		}
+		return points
+	}
+	require.Equal(t, path(SymbolSeed(1, "NTN-USDC")), path(SymbolSeed(1, "NTN-USDC")))
+	require.NotEqual(t, path(SymbolSeed(1, "NTN-USDC")), path(SymbolSeed(1, "ATN-USDC")))
+}
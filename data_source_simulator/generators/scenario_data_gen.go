@@ -0,0 +1,212 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// The scripted actions on the price path of a symbol.
+const (
+	ActionJump   = "jump"   // move the price by the rate of value at once.
+	ActionDrift  = "drift"  // move the price by the rate of value gradually over the duration.
+	ActionCrash  = "crash"  // drop the price by the rate of value at once, and recover it gradually over the duration.
+	ActionFreeze = "freeze" // hold the price for the duration while the path keeps moving underneath.
+	ActionStale  = "stale"  // serve the price of value seconds ago for the duration.
+)
+
+// staleHistory is the least range of the data points kept for the stale steps scheduled during running.
+const staleHistory = 5 * time.Minute
+
+var one = decimal.NewFromInt(1)
+
+// Step is a timed action of a scenario, the time is relative to the start of the scenario.
+type Step struct {
+	At       time.Duration
+	Action   string
+	Value    float64
+	Duration time.Duration
+
+	factor decimal.Decimal // the factor applied on the reference point by this step so far.
+}
+
+// ValidateStep checks if the action of the step is one of the price path actions.
+func ValidateStep(step Step) error {
+	switch step.Action {
+	case ActionJump, ActionDrift, ActionCrash, ActionFreeze:
+	case ActionStale:
+		if step.Value <= 0 {
+			return fmt.Errorf("stale step requires a positive lag in value")
+		}
+	default:
+		return fmt.Errorf("unknown scenario action: %s", step.Action)
+	}
+	if step.At < 0 || step.Duration < 0 {
+		return fmt.Errorf("negative time of %s step", step.Action)
+	}
+	return nil
+}
+
+type dataPoint struct {
+	at    time.Duration
+	value decimal.Decimal
+}
+
+// ScenarioDataGenerator generates data points along a scripted price path, the data points are distributed around
+// the path by a RandDataGenerator. The steps take effect by the time elapsed since the generator is created, thus the
+// path can be tuned during running by scheduling more steps or by calling MoveTo or MoveBy interface.
+type ScenarioDataGenerator struct {
+	noise   *RandDataGenerator
+	ref     decimal.Decimal // the reference data point of the path.
+	steps   []*Step
+	history []dataPoint // the generated data points kept for the stale steps.
+	last    *dataPoint
+	clock   func() time.Time
+	start   time.Time
+}
+
+func NewScenarioDataGenerator(ref decimal.Decimal, rateRange decimal.Decimal, steps []Step,
+	clock func() time.Time, seed int64) *ScenarioDataGenerator {
+	if clock == nil {
+		clock = time.Now
+	}
+	sg := &ScenarioDataGenerator{
+		noise: NewRandDataGenerator(ref, rateRange, seed),
+		ref:   ref,
+		clock: clock,
+		start: clock(),
+	}
+	for _, s := range steps {
+		sg.Schedule(s)
+	}
+	return sg
+}
+
+// Elapsed returns the time elapsed since the start of the scenario.
+func (sg *ScenarioDataGenerator) Elapsed() time.Duration {
+	return sg.clock().Sub(sg.start)
+}
+
+// Schedule adds a step into the scenario, a step scheduled in the past takes effect on the next data point.
+func (sg *ScenarioDataGenerator) Schedule(step Step) {
+	s := step
+	s.factor = one
+	sg.steps = append(sg.steps, &s)
+	sort.SliceStable(sg.steps, func(i, j int) bool {
+		return sg.steps[i].At < sg.steps[j].At
+	})
+}
+
+func (sg *ScenarioDataGenerator) SetDistributionRate(rate decimal.Decimal) {
+	sg.noise.SetDistributionRate(rate)
+}
+
+func (sg *ScenarioDataGenerator) MoveTo(target decimal.Decimal) {
+	sg.ref = target
+}
+
+// MoveBy move to a new target by a percentage which could be negative as well.
+func (sg *ScenarioDataGenerator) MoveBy(percentage decimal.Decimal) {
+	sg.ref = sg.ref.Add(sg.ref.Mul(percentage))
+}
+
+func (sg *ScenarioDataGenerator) NextDataPoint() decimal.Decimal {
+	now := sg.Elapsed()
+
+	var frozen bool
+	var lag time.Duration
+	remaining := sg.steps[:0]
+	for _, s := range sg.steps {
+		if now < s.At {
+			remaining = append(remaining, s)
+			continue
+		}
+		progress := one
+		if s.Duration > 0 && now < s.At+s.Duration {
+			progress = decimal.NewFromInt(int64(now - s.At)).Div(decimal.NewFromInt(int64(s.Duration)))
+		}
+		rate := decimal.NewFromFloat(s.Value)
+
+		switch s.Action {
+		case ActionJump:
+			sg.applyFactor(s, one.Add(rate))
+		case ActionDrift:
+			sg.applyFactor(s, one.Add(rate.Mul(progress)))
+		case ActionCrash:
+			sg.applyFactor(s, one.Add(rate.Mul(one.Sub(progress))))
+		case ActionFreeze:
+			frozen = progress.LessThan(one)
+		case ActionStale:
+			if progress.LessThan(one) {
+				lag = time.Duration(s.Value * float64(time.Second))
+			}
+		}
+
+		if progress.LessThan(one) {
+			remaining = append(remaining, s)
+		}
+	}
+	sg.steps = remaining
+
+	sg.noise.MoveTo(sg.ref)
+	point := dataPoint{at: now, value: sg.noise.NextDataPoint()}
+	switch {
+	case frozen && sg.last != nil:
+		point.value = sg.last.value
+	case lag > 0:
+		point.value = sg.valueAt(now - lag)
+	}
+
+	sg.record(dataPoint{at: now, value: point.value})
+	return point.value
+}
+
+// applyFactor replaces the factor applied by the step with the new one on the reference point.
+func (sg *ScenarioDataGenerator) applyFactor(s *Step, factor decimal.Decimal) {
+	if s.factor.IsZero() || factor.IsZero() {
+		// a rate of -100% wipes out the price, it cannot be recovered by factors.
+		sg.ref = sg.ref.Mul(factor)
+		s.factor = factor
+		return
+	}
+	sg.ref = sg.ref.Div(s.factor).Mul(factor)
+	s.factor = factor
+}
+
+// valueAt returns the latest data point generated at or before the time.
+func (sg *ScenarioDataGenerator) valueAt(at time.Duration) decimal.Decimal {
+	value := sg.ref
+	if len(sg.history) > 0 {
+		value = sg.history[0].value
+	}
+	for _, p := range sg.history {
+		if p.at > at {
+			break
+		}
+		value = p.value
+	}
+	return value
+}
+
+// record keeps the data points in the range of the longest scheduled lag.
+func (sg *ScenarioDataGenerator) record(point dataPoint) {
+	sg.last = &point
+
+	maxLag := staleHistory
+	for _, s := range sg.steps {
+		if s.Action == ActionStale {
+			if l := time.Duration(s.Value * float64(time.Second)); l > maxLag {
+				maxLag = l
+			}
+		}
+	}
+	sg.history = append(sg.history, point)
+	// keep one point before the range, it is the value at the start of the range.
+	i := 0
+	for i+1 < len(sg.history) && sg.history[i+1].at <= point.at-maxLag {
+		i++
+	}
+	sg.history = sg.history[i:]
+}
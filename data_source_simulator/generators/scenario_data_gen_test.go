@@ -0,0 +1,76 @@
+package generators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestScenarioDataGenerator_NextDataPoint(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	steps := []Step{
+		{At: 10 * time.Second, Action: ActionJump, Value: 0.1},
+		{At: 20 * time.Second, Action: ActionDrift, Value: -0.5, Duration: 10 * time.Second},
+		{At: 40 * time.Second, Action: ActionCrash, Value: -0.5, Duration: 10 * time.Second},
+		{At: 60 * time.Second, Action: ActionFreeze, Duration: 10 * time.Second},
+		{At: 80 * time.Second, Action: ActionStale, Value: 5, Duration: 10 * time.Second},
+	}
+	gen := NewScenarioDataGenerator(decimal.NewFromInt(100), decimal.Zero, steps, clock.Now, 1)
+
+	next := func(d time.Duration) string {
+		clock.Advance(d)
+		return gen.NextDataPoint().String()
+	}
+
+	require.Equal(t, "100", next(0))
+	require.Equal(t, "110", next(10*time.Second))
+	// the drift moves the price gradually, and it stays at the end.
+	require.Equal(t, "82.5", next(15*time.Second))
+	require.Equal(t, "55", next(5*time.Second))
+	require.Equal(t, "55", next(5*time.Second))
+	// the crash drops the price at once, and recovers it.
+	require.Equal(t, "27.5", next(5*time.Second))
+	require.Equal(t, "41.25", next(5*time.Second))
+	require.Equal(t, "55", next(5*time.Second))
+
+	// the price is held during the freeze while the path keeps moving underneath.
+	gen.MoveTo(decimal.NewFromInt(50))
+	require.Equal(t, "50", next(9*time.Second))
+	gen.MoveTo(decimal.NewFromInt(60))
+	require.Equal(t, "50", next(5*time.Second))
+	require.Equal(t, "60", next(6*time.Second))
+
+	// the stale feed lags behind the path.
+	clock.Advance(5 * time.Second)
+	for _, p := range []int64{70, 80, 90} {
+		gen.MoveTo(decimal.NewFromInt(p))
+		next(time.Second)
+	}
+	require.Equal(t, "70", next(3*time.Second))
+	require.Equal(t, "90", next(10*time.Second))
+
+	// the steps scheduled during running take effect on the next data point.
+	gen.Schedule(Step{At: gen.Elapsed(), Action: ActionJump, Value: 1})
+	require.Equal(t, "180", next(time.Second))
+}
+
+func TestValidateStep(t *testing.T) {
+	require.NoError(t, ValidateStep(Step{Action: ActionDrift, Value: 0.1, Duration: time.Second}))
+	require.Error(t, ValidateStep(Step{Action: "pump"}))
+	require.Error(t, ValidateStep(Step{Action: ActionStale}))
+	require.Error(t, ValidateStep(Step{Action: ActionJump, At: -time.Second}))
+}
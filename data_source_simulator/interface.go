@@ -18,3 +18,8 @@ type GeneratorManager interface {
 	GetSymbolPrice([]string) (types.Prices, error)
 	AdjustParams(params types.GeneratorParams, method string) error
 }
+
+// FaultInjector is implemented by the generator managers which script the faults of the data feed.
+type FaultInjector interface {
+	InjectFault() types.Fault
+}
@@ -58,6 +58,7 @@ var ErrZeroKey = errors.New("generated secret key is zero")
 type DataSimulator struct {
 	Command    *exec.Cmd
 	SimulateTM int
+	Seed       int64
 }
 
 func (s *DataSimulator) Start() {
@@ -75,7 +76,11 @@ func (s *DataSimulator) Stop() {
 }
 
 func (s *DataSimulator) GenCMD() {
-	c := exec.Command("./simulator", fmt.Sprintf("-sim_timeout=%d", s.SimulateTM))
+	args := []string{fmt.Sprintf("-sim_timeout=%d", s.SimulateTM)}
+	if s.Seed != 0 {
+		args = append(args, fmt.Sprintf("-sim_seed=%d", s.Seed))
+	}
+	c := exec.Command("./simulator", args...)
 	c.Stderr = os.Stderr
 	c.Stdout = os.Stdout
 	s.Command = c
@@ -295,7 +300,8 @@ type NetworkConfig struct {
 	VotePeriod      uint64
 	PluginDIRs      []string // different oracle can have different plugins configured.
 	MetricConfigs   []config.MetricConfig
-	SimulateTimeout int // to simulate timeout in seconds at data source simulator when processing http request.
+	SimulateTimeout int   // to simulate timeout in seconds at data source simulator when processing http request.
+	SimulatorSeed   int64 // to replay the data points of a failed run with the seed printed by the simulator.
 	EpochPeriod     uint64
 }
 
@@ -420,7 +426,7 @@ func createNetwork(netConf *NetworkConfig, numOfValidators int) (*Network, error
 		if len(d) != 0 {
 			pluginDIRs[i] = d
 			if (d == simulatorPlugDir || d == mixPluginDir) && simulator == nil {
-				simulator = &DataSimulator{SimulateTM: netConf.SimulateTimeout}
+				simulator = &DataSimulator{SimulateTM: netConf.SimulateTimeout, Seed: netConf.SimulatorSeed}
 			}
 		}
 	}
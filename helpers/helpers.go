@@ -136,6 +136,31 @@ func VWAP(prices []decimal.Decimal, volumes []*big.Int) (decimal.Decimal, *big.I
 	return vwap, highestVol, nil
 }
 
+// TWAP computes the time weighted average price for the input prices with the durations in seconds that each of the
+// prices was held for.
+func TWAP(prices []decimal.Decimal, durations []int64) (decimal.Decimal, error) {
+	if len(prices) == 0 || len(durations) == 0 || len(prices) != len(durations) {
+		return decimal.Zero, errors.New("prices and durations must be of the same non-zero length")
+	}
+
+	var totalWeightedPrice decimal.Decimal
+	var totalDuration int64
+	for i := range prices {
+		if durations[i] < 0 {
+			return decimal.Zero, errors.New("duration cannot be negative")
+		}
+		totalWeightedPrice = totalWeightedPrice.Add(prices[i].Mul(decimal.NewFromInt(durations[i])))
+		totalDuration += durations[i]
+	}
+
+	// Avoid division by zero
+	if totalDuration == 0 {
+		return decimal.Zero, errors.New("total duration cannot be zero")
+	}
+
+	return totalWeightedPrice.Div(decimal.NewFromInt(totalDuration)), nil
+}
+
 // ListPlugins returns a mapping of file names to fs.FileInfo for executable files in the specified path.
 func ListPlugins(path string) (map[string]fs.FileInfo, error) {
 	plugins := make(map[string]fs.FileInfo)
@@ -153,3 +153,35 @@ func TestVWAP(t *testing.T) {
 		}
 	}
 }
+
+func TestTWAP(t *testing.T) {
+	t.Run("normal cases", func(t *testing.T) {
+		prices := []decimal.Decimal{
+			decimal.NewFromFloat(100.0),
+			decimal.NewFromFloat(200.0),
+			decimal.NewFromFloat(400.0),
+		}
+		twap, err := TWAP(prices, []int64{2, 1, 1}) // (100*2 + 200*1 + 400*1) / (2 + 1 + 1)
+		require.NoError(t, err)
+		require.Equal(t, true, decimal.NewFromInt(200).Equal(twap))
+
+		// a price held for zero duration does not count.
+		twap, err = TWAP(prices, []int64{1, 0, 1})
+		require.NoError(t, err)
+		require.Equal(t, true, decimal.NewFromInt(250).Equal(twap))
+	})
+
+	t.Run("invalid inputs", func(t *testing.T) {
+		_, err := TWAP(nil, nil)
+		require.Error(t, err)
+
+		_, err = TWAP([]decimal.Decimal{decimal.NewFromInt(1)}, []int64{1, 2})
+		require.Error(t, err)
+
+		_, err = TWAP([]decimal.Decimal{decimal.NewFromInt(1)}, []int64{0})
+		require.Error(t, err)
+
+		_, err = TWAP([]decimal.Decimal{decimal.NewFromInt(1)}, []int64{-1})
+		require.Error(t, err)
+	})
+}
@@ -6,11 +6,15 @@ import (
 	"autonity-oracle/monitor"
 	"autonity-oracle/server"
 	"autonity-oracle/types"
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/metrics/exp"
@@ -33,6 +37,20 @@ func main() { //nolint
 		exp.Setup(address)
 	}
 
+	// start the native prometheus exposition with labels if it is enabled.
+	if conf.MetricConfigs.EnablePrometheus {
+		metrics.Enabled = true
+		address := fmt.Sprintf("%s:%d", conf.MetricConfigs.HTTP, conf.MetricConfigs.Port)
+		log.Printf("Native prometheus metrics enabled at: http://%s%s", address, conf.MetricConfigs.PrometheusPath)
+		mux := http.NewServeMux()
+		mux.Handle(conf.MetricConfigs.PrometheusPath, monitor.EnablePrometheus().Handler())
+		go func() {
+			if err := http.ListenAndServe(address, mux); err != nil { //nolint
+				log.Printf("Failure in running native prometheus metrics server: %s", err.Error())
+			}
+		}()
+	}
+
 	// start influxDB metrics reporter if it is enabled.
 	tagsMap := config.SplitTagsFlag(conf.MetricConfigs.InfluxDBTags)
 	if conf.MetricConfigs.EnableInfluxDB {
@@ -63,6 +81,27 @@ func main() { //nolint
 		go metrics.CollectProcessMetrics(config.MetricsInterval)
 	}
 
+	// start the tracing of the rounds if it is enabled.
+	if conf.Tracing.Enabled {
+		file := conf.Tracing.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(conf.ProfileDir, file)
+		}
+		shutdown, err := monitor.EnableTracing(conf.Tracing, file, config.VersionString(config.Version))
+		if err != nil {
+			log.Printf("cannot enable tracing: %s", err.Error())
+			os.Exit(1)
+		}
+		log.Printf("Tracing of rounds enabled with exporter: %s", conf.Tracing.Exporter)
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdown(ctx); err != nil {
+				log.Printf("Failure in flushing traces: %s", err.Error())
+			}
+		}()
+	}
+
 	// create metrics before the context of the usage.
 	monitor.InitOracleMetrics()
 
@@ -1,6 +1,11 @@
 package monitor
 
-import "github.com/ethereum/go-ethereum/metrics"
+import (
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
 
 var (
 	PluginMetric         = "oracle/plugins"
@@ -16,8 +21,58 @@ var (
 	OutlierNoSlashTimesMetric    = "oracle/outlier/noslash/times"
 	OutlierSlashTimesMetric      = "oracle/outlier/slash/times"
 	OutlierPenaltyMetric         = "oracle/outlier/penality"
+
+	VoteLatencyMetric     = "oracle/vote/latency" // milliseconds from the new round event to the vote tx submission.
+	VoteMinedBlocksMetric = "oracle/vote/blocks"  // blocks from the round start until the vote tx is mined.
+	VoteFeeMetric         = "oracle/vote/fee"     // the fee in wei spent by the last mined vote tx.
 )
 
+// PluginFetchLatencyMetric is the histogram of the milliseconds that the plugin took to return the prices.
+func PluginFetchLatencyMetric(plugin string) string {
+	return strings.Join([]string{"oracle", plugin, "fetch", "latency"}, "/")
+}
+
+// PluginFetchErrorsMetric is the counter of the price fetching that the plugin returned with an error.
+func PluginFetchErrorsMetric(plugin string) string {
+	return strings.Join([]string{"oracle", plugin, "fetch", "errors"}, "/")
+}
+
+// PluginFetchTimeoutsMetric is the counter of the price fetching that the plugin did not return before the deadline.
+func PluginFetchTimeoutsMetric(plugin string) string {
+	return strings.Join([]string{"oracle", plugin, "fetch", "timeouts"}, "/")
+}
+
+// PluginFetchSkippedMetric is the counter of the price fetching skipped as the last one of the plugin was on-going.
+func PluginFetchSkippedMetric(plugin string) string {
+	return strings.Join([]string{"oracle", plugin, "fetch", "skipped"}, "/")
+}
+
+// SymbolSamplesMetric is the number of plugins' samples aggregated into the symbol's price of the last round.
+func SymbolSamplesMetric(symbol string) string {
+	return strings.Join([]string{"oracle", symbol, "samples"}, "/")
+}
+
+// SymbolReportPriceMetric is the price of the symbol reported in the last round.
+func SymbolReportPriceMetric(symbol string) string {
+	return strings.Join([]string{"oracle", symbol, "report", "price"}, "/")
+}
+
+// SymbolReportConfidenceMetric is the confidence of the symbol reported in the last round.
+func SymbolReportConfidenceMetric(symbol string) string {
+	return strings.Join([]string{"oracle", symbol, "report", "confidence"}, "/")
+}
+
+// SymbolDeviationPercentMetric is the deviation in percentage of the symbol's report from the on-chain median of the
+// last closed round.
+func SymbolDeviationPercentMetric(symbol string) string {
+	return strings.Join([]string{"oracle", symbol, "deviation", "percentage"}, "/")
+}
+
+// GetOrRegisterHistogram returns the histogram of the name with an exponentially decaying sample of the recent values.
+func GetOrRegisterHistogram(name string) metrics.Histogram {
+	return metrics.GetOrRegisterHistogram(name, nil, metrics.NewExpDecaySample(1028, 0.015))
+}
+
 func InitOracleMetrics() {
 	if metrics.Enabled {
 		metrics.GetOrRegisterGauge(PluginMetric, nil)
@@ -35,5 +90,112 @@ func InitOracleMetrics() {
 		metrics.GetOrRegisterCounter(OutlierNoSlashTimesMetric, nil)
 		metrics.GetOrRegisterCounter(OutlierSlashTimesMetric, nil)
 		metrics.GetOrRegisterGaugeFloat64(OutlierPenaltyMetric, nil)
+
+		// create metrics for the vote transactions in advance.
+		GetOrRegisterHistogram(VoteLatencyMetric)
+		GetOrRegisterHistogram(VoteMinedBlocksMetric)
+		metrics.GetOrRegisterGaugeFloat64(VoteFeeMetric, nil)
+	}
+}
+
+// The per plugin and the per symbol metrics below are labeled in the native prometheus registry once it is enabled, and
+// they are always named after the plugin or the symbol in the metrics registry of go-ethereum, thus they are still
+// exported to InfluxDB along with the native prometheus exposition.
+
+// PluginPriceMetric is the price of the symbol sampled from the plugin.
+func PluginPriceMetric(plugin, symbol string) string {
+	return strings.Join([]string{"oracle", plugin, symbol, "price"}, "/")
+}
+
+// UpdatePluginPrice tracks the price of the symbol sampled from the plugin.
+func UpdatePluginPrice(plugin, source, symbol string, price float64) {
+	if Prometheus != nil {
+		Prometheus.PluginPrice.WithLabelValues(plugin, symbol, source).Set(price)
+	}
+	metrics.GetOrRegisterGaugeFloat64(PluginPriceMetric(plugin, symbol), nil).Update(price)
+}
+
+// ObservePluginFetch tracks the latency of a price fetching of the plugin, and counts it if it returned an error.
+func ObservePluginFetch(plugin, source string, latency time.Duration, err error) {
+	if Prometheus != nil {
+		Prometheus.FetchLatency.WithLabelValues(plugin, source).Observe(latency.Seconds())
+		if err != nil {
+			Prometheus.FetchErrors.WithLabelValues(plugin, source).Inc()
+		}
+	}
+	GetOrRegisterHistogram(PluginFetchLatencyMetric(plugin)).Update(latency.Milliseconds())
+	if err != nil {
+		metrics.GetOrRegisterCounter(PluginFetchErrorsMetric(plugin), nil).Inc(1)
+	}
+}
+
+// CountPluginFetchTimeout counts the price fetching of the plugin that was not returned before the deadline.
+func CountPluginFetchTimeout(plugin, source string) {
+	if Prometheus != nil {
+		Prometheus.FetchTimeouts.WithLabelValues(plugin, source).Inc()
+	}
+	metrics.GetOrRegisterCounter(PluginFetchTimeoutsMetric(plugin), nil).Inc(1)
+}
+
+// CountPluginFetchSkipped counts the price fetching of the plugin skipped as the last one was on-going.
+func CountPluginFetchSkipped(plugin, source string) {
+	if Prometheus != nil {
+		Prometheus.FetchSkipped.WithLabelValues(plugin, source).Inc()
+	}
+	metrics.GetOrRegisterCounter(PluginFetchSkippedMetric(plugin), nil).Inc(1)
+}
+
+// RemovePluginMetrics removes the metrics of the plugin once it is unloaded, thus the series of the removed plugins
+// are not exported anymore.
+func RemovePluginMetrics(plugin string) {
+	if Prometheus != nil {
+		Prometheus.deletePlugin(plugin)
+	}
+	prefix := strings.Join([]string{"oracle", plugin, ""}, "/")
+	var names []string
+	metrics.DefaultRegistry.Each(func(name string, _ interface{}) {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	})
+	for _, name := range names {
+		metrics.Unregister(name)
+	}
+}
+
+// UpdateSymbolSamples tracks the number of plugins' samples aggregated into the symbol's price.
+func UpdateSymbolSamples(symbol string, samples int) {
+	if Prometheus != nil {
+		Prometheus.SymbolSamples.WithLabelValues(symbol).Set(float64(samples))
+	}
+	metrics.GetOrRegisterGauge(SymbolSamplesMetric(symbol), nil).Update(int64(samples))
+}
+
+// UpdateSymbolReport tracks the price and the confidence of the symbol reported in the round.
+func UpdateSymbolReport(symbol string, price float64, confidence uint8) {
+	if Prometheus != nil {
+		Prometheus.ReportPrice.WithLabelValues(symbol).Set(price)
+		Prometheus.ReportConfidence.WithLabelValues(symbol).Set(float64(confidence))
+	}
+	metrics.GetOrRegisterGaugeFloat64(SymbolReportPriceMetric(symbol), nil).Update(price)
+	metrics.GetOrRegisterGauge(SymbolReportConfidenceMetric(symbol), nil).Update(int64(confidence))
+}
+
+// UpdateSymbolDeviation tracks the deviation in percentage of the symbol's report from the on-chain median.
+func UpdateSymbolDeviation(symbol string, percent float64) {
+	if Prometheus != nil {
+		Prometheus.DeviationPercent.WithLabelValues(symbol).Set(percent)
+	}
+	metrics.GetOrRegisterGaugeFloat64(SymbolDeviationPercentMetric(symbol), nil).Update(percent)
+}
+
+// RemoveSymbolMetrics removes the metrics of the symbol once it is no longer sampled.
+func RemoveSymbolMetrics(symbol string) {
+	if Prometheus != nil {
+		Prometheus.deleteSymbol(symbol)
 	}
+	metrics.Unregister(SymbolSamplesMetric(symbol))
+	metrics.Unregister(SymbolReportPriceMetric(symbol))
+	metrics.Unregister(SymbolReportConfidenceMetric(symbol))
+	metrics.Unregister(SymbolDeviationPercentMetric(symbol))
 }
@@ -0,0 +1,168 @@
+package monitor
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The label dimensions of the native prometheus metrics.
+const (
+	LabelPlugin = "plugin"
+	LabelSymbol = "symbol"
+	LabelSource = "source"
+)
+
+// Prometheus is the registry of the native prometheus metrics, it is nil unless the native exposition is enabled.
+var Prometheus *PromMetrics
+
+// PromMetrics keeps the per plugin and the per symbol metrics with labels in a prometheus registry, rather than one
+// series per slash joined name in the metrics registry of go-ethereum. The server level metrics are bridged from the
+// registry of go-ethereum, thus they are kept updated by the same call sites.
+type PromMetrics struct {
+	registry *prometheus.Registry
+
+	PluginPrice   *prometheus.GaugeVec     // plugin, symbol, source
+	FetchLatency  *prometheus.HistogramVec // plugin, source
+	FetchErrors   *prometheus.CounterVec   // plugin, source
+	FetchTimeouts *prometheus.CounterVec   // plugin, source
+	FetchSkipped  *prometheus.CounterVec   // plugin, source
+
+	SymbolSamples    *prometheus.GaugeVec // symbol
+	ReportPrice      *prometheus.GaugeVec // symbol
+	ReportConfidence *prometheus.GaugeVec // symbol
+	DeviationPercent *prometheus.GaugeVec // symbol
+}
+
+// EnablePrometheus creates the native prometheus registry.
+func EnablePrometheus() *PromMetrics {
+	pluginLabels := []string{LabelPlugin, LabelSource}
+	symbolLabels := []string{LabelSymbol}
+	m := &PromMetrics{
+		registry: prometheus.NewRegistry(),
+		PluginPrice: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "oracle", Subsystem: "plugin", Name: "price",
+			Help: "The last price of the symbol sampled from the plugin.",
+		}, []string{LabelPlugin, LabelSymbol, LabelSource}),
+		FetchLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "oracle", Subsystem: "plugin", Name: "fetch_latency_seconds",
+			Help:    "The time that the plugin took to return the prices.",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, pluginLabels),
+		FetchErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "oracle", Subsystem: "plugin", Name: "fetch_errors_total",
+			Help: "The price fetching returned with an error by the plugin.",
+		}, pluginLabels),
+		FetchTimeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "oracle", Subsystem: "plugin", Name: "fetch_timeouts_total",
+			Help: "The price fetching not returned before the plugin's timeout.",
+		}, pluginLabels),
+		FetchSkipped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "oracle", Subsystem: "plugin", Name: "fetch_skipped_total",
+			Help: "The price fetching skipped as the last one of the plugin was on-going.",
+		}, pluginLabels),
+		SymbolSamples: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "oracle", Subsystem: "symbol", Name: "samples",
+			Help: "The number of plugins' samples aggregated into the symbol's price of the last round.",
+		}, symbolLabels),
+		ReportPrice: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "oracle", Subsystem: "report", Name: "price",
+			Help: "The price of the symbol reported in the last round.",
+		}, symbolLabels),
+		ReportConfidence: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "oracle", Subsystem: "report", Name: "confidence",
+			Help: "The confidence of the symbol reported in the last round.",
+		}, symbolLabels),
+		DeviationPercent: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "oracle", Subsystem: "report", Name: "deviation_percentage",
+			Help: "The deviation in percentage of the report from the on-chain median of the last closed round.",
+		}, symbolLabels),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.PluginPrice, m.FetchLatency, m.FetchErrors, m.FetchTimeouts, m.FetchSkipped,
+		m.SymbolSamples, m.ReportPrice, m.ReportConfidence, m.DeviationPercent,
+		&gethCollector{names: serverMetrics()},
+	)
+	Prometheus = m
+	return m
+}
+
+// Handler serves the metrics of the registry in the prometheus exposition format.
+func (m *PromMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// deletePlugin removes the series of the plugin once it is unloaded.
+func (m *PromMetrics) deletePlugin(plugin string) {
+	labels := prometheus.Labels{LabelPlugin: plugin}
+	m.PluginPrice.DeletePartialMatch(labels)
+	m.FetchLatency.DeletePartialMatch(labels)
+	m.FetchErrors.DeletePartialMatch(labels)
+	m.FetchTimeouts.DeletePartialMatch(labels)
+	m.FetchSkipped.DeletePartialMatch(labels)
+}
+
+// deleteSymbol removes the series of the symbol once it is no longer sampled.
+func (m *PromMetrics) deleteSymbol(symbol string) {
+	labels := prometheus.Labels{LabelSymbol: symbol}
+	m.SymbolSamples.DeletePartialMatch(labels)
+	m.ReportPrice.DeletePartialMatch(labels)
+	m.ReportConfidence.DeletePartialMatch(labels)
+	m.DeviationPercent.DeletePartialMatch(labels)
+}
+
+// serverMetrics are the server level metrics bridged from the registry of go-ethereum.
+func serverMetrics() []string {
+	return []string{
+		PluginMetric, RoundMetric, BalanceMetric, IsVoterMetric, L1ConnectivityMetric, InvalidVoteMetric,
+		NoRevealVoteMetric, SuccessfulVoteMetric, OutlierDistancePercentMetric, OutlierNoSlashTimesMetric,
+		OutlierSlashTimesMetric, OutlierPenaltyMetric, VoteLatencyMetric, VoteMinedBlocksMetric, VoteFeeMetric,
+	}
+}
+
+// gethCollector exports the metrics of the go-ethereum registry on collection, the slashes of the names are replaced
+// with underscores, e.g. oracle/vote/latency is exported as oracle_vote_latency.
+type gethCollector struct {
+	names []string
+}
+
+// Describe sends no descriptor, thus the collector is unchecked as the metrics are registered on demand.
+func (c *gethCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c *gethCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, name := range c.names {
+		m := metrics.DefaultRegistry.Get(name)
+		if m == nil {
+			continue
+		}
+		fqName := strings.ReplaceAll(name, "/", "_")
+		switch metric := m.(type) {
+		case metrics.Counter:
+			desc := prometheus.NewDesc(fqName, name, nil, nil)
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(metric.Count()))
+		case metrics.Gauge:
+			desc := prometheus.NewDesc(fqName, name, nil, nil)
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(metric.Value()))
+		case metrics.GaugeFloat64:
+			desc := prometheus.NewDesc(fqName, name, nil, nil)
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, metric.Value())
+		case metrics.Histogram:
+			snapshot := metric.Snapshot()
+			quantiles := []float64{.5, .9, .99}
+			values := snapshot.Percentiles(quantiles)
+			summary := make(map[float64]float64, len(quantiles))
+			for i, q := range quantiles {
+				summary[q] = values[i]
+			}
+			desc := prometheus.NewDesc(fqName, name, nil, nil)
+			ch <- prometheus.MustNewConstSummary(desc, uint64(snapshot.Count()), float64(snapshot.Sum()), summary)
+		}
+	}
+}
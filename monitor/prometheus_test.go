@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *PromMetrics) string {
+	srv := httptest.NewServer(m.Handler())
+	defer srv.Close()
+	res, err := srv.Client().Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestPrometheus(t *testing.T) {
+	metrics.Enabled = true
+	m := EnablePrometheus()
+	defer func() {
+		metrics.Enabled = false
+		Prometheus = nil
+	}()
+
+	InitOracleMetrics()
+	metrics.GetOrRegisterGauge(RoundMetric, nil).Update(7)
+	GetOrRegisterHistogram(VoteLatencyMetric).Update(1500)
+	UpdatePluginPrice("crypto_kraken", "cex", "NTN-USD", 1.5)
+	UpdatePluginPrice("forex_ecb", "cex", "EUR-USD", 1.1)
+	ObservePluginFetch("crypto_kraken", "cex", 200*time.Millisecond, errors.New("boom"))
+	CountPluginFetchTimeout("crypto_kraken", "cex")
+	UpdateSymbolReport("NTN-USD", 1.5, 100)
+
+	out := scrape(t, m)
+	require.Contains(t, out, `oracle_plugin_price{plugin="crypto_kraken",source="cex",symbol="NTN-USD"} 1.5`)
+	require.Contains(t, out, `oracle_plugin_fetch_errors_total{plugin="crypto_kraken",source="cex"} 1`)
+	require.Contains(t, out, `oracle_plugin_fetch_timeouts_total{plugin="crypto_kraken",source="cex"} 1`)
+	require.Contains(t, out, `oracle_plugin_fetch_latency_seconds_count{plugin="crypto_kraken",source="cex"} 1`)
+	require.Contains(t, out, `oracle_report_confidence{symbol="NTN-USD"} 100`)
+	require.Contains(t, out, "oracle_round 7")
+	require.Contains(t, out, "oracle_vote_latency_count 1")
+	// the per plugin and per symbol metrics are also named in the registry of go-ethereum for the InfluxDB exporters,
+	// but they are not bridged into the native exposition.
+	require.NotNil(t, metrics.DefaultRegistry.Get(PluginPriceMetric("crypto_kraken", "NTN-USD")))
+	require.NotNil(t, metrics.DefaultRegistry.Get(SymbolReportPriceMetric("NTN-USD")))
+	require.NotContains(t, out, "oracle_crypto_kraken")
+
+	// the series of the unloaded plugin and the symbols no longer sampled are cleaned up.
+	RemovePluginMetrics("crypto_kraken")
+	RemoveSymbolMetrics("NTN-USD")
+	out = scrape(t, m)
+	require.NotContains(t, out, `plugin="crypto_kraken"`)
+	require.NotContains(t, out, `symbol="NTN-USD"`)
+	require.Nil(t, metrics.DefaultRegistry.Get(PluginPriceMetric("crypto_kraken", "NTN-USD")))
+	require.Nil(t, metrics.DefaultRegistry.Get(SymbolReportPriceMetric("NTN-USD")))
+	require.Contains(t, out, `oracle_plugin_price{plugin="forex_ecb",source="cex",symbol="EUR-USD"} 1.1`)
+}
+
+func TestRemovePluginMetrics(t *testing.T) {
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = false }()
+
+	UpdatePluginPrice("crypto_okx", "cex", "NTN-USD", 1.5)
+	CountPluginFetchSkipped("crypto_okx", "cex")
+	UpdatePluginPrice("crypto_bybit", "cex", "NTN-USD", 1.5)
+	require.NotNil(t, metrics.DefaultRegistry.Get(PluginPriceMetric("crypto_okx", "NTN-USD")))
+
+	RemovePluginMetrics("crypto_okx")
+	require.Nil(t, metrics.DefaultRegistry.Get(PluginPriceMetric("crypto_okx", "NTN-USD")))
+	require.Nil(t, metrics.DefaultRegistry.Get(PluginFetchSkippedMetric("crypto_okx")))
+	require.NotNil(t, metrics.DefaultRegistry.Get(PluginPriceMetric("crypto_bybit", "NTN-USD")))
+}
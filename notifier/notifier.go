@@ -0,0 +1,185 @@
+package notifier
+
+import (
+	"autonity-oracle/config"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// The critical conditions of the oracle server to be alerted.
+const (
+	LowBalance     = "lowBalance"     // the balance of the oracle account reaches the threshold.
+	OutlierPenalty = "outlierPenalty" // the oracle node is slashed as an outlier.
+	InvalidVote    = "invalidVote"    // the vote of the oracle node is invalid.
+	NoReveal       = "noReveal"       // the oracle node missed the reveal of its commitment.
+	LostSync       = "lostSync"       // the connectivity with the L1 node is lost.
+)
+
+// Alert is the message sent to the sinks once a condition reaches its threshold.
+type Alert struct {
+	Condition string            `json:"condition"`
+	Node      string            `json:"node"` // the address of the oracle account.
+	Message   string            `json:"message"`
+	Value     string            `json:"value,omitempty"` // the measured value of the condition, empty for the counted ones.
+	Threshold uint64            `json:"threshold"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Time      time.Time         `json:"time"`
+}
+
+// String formats the alert as a human-readable text for the chat and the mail sinks.
+func (a *Alert) String() string {
+	text := fmt.Sprintf("[autonity-oracle] %s on %s: %s", a.Condition, a.Node, a.Message)
+	if len(a.Value) != 0 {
+		text += fmt.Sprintf(" (value: %s, threshold: %d)", a.Value, a.Threshold)
+	}
+	keys := make([]string, 0, len(a.Fields))
+	for k := range a.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		text += fmt.Sprintf("\n%s: %s", k, a.Fields[k])
+	}
+	return text
+}
+
+// Sink delivers the alerts to an external system.
+type Sink interface {
+	Name() string
+	Send(alert *Alert) error
+}
+
+// condition keeps the config and the state of the alert of a condition.
+type condition struct {
+	conf     config.AlertCondition
+	below    bool // the condition alerts when the value is at or below the threshold rather than above it.
+	counted  bool // the condition is measured by the occurrences since its last alert rather than by the value.
+	count    uint64
+	lastSent time.Time
+}
+
+// Notifier raises the alerts of the critical conditions to the sinks, a condition alerts once it reaches its threshold,
+// and the repeated alerts of it are suppressed within its de-duplication interval.
+type Notifier struct {
+	lock       sync.Mutex
+	logger     hclog.Logger
+	node       string
+	conditions map[string]*condition
+	sinks      []Sink
+}
+
+func NewNotifier(conf config.AlertingConfig, node string, logger hclog.Logger) (*Notifier, error) {
+	n := &Notifier{
+		logger: logger,
+		node:   node,
+		conditions: map[string]*condition{
+			LowBalance:     {conf: conf.LowBalance, below: true},
+			OutlierPenalty: {conf: conf.OutlierPenalty, counted: true},
+			InvalidVote:    {conf: conf.InvalidVote, counted: true},
+			NoReveal:       {conf: conf.NoReveal},
+			LostSync:       {conf: conf.LostSync},
+		},
+	}
+
+	for _, c := range conf.Sinks {
+		sink, err := newSink(c)
+		if err != nil {
+			return nil, err
+		}
+		n.sinks = append(n.sinks, sink)
+	}
+	return n, nil
+}
+
+func newSink(conf config.AlertSink) (Sink, error) {
+	switch conf.Type {
+	case config.AlertSinkWebhook:
+		return newWebhookSink(conf.URL), nil
+	case config.AlertSinkSlack:
+		return newSlackSink(conf.URL), nil
+	case config.AlertSinkScript:
+		return newScriptSink(conf.Command), nil
+	case config.AlertSinkSMTP:
+		return newSMTPSink(conf), nil
+	default:
+		return nil, fmt.Errorf("unknown alert sink type %q", conf.Type)
+	}
+}
+
+// Notify checks the condition with its measured value, the value of a counted condition is omitted, and raises the
+// alert to the sinks if it reaches the threshold and is not suppressed. The key values are the context of the alert,
+// in the same form as the ones of the logger. It is safe to call on a nil notifier, thus the alerting is disabled.
+func (n *Notifier) Notify(name string, value *big.Int, message string, keyValues ...interface{}) {
+	if n == nil {
+		return
+	}
+
+	n.lock.Lock()
+	c, ok := n.conditions[name]
+	if !ok || c.conf.Disabled {
+		n.lock.Unlock()
+		return
+	}
+
+	if !c.reached(value) {
+		n.lock.Unlock()
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(c.lastSent) < time.Duration(c.conf.Dedup)*time.Second {
+		n.lock.Unlock()
+		n.logger.Debug("suppressed duplicated alert", "condition", name)
+		return
+	}
+	c.lastSent = now
+	c.count = 0
+	n.lock.Unlock()
+
+	alert := &Alert{
+		Condition: name,
+		Node:      n.node,
+		Message:   message,
+		Threshold: c.conf.Threshold,
+		Fields:    make(map[string]string),
+		Time:      now,
+	}
+	if value != nil {
+		alert.Value = value.String()
+	}
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		alert.Fields[fmt.Sprint(keyValues[i])] = fmt.Sprint(keyValues[i+1])
+	}
+
+	// the sinks can be slow or unreachable, thus they are not waited by the caller.
+	for _, s := range n.sinks {
+		go func(s Sink) {
+			if err := s.Send(alert); err != nil {
+				n.logger.Warn("failed to send alert", "sink", s.Name(), "condition", name, "error", err.Error())
+			}
+		}(s)
+	}
+}
+
+// reached counts the occurrence of a counted condition, and checks if the condition reaches its threshold.
+func (c *condition) reached(value *big.Int) bool {
+	threshold := new(big.Int).SetUint64(c.conf.Threshold)
+	if c.counted {
+		c.count++
+		return c.count >= c.conf.Threshold
+	}
+
+	if value == nil {
+		return false
+	}
+
+	if c.below {
+		return value.Cmp(threshold) <= 0
+	}
+	return value.Cmp(threshold) >= 0
+}
@@ -0,0 +1,175 @@
+package notifier
+
+import (
+	"autonity-oracle/config"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifier(t *testing.T) {
+	alerts := make(chan *Alert, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var a Alert
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		alerts <- &a
+	}))
+	defer srv.Close()
+
+	conf := config.DefaultAlertingConfig
+	conf.InvalidVote = config.AlertCondition{Threshold: 2}
+	conf.NoReveal.Disabled = true
+	conf.Sinks = []config.AlertSink{{Type: config.AlertSinkWebhook, URL: srv.URL}}
+	n, err := NewNotifier(conf, "0x01", hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	expectAlert := func(condition string) *Alert {
+		select {
+		case a := <-alerts:
+			require.Equal(t, condition, a.Condition)
+			return a
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no alert received", condition)
+		}
+		return nil
+	}
+	expectNoAlert := func() {
+		select {
+		case a := <-alerts:
+			require.FailNow(t, "unexpected alert", a.Condition)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	t.Run("low balance alerts at or below the threshold, and it is de-duplicated", func(t *testing.T) {
+		n.Notify(LowBalance, big.NewInt(2000000000001), "low balance")
+		expectNoAlert()
+		n.Notify(LowBalance, big.NewInt(2000000000000), "low balance")
+		a := expectAlert(LowBalance)
+		require.Equal(t, "0x01", a.Node)
+		require.Equal(t, "2000000000000", a.Value)
+		n.Notify(LowBalance, big.NewInt(1), "low balance")
+		expectNoAlert()
+	})
+
+	t.Run("counted conditions alert once the occurrences reach the threshold", func(t *testing.T) {
+		n.Notify(InvalidVote, nil, "invalid vote", "cause", "price")
+		expectNoAlert()
+		n.Notify(InvalidVote, nil, "invalid vote", "cause", "price")
+		a := expectAlert(InvalidVote)
+		require.Equal(t, "price", a.Fields["cause"])
+		n.Notify(InvalidVote, nil, "invalid vote", "cause", "price")
+		expectNoAlert()
+	})
+
+	t.Run("every penalty alerts by default", func(t *testing.T) {
+		n.Notify(OutlierPenalty, nil, "penalized")
+		expectAlert(OutlierPenalty)
+		n.Notify(OutlierPenalty, nil, "penalized")
+		expectAlert(OutlierPenalty)
+	})
+
+	t.Run("lost sync alerts after the threshold, and disabled conditions never alert", func(t *testing.T) {
+		n.Notify(LostSync, big.NewInt(30), "lost sync")
+		expectNoAlert()
+		n.Notify(LostSync, big.NewInt(60), "lost sync")
+		expectAlert(LostSync)
+		n.Notify(NoReveal, big.NewInt(10), "no reveal")
+		expectNoAlert()
+	})
+
+	t.Run("nil notifier is a no-op", func(t *testing.T) {
+		var disabled *Notifier
+		disabled.Notify(OutlierPenalty, nil, "penalized")
+	})
+}
+
+func TestSinks(t *testing.T) {
+	alert := &Alert{
+		Condition: OutlierPenalty,
+		Node:      "0x01",
+		Message:   "penalized as an outlier",
+		Fields:    map[string]string{"symbol": "NTN-USD", "median": "100"},
+		Time:      time.Now(),
+	}
+
+	t.Run("slack sink posts the alert as text", func(t *testing.T) {
+		texts := make(chan string, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var msg map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+			texts <- msg["text"]
+		}))
+		defer srv.Close()
+
+		require.NoError(t, newSlackSink(srv.URL).Send(alert))
+		require.Equal(t, "[autonity-oracle] outlierPenalty on 0x01: penalized as an outlier\nmedian: 100\nsymbol: NTN-USD", <-texts)
+	})
+
+	t.Run("webhook sink fails on error status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+		require.Error(t, newWebhookSink(srv.URL).Send(alert))
+	})
+
+	t.Run("script sink takes the alert from stdin and env", func(t *testing.T) {
+		dir := t.TempDir()
+		out := filepath.Join(dir, "alert.json")
+		env := filepath.Join(dir, "alert.env")
+		script := filepath.Join(dir, "alert.sh")
+		require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\ncat > "+out+"\necho $ORACLE_ALERT_CONDITION > "+env+"\n"), 0700)) //nolint
+
+		require.NoError(t, newScriptSink(script).Send(alert))
+		content, err := os.ReadFile(out)
+		require.NoError(t, err)
+		var a Alert
+		require.NoError(t, json.Unmarshal(content, &a))
+		require.Equal(t, alert.Fields, a.Fields)
+		content, err = os.ReadFile(env)
+		require.NoError(t, err)
+		require.Equal(t, OutlierPenalty, strings.TrimSpace(string(content)))
+	})
+
+	t.Run("smtp sink composes the mail", func(t *testing.T) {
+		s := newSMTPSink(config.AlertSink{Host: "localhost:25", From: "oracle@example.com", To: []string{"a@example.com", "b@example.com"}})
+		msg := string(s.message(alert))
+		require.Contains(t, msg, "To: a@example.com, b@example.com\r\n")
+		require.Contains(t, msg, "Subject: [autonity-oracle] outlierPenalty on 0x01\r\n")
+		require.True(t, strings.HasSuffix(msg, "\r\nsymbol: NTN-USD\r\n"))
+	})
+
+	t.Run("smtp sink gives up on a blackholed server", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+		go func() {
+			// accept the connection but never greet.
+			if conn, err := ln.Accept(); err == nil {
+				defer conn.Close()
+				time.Sleep(2 * time.Second)
+			}
+		}()
+
+		defer func(timeout time.Duration) { sendTimeout = timeout }(sendTimeout)
+		sendTimeout = 200 * time.Millisecond
+		s := newSMTPSink(config.AlertSink{Host: ln.Addr().String(), From: "oracle@example.com", To: []string{"a@example.com"}})
+		start := time.Now()
+		require.Error(t, s.Send(alert))
+		require.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,196 @@
+package notifier
+
+import (
+	"autonity-oracle/config"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var sendTimeout = 10 * time.Second // the deadline of a sink to deliver an alert.
+
+// webhookSink posts the alert in JSON to the URL.
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookSink(url string) *webhookSink {
+	return &webhookSink{url: url, client: &http.Client{Timeout: sendTimeout}}
+}
+
+func (w *webhookSink) Name() string {
+	return config.AlertSinkWebhook
+}
+
+func (w *webhookSink) Send(alert *Alert) error {
+	return postJSON(w.client, w.url, alert)
+}
+
+// slackSink posts the alert as a text message to a Slack compatible incoming webhook URL.
+type slackSink struct {
+	url    string
+	client *http.Client
+}
+
+func newSlackSink(url string) *slackSink {
+	return &slackSink{url: url, client: &http.Client{Timeout: sendTimeout}}
+}
+
+func (s *slackSink) Name() string {
+	return config.AlertSinkSlack
+}
+
+func (s *slackSink) Send(alert *Alert) error {
+	return postJSON(s.client, s.url, map[string]string{"text": alert.String()})
+}
+
+func postJSON(client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
+
+// scriptSink executes the command with the alert in JSON on its stdin, the condition and the message of the alert are
+// passed by the environment variables as well for the simple scripts.
+type scriptSink struct {
+	command string
+}
+
+func newScriptSink(command string) *scriptSink {
+	return &scriptSink{command: command}
+}
+
+func (s *scriptSink) Name() string {
+	return config.AlertSinkScript
+}
+
+func (s *scriptSink) Send(alert *Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.command) //nolint
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(cmd.Environ(), "ORACLE_ALERT_CONDITION="+alert.Condition, "ORACLE_ALERT_NODE="+alert.Node,
+		"ORACLE_ALERT_MESSAGE="+alert.Message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// smtpSink mails the alert over SMTP, the auth is skipped if the username is not set.
+type smtpSink struct {
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func newSMTPSink(conf config.AlertSink) *smtpSink {
+	return &smtpSink{
+		host:     conf.Host,
+		username: conf.Username,
+		password: conf.Password,
+		from:     conf.From,
+		to:       conf.To,
+	}
+}
+
+func (s *smtpSink) Name() string {
+	return config.AlertSinkSMTP
+}
+
+// Send mails the alert over a connection with the deadline of the sinks, as smtp.SendMail waits on a blackholed
+// server forever.
+func (s *smtpSink) Send(alert *Alert) error {
+	host, _, err := net.SplitHostPort(s.host)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", s.host, sendTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(sendTimeout)); err != nil {
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if len(s.username) != 0 {
+		if err = c.Auth(smtp.PlainAuth("", s.username, s.password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err = c.Mail(s.from); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err = c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(s.message(alert)); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (s *smtpSink) message(alert *Alert) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&msg, "Subject: [autonity-oracle] %s on %s\r\n", alert.Condition, alert.Node)
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.Time.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(alert.String(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes()
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package pluginwrapper
+
+import (
+	"autonity-oracle/config"
+
+	"golang.org/x/sys/unix"
+)
+
+// applyProcessLimits restricts the memory and the scheduling priority of the spawned plugin process by according to
+// its configuration. The memory limit is applied on the data segment rather than the address space, since the go
+// runtime of a plugin reserves much more virtual memory than it actually uses.
+func applyProcessLimits(pid int, conf *config.PluginConfig) error {
+	if conf == nil {
+		return nil
+	}
+
+	if conf.MaxMemory > 0 {
+		limit := uint64(conf.MaxMemory) * 1024 * 1024 //nolint
+		if err := unix.Prlimit(pid, unix.RLIMIT_DATA, &unix.Rlimit{Cur: limit, Max: limit}, nil); err != nil {
+			return err
+		}
+	}
+
+	if conf.Niceness != 0 {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, pid, conf.Niceness); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package pluginwrapper
+
+import (
+	"autonity-oracle/config"
+	"errors"
+)
+
+var errLimitsNotSupported = errors.New("plugin process limits are only supported on linux")
+
+// applyProcessLimits is not supported out of linux, it refuses to start a plugin which asks for limits.
+func applyProcessLimits(_ int, conf *config.PluginConfig) error {
+	if conf != nil && (conf.MaxMemory > 0 || conf.Niceness != 0) {
+		return errLimitsNotSupported
+	}
+	return nil
+}
@@ -2,24 +2,39 @@ package pluginwrapper
 
 import (
 	"autonity-oracle/config"
+	"autonity-oracle/helpers"
+	"autonity-oracle/monitor"
+	samplerecorder "autonity-oracle/sample_recorder"
 	"autonity-oracle/types"
+	"context"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"math/big"
 	"os"
 	"os/exec"
-	"strings"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var (
-	sampleTTL = 30 // 30s, the TTL of a sample before GC it.
+	defaultFetchTimeout = 10 // 10s, the host side deadline of a price fetching if the plugin has no timeout configured.
 )
 
+// fetchResult carries the outcome of an adapter call back to the waiting sampling routine.
+type fetchResult struct {
+	report types.PluginPriceReport
+	err    error
+}
+
 // PluginWrapper is the unified wrapper for the interface of a plugin, it contains metadata of a corresponding
 // plugin, buffers recent data samples measured from the corresponding plugin.
 type PluginWrapper struct {
@@ -42,8 +57,12 @@ type PluginWrapper struct {
 	subSampleEvent event.Subscription
 	samplingSub    types.SampleEventSubscriber
 
-	// metrics for the prices that are sampled by per plugin.
-	priceMetrics map[string]metrics.GaugeFloat64
+	// counters of the sampling routines that were timed out or skipped due to an on-going one.
+	timeouts atomic.Uint64
+	skipped  atomic.Uint64
+
+	// the recorder of the price reports, nil if the recording is disabled.
+	recorder *samplerecorder.Recorder
 }
 
 func NewPluginWrapper(logLevel hclog.Level, name string, pluginDir string, sub types.SampleEventSubscriber, conf *config.PluginConfig) *PluginWrapper {
@@ -77,13 +96,32 @@ func NewPluginWrapper(logLevel hclog.Level, name string, pluginDir string, sub t
 		samples:          make(map[string]map[int64]types.Price),
 		latestTimestamps: make(map[string]int64),
 		chSampleEvent:    make(chan *types.SampleEvent),
-		priceMetrics:     make(map[string]metrics.GaugeFloat64),
 		logger:           logger,
 	}
 
 	return p
 }
 
+// NewSampleWrapper creates a wrapper without a plugin process, its samples are added by AddSample. It is used to
+// replay the recorded samples of a plugin through the price aggregation of the oracle server.
+func NewSampleWrapper(name string, srcType types.DataSourceType, conf *config.PluginConfig) *PluginWrapper {
+	return &PluginWrapper{
+		name:             name,
+		conf:             conf,
+		dataSrcType:      srcType,
+		startAt:          time.Now(),
+		doneCh:           make(chan struct{}),
+		samples:          make(map[string]map[int64]types.Price),
+		latestTimestamps: make(map[string]int64),
+		logger:           hclog.NewNullLogger(),
+	}
+}
+
+// SetRecorder sets the recorder to log the price reports of the plugin.
+func (pw *PluginWrapper) SetRecorder(recorder *samplerecorder.Recorder) {
+	pw.recorder = recorder
+}
+
 func (pw *PluginWrapper) Config() *config.PluginConfig {
 	return pw.conf
 }
@@ -107,10 +145,11 @@ func (pw *PluginWrapper) AddSample(prices []types.Price, ts int64) {
 }
 
 // AggregatedPrice returns the aggregated price computed from a set of pre-samples of a symbol by a specific plugin.
-// For data points from AMM and AFQ markets, they are aggregated by the samples of the recent pre-samplings period,
-// while for data points from CEX, the last sample of the pre-sampling period will be taken.
+// For data points from AMM and AFQ markets, they are aggregated by the samples of the recent pre-samplings window with
+// TWAP or VWAP, while for data points from CEX, the nearest sample to the target will be taken. The window and the
+// strategy are configured by the oracle server per data source type, a zero window takes the nearest sample as well.
 // The target is the timestamp on which the round block is mined, it's used to select datapoint from CEX data source.
-func (pw *PluginWrapper) AggregatedPrice(symbol string, target int64) (types.Price, error) {
+func (pw *PluginWrapper) AggregatedPrice(symbol string, target int64, conf config.SamplingConfig) (types.Price, error) {
 	pw.lockSamples.RLock()
 	defer pw.lockSamples.RUnlock()
 	tsMap, ok := pw.samples[symbol]
@@ -125,6 +164,14 @@ func (pw *PluginWrapper) AggregatedPrice(symbol string, target int64) (types.Pri
 		}
 	}
 
+	if conf.Window > 0 {
+		price, err := pw.windowedPrice(symbol, tsMap, target, conf)
+		if err == nil {
+			return price, nil
+		}
+		pw.logger.Debug("fall back to nearest sample", "symbol", symbol, "targetTS", target, "reason", err.Error())
+	}
+
 	// Try to get the target TS sample, otherwise we search for the nearest measurement.
 	if p, ok := tsMap[target]; ok {
 		return p, nil
@@ -149,16 +196,77 @@ func (pw *PluginWrapper) AggregatedPrice(symbol string, target int64) (types.Pri
 	return price, nil
 }
 
+// windowedPrice aggregates the samples in the window of [target-window, target] with TWAP or VWAP. For TWAP, each
+// sample is weighted by the seconds it was held for until the next sample or until the target, at least 1 second. The
+// returned price carries the meta data of the latest sample in the window.
+func (pw *PluginWrapper) windowedPrice(symbol string, tsMap map[int64]types.Price, target int64, conf config.SamplingConfig) (types.Price, error) {
+	var timestamps []int64
+	for ts := range tsMap {
+		if ts >= target-int64(conf.Window) && ts <= target {
+			timestamps = append(timestamps, ts)
+		}
+	}
+
+	if len(timestamps) == 0 {
+		return types.Price{}, types.ErrNoAvailablePrice
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	prices := make([]decimal.Decimal, len(timestamps))
+	volumes := make([]*big.Int, len(timestamps))
+	durations := make([]int64, len(timestamps))
+	for i, ts := range timestamps {
+		prices[i] = tsMap[ts].Price
+		volumes[i] = tsMap[ts].Volume
+		if volumes[i] == nil {
+			volumes[i] = new(big.Int)
+		}
+
+		next := target
+		if i+1 < len(timestamps) {
+			next = timestamps[i+1]
+		}
+		durations[i] = next - ts
+		if durations[i] < 1 {
+			durations[i] = 1
+		}
+	}
+
+	result := tsMap[timestamps[len(timestamps)-1]]
+	if conf.Strategy == config.AggregationStrategyVWAP {
+		p, vol, err := helpers.VWAP(prices, volumes)
+		if err == nil {
+			result.Price = p
+			result.Volume = vol
+			pw.logger.Debug("VWAP of samples", "symbol", symbol, "samples", len(timestamps), "targetTS", target, "price", result)
+			return result, nil
+		}
+		// no volume was traded in the window, take TWAP instead.
+		pw.logger.Debug("cannot compute VWAP of samples, take TWAP", "symbol", symbol, "reason", err.Error())
+	}
+
+	p, err := helpers.TWAP(prices, durations)
+	if err != nil {
+		return types.Price{}, err
+	}
+	result.Price = p
+	pw.logger.Debug("TWAP of samples", "symbol", symbol, "samples", len(timestamps), "targetTS", target, "price", result)
+	return result, nil
+}
+
 // GCExpiredSamples removes data points that are older than the TTL seconds of per plugin, it leaves recent samples
-// together with next round's pre-samples as the input for the price aggregation for AMM, AFQ plugins. While, for CEX
-// plugins, only the latest sample are kept without GC.
-func (pw *PluginWrapper) GCExpiredSamples() {
+// together with next round's pre-samples as the input for the price aggregation. The TTL is configured by the oracle
+// server per data source type, thus AMM, AFQ plugins can keep a longer window of samples than the CEX plugins.
+func (pw *PluginWrapper) GCExpiredSamples(ttl int) {
+	currentTime := time.Now().Unix() // Get the current time in seconds
+	pw.GCSamplesBefore(currentTime - int64(ttl))
+}
+
+// GCSamplesBefore removes data points that were sampled before the threshold timestamp.
+func (pw *PluginWrapper) GCSamplesBefore(threshold int64) {
 	pw.lockSamples.Lock()
 	defer pw.lockSamples.Unlock()
 
-	currentTime := time.Now().Unix() // Get the current time in seconds
-	threshold := currentTime - int64(sampleTTL)
-
 	for symbol, tsMap := range pw.samples {
 		if len(tsMap) == 0 {
 			continue // Skip if there are no samples for this symbol
@@ -187,6 +295,10 @@ func (pw *PluginWrapper) Version() string {
 	return pw.version
 }
 
+func (pw *PluginWrapper) DataSourceType() types.DataSourceType {
+	return pw.dataSrcType
+}
+
 func (pw *PluginWrapper) StartTime() time.Time {
 	return pw.startAt
 }
@@ -209,6 +321,16 @@ func (pw *PluginWrapper) Initialize(chainID int64) error {
 
 	pw.adapter = raw.(types.Adapter)
 
+	// apply the process limits on the spawned plugin process if there are any.
+	if reattach := pw.plugin.ReattachConfig(); reattach != nil {
+		if err = applyProcessLimits(reattach.Pid, pw.conf); err != nil {
+			pw.logger.Error("cannot apply process limits", "pid", reattach.Pid, "error", err.Error())
+			// the process is already running without the limits, don't leave it behind.
+			pw.CleanPluginProcess()
+			return err
+		}
+	}
+
 	// load with plugin's statement, check if chainID is matched.
 	state, err := pw.state(chainID)
 	if err != nil {
@@ -218,11 +340,10 @@ func (pw *PluginWrapper) Initialize(chainID int64) error {
 	pw.dataSrcType = state.DataSourceType
 	pw.version = state.Version
 
-	// create metrics for plugin on init phase.
+	// create metrics for plugin on init phase, the labeled series of the native prometheus are created on sampling.
 	if metrics.Enabled {
 		for _, symbol := range state.AvailableSymbols {
-			name := strings.Join([]string{"oracle", pw.Name(), symbol, "price"}, "/")
-			metrics.GetOrRegisterGaugeFloat64(name, nil)
+			metrics.GetOrRegisterGaugeFloat64(monitor.PluginPriceMetric(pw.Name(), symbol), nil)
 		}
 	}
 
@@ -255,7 +376,7 @@ func (pw *PluginWrapper) start() {
 		case sampleEvent := <-pw.chSampleEvent:
 			pw.logger.Debug("sampling price", "symbols", sampleEvent.Symbols, "TS", sampleEvent.TS)
 			go func() {
-				err := pw.fetchPrices(sampleEvent.Symbols, sampleEvent.TS)
+				err := pw.fetchPrices(sampleEvent.Symbols, sampleEvent.TS, sampleEvent.Round)
 				if err != nil {
 					pw.logger.Warn("fetch price routine", "error", err.Error())
 					return
@@ -275,16 +396,76 @@ func (pw *PluginWrapper) state(chainID int64) (types.PluginStatement, error) {
 	return state, nil
 }
 
-func (pw *PluginWrapper) fetchPrices(symbols []string, ts int64) error {
-	// prevent race condition throughout data sampling routines in case of waiting for timeout.
-	pw.lockService.Lock()
-	defer pw.lockService.Unlock()
+// Timeouts returns the number of price fetching that were not answered by the plugin before the deadline.
+func (pw *PluginWrapper) Timeouts() uint64 {
+	return pw.timeouts.Load()
+}
 
-	report, err := pw.adapter.FetchPrices(symbols)
-	if err != nil {
-		return err
+// Skipped returns the number of sampling events that were skipped as the plugin was still serving the previous one.
+func (pw *PluginWrapper) Skipped() uint64 {
+	return pw.skipped.Load()
+}
+
+// fetchTimeout resolves the host side deadline of a price fetching from the plugin's configured timeout.
+func (pw *PluginWrapper) fetchTimeout() time.Duration {
+	if pw.conf == nil || pw.conf.Timeout <= 0 {
+		return time.Duration(defaultFetchTimeout) * time.Second
+	}
+	return time.Duration(pw.conf.Timeout) * time.Second
+}
+
+// fetchPrices samples the prices of the symbols from the plugin, the span of the fetching is linked with the round's
+// span, as the sampling is driven by the pre-sampling ticker rather than by the round event.
+func (pw *PluginWrapper) fetchPrices(symbols []string, ts int64, round trace.SpanContext) (err error) {
+	_, span := monitor.Tracer().Start(context.Background(), "FetchPrices", trace.WithLinks(trace.Link{SpanContext: round}),
+		trace.WithAttributes(attribute.String("plugin", pw.name), attribute.Int64("ts", ts), attribute.StringSlice("symbols", symbols)))
+	defer func() {
+		monitor.EndSpan(span, err)
+	}()
+
+	// prevent race condition throughout data sampling routines, if the last sampling is still waiting for the plugin,
+	// skip this one rather than piling up another routine on a hung plugin.
+	if !pw.lockService.TryLock() {
+		pw.skipped.Add(1)
+		if metrics.Enabled {
+			monitor.CountPluginFetchSkipped(pw.Name(), pw.dataSrcType.String())
+		}
+		return types.ErrSamplingOverlap
 	}
 
+	// the service lock is released once the adapter returns, thus a hung plugin holds at most one routine.
+	start := time.Now()
+	resultCh := make(chan fetchResult, 1)
+	go func() {
+		defer pw.lockService.Unlock()
+		report, err := pw.adapter.FetchPrices(symbols)
+		resultCh <- fetchResult{report: report, err: err}
+	}()
+
+	timer := time.NewTimer(pw.fetchTimeout())
+	defer timer.Stop()
+
+	var result fetchResult
+	select {
+	case result = <-resultCh:
+	case <-timer.C:
+		pw.timeouts.Add(1)
+		if metrics.Enabled {
+			monitor.CountPluginFetchTimeout(pw.Name(), pw.dataSrcType.String())
+		}
+		pw.record(symbols, ts, start, fetchResult{err: types.ErrFetchTimeout})
+		return types.ErrFetchTimeout
+	}
+	pw.record(symbols, ts, start, result)
+	if metrics.Enabled {
+		monitor.ObservePluginFetch(pw.Name(), pw.dataSrcType.String(), time.Since(start), result.err)
+	}
+
+	if result.err != nil {
+		return result.err
+	}
+
+	report := result.report
 	if len(report.UnRecognizableSymbols) != 0 {
 		pw.logger.Debug("some symbol are not supported yet in this plugin", "unsupported", report.UnRecognizableSymbols)
 	}
@@ -299,17 +480,31 @@ func (pw *PluginWrapper) fetchPrices(symbols []string, ts int64) error {
 	return nil
 }
 
+// record logs the result of a price fetching if the recording is enabled.
+func (pw *PluginWrapper) record(symbols []string, ts int64, start time.Time, result fetchResult) {
+	if pw.recorder == nil {
+		return
+	}
+	rec := &samplerecorder.Record{
+		Plugin:  pw.name,
+		Version: pw.version,
+		Source:  pw.dataSrcType,
+		TS:      ts,
+		Symbols: symbols,
+		Latency: time.Since(start).Milliseconds(),
+		Report:  result.report,
+	}
+	if result.err != nil {
+		rec.Error = result.err.Error()
+	}
+	if err := pw.recorder.Record(rec); err != nil {
+		pw.logger.Warn("failed to record price report", "error", err.Error())
+	}
+}
+
 func (pw *PluginWrapper) updateMetrics(prices []types.Price) {
 	for _, p := range prices {
-		m, ok := pw.priceMetrics[p.Symbol]
-		if !ok {
-			name := strings.Join([]string{"oracle", pw.Name(), p.Symbol, "price"}, "/")
-			gauge := metrics.GetOrRegisterGaugeFloat64(name, nil)
-			gauge.Update(p.Price.InexactFloat64())
-			pw.priceMetrics[p.Symbol] = gauge
-			continue
-		}
-		m.Update(p.Price.InexactFloat64())
+		monitor.UpdatePluginPrice(pw.Name(), pw.dataSrcType.String(), p.Symbol, p.Price.InexactFloat64())
 	}
 }
 
@@ -321,4 +516,7 @@ func (pw *PluginWrapper) Close() {
 	pw.plugin.Kill()
 	pw.doneCh <- struct{}{}
 	pw.subSampleEvent.Unsubscribe()
+	if metrics.Enabled {
+		monitor.RemovePluginMetrics(pw.Name())
+	}
 }
@@ -1,10 +1,13 @@
 package pluginwrapper
 
 import (
+	"autonity-oracle/config"
 	"autonity-oracle/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -34,48 +37,143 @@ func TestPluginWrapper(t *testing.T) {
 		}
 
 		target := now
-		price, err := p.AggregatedPrice("NTNGBP", target)
+		price, err := p.AggregatedPrice("NTNGBP", target, config.DefaultSamplingConfig)
 		require.NoError(t, err)
 		require.Equal(t, now, price.Timestamp)
 
 		// upper bound
 		target = now + 100
-		price, err = p.AggregatedPrice("NTNGBP", target)
+		price, err = p.AggregatedPrice("NTNGBP", target, config.DefaultSamplingConfig)
 		require.NoError(t, err)
 		require.Equal(t, now+59, price.Timestamp)
 
 		// lower bound
 		target = now - 1
-		price, err = p.AggregatedPrice("NTNGBP", target)
+		price, err = p.AggregatedPrice("NTNGBP", target, config.DefaultSamplingConfig)
 		require.NoError(t, err)
 		require.Equal(t, now, price.Timestamp)
 
 		// middle
 		target = now + 29
-		price, err = p.AggregatedPrice("NTNGBP", target)
+		price, err = p.AggregatedPrice("NTNGBP", target, config.DefaultSamplingConfig)
 		require.NoError(t, err)
 		require.Equal(t, now+28, price.Timestamp)
 
 		// middle
 		target = now + 33
-		price, err = p.AggregatedPrice("NTNGBP", target)
+		price, err = p.AggregatedPrice("NTNGBP", target, config.DefaultSamplingConfig)
 		require.NoError(t, err)
 		require.Equal(t, now+35, price.Timestamp)
 
 		// middle
 		target = now + 34
-		price, err = p.AggregatedPrice("NTNGBP", target)
+		price, err = p.AggregatedPrice("NTNGBP", target, config.DefaultSamplingConfig)
 		require.NoError(t, err)
 		require.Equal(t, now+35, price.Timestamp)
 
 		// middle
 		target = now + 35
-		price, err = p.AggregatedPrice("NTNGBP", target)
+		price, err = p.AggregatedPrice("NTNGBP", target, config.DefaultSamplingConfig)
 		require.NoError(t, err)
 		require.Equal(t, now+35, price.Timestamp)
 
 		// test gc, at least 1 sample is kept in the cache.
-		p.GCExpiredSamples()
+		p.GCExpiredSamples(config.DefaultSamplingConfig.SampleTTL)
 		require.Equal(t, 1, len(p.samples))
 	})
 }
+
+func TestPluginWrapperWindowedAggregation(t *testing.T) {
+	p := PluginWrapper{
+		logger:           hclog.NewNullLogger(),
+		samples:          make(map[string]map[int64]types.Price),
+		latestTimestamps: make(map[string]int64),
+		dataSrcType:      types.SrcAMM,
+	}
+
+	// a manipulated swap lifts the price to 10 right before the round, while it was 1 for the rest of the window.
+	target := time.Now().Unix()
+	samples := map[int64]string{target - 40: "5", target - 8: "1", target - 6: "1", target - 4: "1", target - 2: "1", target - 1: "10"}
+	for ts, price := range samples {
+		p.AddSample([]types.Price{{
+			Timestamp: ts,
+			Symbol:    "NTN-USDC",
+			Price:     decimal.RequireFromString(price),
+			Volume:    big.NewInt(ts - target + 41),
+		}}, ts)
+	}
+
+	// the nearest sample is taken without window.
+	price, err := p.AggregatedPrice("NTN-USDC", target, config.DefaultSamplingConfig)
+	require.NoError(t, err)
+	require.Equal(t, "10", price.Price.String())
+
+	// TWAP over the window: (1*2 + 1*2 + 1*2 + 1*1 + 10*1) / 8, the sample out of window is not counted.
+	conf := config.SamplingConfig{SampleTTL: 30, Window: 10, Strategy: config.AggregationStrategyTWAP}
+	price, err = p.AggregatedPrice("NTN-USDC", target, conf)
+	require.NoError(t, err)
+	require.Equal(t, "2.125", price.Price.String())
+	require.Equal(t, target-1, price.Timestamp)
+
+	// VWAP over the window: (1*33 + 1*35 + 1*37 + 1*39 + 10*40) / 184
+	conf.Strategy = config.AggregationStrategyVWAP
+	price, err = p.AggregatedPrice("NTN-USDC", target, conf)
+	require.NoError(t, err)
+	require.Equal(t, true, decimal.NewFromInt(544).Div(decimal.NewFromInt(184)).Equal(price.Price))
+
+	// no sample in the window, fall back to the nearest sample.
+	price, err = p.AggregatedPrice("NTN-USDC", target-20, conf)
+	require.NoError(t, err)
+	require.Equal(t, "1", price.Price.String())
+}
+
+// hungAdapter is an adapter that does not answer the price fetching until it is released.
+type hungAdapter struct {
+	release chan struct{}
+}
+
+func (a *hungAdapter) FetchPrices(symbols []string) (types.PluginPriceReport, error) {
+	<-a.release
+	var report types.PluginPriceReport
+	for _, s := range symbols {
+		report.Prices = append(report.Prices, types.Price{Symbol: s, Price: decimal.RequireFromString("1.1")})
+	}
+	return report, nil
+}
+
+func (a *hungAdapter) State(_ int64) (types.PluginStatement, error) {
+	return types.PluginStatement{}, nil
+}
+
+func TestPluginWrapperFetchTimeout(t *testing.T) {
+	adapter := &hungAdapter{release: make(chan struct{})}
+	p := PluginWrapper{
+		name:             "hung_plugin",
+		logger:           hclog.NewNullLogger(),
+		conf:             &config.PluginConfig{Timeout: 1},
+		adapter:          adapter,
+		samples:          make(map[string]map[int64]types.Price),
+		latestTimestamps: make(map[string]int64),
+	}
+
+	now := time.Now().Unix()
+	err := p.fetchPrices([]string{"NTN-USD"}, now, trace.SpanContext{})
+	require.ErrorIs(t, err, types.ErrFetchTimeout)
+	require.Equal(t, uint64(1), p.Timeouts())
+
+	// the overlapped sampling is skipped while the last one is still hung.
+	err = p.fetchPrices([]string{"NTN-USD"}, now+1, trace.SpanContext{})
+	require.ErrorIs(t, err, types.ErrSamplingOverlap)
+	require.Equal(t, uint64(1), p.Skipped())
+	require.Equal(t, uint64(1), p.Timeouts())
+
+	// once the plugin answers, the sampling is served again.
+	close(adapter.release)
+	require.Eventually(t, func() bool {
+		return p.fetchPrices([]string{"NTN-USD"}, now+2, trace.SpanContext{}) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	price, err := p.AggregatedPrice("NTN-USD", now+2, config.DefaultSamplingConfig)
+	require.NoError(t, err)
+	require.Equal(t, "1.1", price.Price.String())
+}
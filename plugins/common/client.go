@@ -1,11 +1,24 @@
 package common
 
 import (
+	"autonity-oracle/config"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
+const (
+	DefaultBreakerCooldown = 60 // 60s, the cooldown period of an open circuit breaker.
+	retryBaseDelay         = 500 * time.Millisecond
+	maxRetryDelay          = 10 * time.Second // a longer Retry-After than it opens the circuit breaker rather than waiting.
+)
+
 type Connection interface {
 	Request(scheme string, endpoint *url.URL) (*http.Response, error)
 	Do(req *http.Request) (*http.Response, error)
@@ -19,19 +32,61 @@ type DataSourceClient interface {
 	Close()
 }
 
+// HTTPPolicy is the rate limit, retry and circuit breaker policy applied on the requests to the data provider.
+type HTTPPolicy struct {
+	RateLimit        float64       // requests per second, 0 means no limit.
+	RateBurst        int           // the bucket size of the rate limiter.
+	MaxRetries       int           // retries on 5xx, 429 and timeout errors.
+	BreakerThreshold int           // consecutive failed requests to open the circuit breaker, 0 disables it.
+	BreakerCooldown  time.Duration // the period that the open circuit breaker rejects the requests.
+}
+
+// NewHTTPPolicy resolves the HTTP policy from the plugin config.
+func NewHTTPPolicy(conf *config.PluginConfig) HTTPPolicy {
+	policy := HTTPPolicy{
+		RateLimit:        conf.RateLimit,
+		RateBurst:        conf.RateBurst,
+		MaxRetries:       conf.MaxRetries,
+		BreakerThreshold: conf.BreakerThreshold,
+		BreakerCooldown:  time.Duration(conf.BreakerCooldown) * time.Second,
+	}
+	if policy.RateBurst <= 0 {
+		policy.RateBurst = 1
+	}
+	if policy.BreakerCooldown <= 0 {
+		policy.BreakerCooldown = DefaultBreakerCooldown * time.Second
+	}
+	return policy
+}
+
 type connection struct {
-	client *http.Client
-	host   string
+	client     *http.Client
+	host       string
+	limiter    *rateLimiter
+	breaker    *circuitBreaker
+	maxRetries int
+	retryDelay time.Duration
 }
 
 func NewConnection(duration time.Duration, host string) Connection {
+	return NewConnectionWithPolicy(duration, host, nil, HTTPPolicy{})
+}
+
+// NewConnectionWithPolicy creates a connection over the transport, the default transport is taken if it is nil, and
+// it applies the policy on every request sent over it.
+func NewConnectionWithPolicy(duration time.Duration, host string, transport http.RoundTripper, policy HTTPPolicy) Connection {
 	client := &http.Client{
-		Timeout: duration,
+		Timeout:   duration,
+		Transport: transport,
 	}
 
 	return &connection{
-		client: client,
-		host:   host,
+		client:     client,
+		host:       host,
+		limiter:    newRateLimiter(policy.RateLimit, policy.RateBurst),
+		breaker:    newCircuitBreaker(policy.BreakerThreshold, policy.BreakerCooldown),
+		maxRetries: policy.MaxRetries,
+		retryDelay: retryBaseDelay,
 	}
 }
 func (conn *connection) Close() {
@@ -42,12 +97,126 @@ func (conn *connection) Close() {
 func (conn *connection) Request(scheme string, endpoint *url.URL) (*http.Response, error) {
 	endpoint.Scheme = scheme
 	endpoint.Host = conn.host
-	targetUrl := endpoint.String()
-	return conn.client.Get(targetUrl)
+	req, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Do(req)
 }
 
+// Do sends the request once the rate limiter allows it, it retries on 5xx, 429 and timeout errors with jittered
+// exponential backoff or the delay asked by the Retry-After header. The response of the last attempt is returned, thus
+// the callers still check its status code. Every exit records the attempt on the circuit breaker, thus the probe slot
+// of a half-open breaker is always released.
 func (conn *connection) Do(req *http.Request) (*http.Response, error) {
-	return conn.client.Do(req)
+	if err := conn.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := conn.limiter.wait(req.Context()); err != nil {
+			conn.breaker.record(false)
+			return nil, err
+		}
+
+		res, err := conn.client.Do(req)
+		retryable, delay, retryAfter := conn.retryable(res, err, attempt)
+		if !retryable {
+			conn.breaker.record(err == nil && res.StatusCode < http.StatusInternalServerError &&
+				res.StatusCode != http.StatusTooManyRequests)
+			return res, err
+		}
+
+		// give up if the data provider asks for a longer pause than we are willing to wait, or on the last attempt.
+		giveUp := delay > maxRetryDelay || attempt >= conn.maxRetries || !rewindable(req)
+		if giveUp {
+			conn.breaker.record(false)
+		}
+
+		// the data provider asks for a pause, stop calling it until then, even if the call gives up retrying.
+		if retryAfter {
+			conn.breaker.holdUntil(time.Now().Add(delay))
+		}
+
+		if giveUp {
+			return res, err
+		}
+
+		if res != nil {
+			io.Copy(io.Discard, res.Body) // nolint
+			res.Body.Close()
+		}
+
+		if req.GetBody != nil {
+			body, e := req.GetBody()
+			if e != nil {
+				conn.breaker.record(false)
+				return nil, e
+			}
+			req.Body = body
+		}
+
+		select {
+		case <-req.Context().Done():
+			conn.breaker.record(false)
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// retryable checks if the attempt failed on 5xx, 429 or timeout errors, and it returns the delay before the next one,
+// and whether the delay was asked by the Retry-After header of the data provider.
+func (conn *connection) retryable(res *http.Response, err error, attempt int) (bool, time.Duration, bool) {
+	// the backoff is capped by the max retry delay, thus only the Retry-After of the data provider can exceed it.
+	backoff := maxRetryDelay
+	if attempt < 16 && conn.retryDelay<<attempt < maxRetryDelay {
+		backoff = conn.retryDelay << attempt
+	}
+	// full jitter in between [backoff/2, backoff) to spread the retries of the plugins.
+	backoff = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)) // nolint
+
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout(), backoff, false
+	}
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		if after, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return true, after, true
+		}
+		return true, backoff, false
+	case res.StatusCode >= http.StatusInternalServerError:
+		return true, backoff, false
+	default:
+		return false, 0, false
+	}
+}
+
+// rewindable checks if the request body can be sent again.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// parseRetryAfter parses the Retry-After header in either delay seconds or HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		delay := time.Until(t)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
 }
 
 type Client struct {
@@ -59,6 +228,18 @@ func NewClient(apiKey string, timeOut time.Duration, host string) *Client {
 	return NewClientConnection(apiKey, NewConnection(timeOut, host))
 }
 
+// NewClientFromConf creates the client of the plugin's data provider with the proxy, TLS and HTTP policy of the plugin
+// config. It panics on an invalid proxy or TLS config, which is rejected by ResolveConf on the plugin's start.
+func NewClientFromConf(conf *config.PluginConfig) *Client {
+	transport, err := NewHTTPTransport(conf)
+	if err != nil {
+		panic(fmt.Sprintf("invalid network config of plugin %s: %s", conf.Name, err.Error()))
+	}
+
+	connection := NewConnectionWithPolicy(time.Second*time.Duration(conf.Timeout), conf.Endpoint, transport, NewHTTPPolicy(conf))
+	return NewClientConnection(conf.Key, connection)
+}
+
 func NewClientConnection(apiKey string, connection Connection) *Client {
 	return &Client{
 		Conn:   connection,
@@ -0,0 +1,316 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestConnection creates a connection to the server with a short retry delay.
+func newTestConnection(server *httptest.Server, timeout time.Duration, policy HTTPPolicy) *connection {
+	conn := NewConnectionWithPolicy(timeout, strings.TrimPrefix(server.URL, "http://"), nil, policy).(*connection)
+	conn.retryDelay = 10 * time.Millisecond
+	return conn
+}
+
+func request(conn *connection) (int, error) {
+	res, err := conn.Request("http", &url.URL{Path: "/price"})
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	return res.StatusCode, nil
+}
+
+func TestNewHTTPPolicy(t *testing.T) {
+	policy := NewHTTPPolicy(&config.PluginConfig{})
+	require.Equal(t, HTTPPolicy{RateBurst: 1, BreakerCooldown: DefaultBreakerCooldown * time.Second}, policy)
+
+	policy = NewHTTPPolicy(&config.PluginConfig{RateLimit: 0.5, RateBurst: 5, MaxRetries: 3, BreakerThreshold: 4,
+		BreakerCooldown: 10})
+	require.Equal(t, HTTPPolicy{RateLimit: 0.5, RateBurst: 5, MaxRetries: 3, BreakerThreshold: 4,
+		BreakerCooldown: 10 * time.Second}, policy)
+}
+
+func TestConnectionRetry(t *testing.T) {
+	t.Run("retry on 5xx until success", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		code, err := request(newTestConnection(server, time.Second, HTTPPolicy{MaxRetries: 2}))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("return the last response once retries are exhausted", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		code, err := request(newTestConnection(server, time.Second, HTTPPolicy{MaxRetries: 2}))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, code)
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("no retry on 4xx", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		code, err := request(newTestConnection(server, time.Second, HTTPPolicy{MaxRetries: 2}))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, code)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("retry on timeout", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		code, err := request(newTestConnection(server, 100*time.Millisecond, HTTPPolicy{MaxRetries: 1}))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("retry after the delay asked by 429", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		start := time.Now()
+		code, err := request(newTestConnection(server, time.Second, HTTPPolicy{MaxRetries: 1}))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+		require.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+
+	t.Run("hold the requests on a long Retry-After", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		conn := newTestConnection(server, time.Second, HTTPPolicy{MaxRetries: 3})
+		code, err := request(conn)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusTooManyRequests, code)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		_, err = request(conn)
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("hold the requests on a short Retry-After without retries", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		conn := newTestConnection(server, time.Second, HTTPPolicy{})
+		code, err := request(conn)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusTooManyRequests, code)
+
+		// the next tick doesn't call the data provider before the Retry-After expires.
+		_, err = request(conn)
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		time.Sleep(1100 * time.Millisecond)
+		code, err = request(conn)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+}
+
+func TestConnectionCircuitBreaker(t *testing.T) {
+	var calls int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conn := newTestConnection(server, time.Second, HTTPPolicy{BreakerThreshold: 2, BreakerCooldown: 100 * time.Millisecond})
+	for i := 0; i < 2; i++ {
+		code, err := request(conn)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+	}
+
+	// the breaker is open during the cooldown period.
+	_, err := request(conn)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// the failed probe opens it again.
+	time.Sleep(150 * time.Millisecond)
+	code, err := request(conn)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, code)
+	_, err = request(conn)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// the successful probe closes it.
+	healthy.Store(true)
+	time.Sleep(150 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		code, err = request(conn)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	}
+	require.Equal(t, int32(6), atomic.LoadInt32(&calls))
+}
+
+func TestCircuitBreakerProbeRelease(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conn := newTestConnection(server, time.Second, HTTPPolicy{RateLimit: 5, RateBurst: 1, BreakerThreshold: 1,
+		BreakerCooldown: 50 * time.Millisecond})
+	code, err := request(conn)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, code)
+
+	// the probe gives up on the rate limiter with its context, it must not keep the probe slot.
+	time.Sleep(60 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/price", nil)
+	require.NoError(t, err)
+	_, err = conn.Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	time.Sleep(60 * time.Millisecond)
+	code, err = request(conn)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCircuitBreakerProbeReleaseOnRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	// the backoff of the late retries is capped by the max retry delay, rather than giving up on it.
+	conn := newTestConnection(server, time.Second, HTTPPolicy{MaxRetries: 6, BreakerThreshold: 1,
+		BreakerCooldown: 50 * time.Millisecond})
+	res := &http.Response{StatusCode: http.StatusInternalServerError}
+	for attempt := 0; attempt < 64; attempt++ {
+		retryable, delay, _ := NewConnection(time.Second, "").(*connection).retryable(res, nil, attempt)
+		require.True(t, retryable)
+		require.LessOrEqual(t, delay, maxRetryDelay)
+		require.Positive(t, delay)
+	}
+
+	// every request exhausts its retries on the failing data source, each probe must release its slot.
+	for i := 0; i < 3; i++ {
+		code, err := request(conn)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		_, err = request(conn)
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		time.Sleep(60 * time.Millisecond)
+	}
+	require.Equal(t, int32(21), atomic.LoadInt32(&calls))
+}
+
+func TestRateLimiter(t *testing.T) {
+	require.Nil(t, newRateLimiter(0, 1))
+
+	limiter := newRateLimiter(20, 2)
+	start := time.Now()
+	// the burst is served immediately, then the requests are spaced by 50ms.
+	for i := 0; i < 4; i++ {
+		require.NoError(t, limiter.wait(context.Background()))
+	}
+	elapsed := time.Since(start)
+	require.GreaterOrEqual(t, elapsed, 90*time.Millisecond)
+	require.Less(t, elapsed, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter = newRateLimiter(0.1, 1)
+	require.NoError(t, limiter.wait(ctx))
+	require.ErrorIs(t, limiter.wait(ctx), context.Canceled)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("120")
+	require.True(t, ok)
+	require.Equal(t, 120*time.Second, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	require.Greater(t, d, 59*time.Minute)
+
+	_, ok = parseRetryAfter("")
+	require.False(t, ok)
+	_, ok = parseRetryAfter("soon")
+	require.False(t, ok)
+}
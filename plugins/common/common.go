@@ -30,6 +30,8 @@ var (
 	NTNUSDCSymbol        = "NTN-USDC"
 	DefaultCryptoSymbols = []string{ATNUSDCSymbol, NTNUSDCSymbol, NTNATNSymbol}
 	DefaultUSDCSymbol    = "USDC-USD"
+	USDTUSDSymbol        = "USDT-USD"
+	USDCUSDTSymbol       = "USDC-USDT"
 	ErrDataNotAvailable  = fmt.Errorf("data is not available")
 	ErrKnownSymbols      = fmt.Errorf("the data source does not have all the data asked by oracle server")
 	ErrAccessLimited     = fmt.Errorf("access rate is limited, please check your subscription from data provider")
@@ -41,12 +43,25 @@ const (
 	AutonityCryptoDecimals       = 18 // both NTN and the Wrapped ATN take 18 as the decimal.
 	USDCDecimals                 = 6  // the decimal of USDC coin in autonity L1 network.
 	CryptoToUsdcDecimals         = 18 // the data precision in oracle contract.
+
+	DefaultAMMBackfillBlocks = 300 // the swaps of recent 300 blocks are backfilled into the AMM order book on start.
+	UniswapV2                = 2
+	UniswapV3                = 3
+	DefaultV3FeeTier         = 3000 // 0.3%, the fee tier of the uniswap V3 pools.
+	DefaultV3TWAPWindow      = 60   // 60s, the window of the TWAP read from the uniswap V3 pools.
 )
 
 type Price struct {
-	Symbol string `json:"symbol,omitempty"`
-	Price  string `json:"price,omitempty"`
-	Volume string `json:"volume,omitempty"` // recent accumulating trade volume in USDCx.
+	Symbol    string `json:"symbol,omitempty"`
+	Price     string `json:"price,omitempty"`
+	Volume    string `json:"volume,omitempty"`    // recent accumulating trade volume in USDCx.
+	Timestamp int64  `json:"timestamp,omitempty"` // unix TS of the quote given by the data source, 0 if it is not provided.
+}
+
+// cachedPrice is a buffered price with the TS on when it was fetched from the data source.
+type cachedPrice struct {
+	price     types.Price
+	fetchedAt int64
 }
 
 type Prices []Price
@@ -58,7 +73,7 @@ type Plugin struct {
 	logger           hclog.Logger
 	client           DataSourceClient
 	conf             *config.PluginConfig
-	cachePrices      map[string]types.Price
+	cachePrices      map[string]cachedPrice
 	chainID          *big.Int // piccadilly, bakerloo, mainnet, or nil for common.
 	dataSourceType   types.DataSourceType
 }
@@ -77,7 +92,7 @@ func NewPlugin(conf *config.PluginConfig, client DataSourceClient, version strin
 		client:           client,
 		conf:             conf,
 		availableSymbols: make(map[string]struct{}),
-		cachePrices:      make(map[string]types.Price),
+		cachePrices:      make(map[string]cachedPrice),
 		chainID:          chainID,
 		dataSourceType:   srcType,
 	}
@@ -121,13 +136,19 @@ func (p *Plugin) FetchPrices(symbols []string) (types.PluginPriceReport, error)
 			continue
 		}
 
+		// take the quote TS from data source if there is one, otherwise the TS of the fetching is used.
+		quoteTS := v.Timestamp
+		if quoteTS == 0 {
+			quoteTS = now
+		}
+
 		pr := types.Price{
-			Timestamp: now,
+			Timestamp: quoteTS,
 			Symbol:    availableSymMap[v.Symbol], // set the symbol with the symbol style used in oracle server side.
 			Price:     decPrice,
 			Volume:    decVol,
 		}
-		p.cachePrices[v.Symbol] = pr
+		p.cachePrices[v.Symbol] = cachedPrice{price: pr, fetchedAt: now}
 		report.Prices = append(report.Prices, pr)
 	}
 	report.UnRecognizableSymbols = unRecognizableSymbols
@@ -203,16 +224,16 @@ func (p *Plugin) fetchPricesFromCache(availableSymbols []string) ([]types.Price,
 	var prices []types.Price
 	now := time.Now().Unix()
 	for _, s := range availableSymbols {
-		pr, ok := p.cachePrices[s]
+		cp, ok := p.cachePrices[s]
 		if !ok {
 			return nil, fmt.Errorf("no data buffered")
 		}
 
-		if now-pr.Timestamp >= int64(p.conf.DataUpdateInterval) {
+		if now-cp.fetchedAt >= int64(p.conf.DataUpdateInterval) {
 			return nil, fmt.Errorf("data is too old")
 		}
 
-		prices = append(prices, pr)
+		prices = append(prices, cp.price)
 	}
 	return prices, nil
 }
@@ -280,25 +301,84 @@ func ResolveConf(cmd string, defConf *config.PluginConfig) *config.PluginConfig
 		conf.Name = defConf.Name
 	}
 
-	if len(conf.NTNTokenAddress) == 0 {
-		conf.NTNTokenAddress = defConf.NTNTokenAddress
-	}
-
-	if len(conf.ATNTokenAddress) == 0 {
-		conf.ATNTokenAddress = defConf.ATNTokenAddress
+	if len(conf.NTNTokenAddress) != 0 || len(conf.ATNTokenAddress) != 0 || len(conf.USDCTokenAddress) != 0 {
+		pairs, err := migrateLegacyTokens(conf, defConf.Pairs)
+		if err != nil {
+			println("invalid pairs conf: ", err.Error(), cmd)
+			os.Exit(-1)
+		}
+		conf.Pairs = pairs
 	}
 
-	if len(conf.USDCTokenAddress) == 0 {
-		conf.USDCTokenAddress = defConf.USDCTokenAddress
+	if len(conf.Pairs) == 0 {
+		conf.Pairs = defConf.Pairs
 	}
 
 	if len(conf.SwapAddress) == 0 {
 		conf.SwapAddress = defConf.SwapAddress
 	}
 
+	if conf.BackfillBlocks == 0 {
+		conf.BackfillBlocks = defConf.BackfillBlocks
+	}
+
+	if conf.PoolVersion == 0 {
+		conf.PoolVersion = defConf.PoolVersion
+	}
+
+	if conf.FeeTier == 0 {
+		conf.FeeTier = defConf.FeeTier
+	}
+
+	if conf.TWAPWindow == 0 {
+		conf.TWAPWindow = defConf.TWAPWindow
+	}
+
+	if err = ValidateNetConf(conf); err != nil {
+		println("invalid network conf: ", err.Error(), cmd)
+		os.Exit(-1)
+	}
+
 	return conf
 }
 
+// migrateLegacyTokens migrates the deprecated token addresses of the plugin config into the pairs, the addresses replace
+// the tokens of the default pairs, or of the ATN-USDC and NTN-USDC pairs if there is no default one. The pairs which are
+// still without both tokens are dropped, and the deprecated addresses cannot be set together with the pairs.
+func migrateLegacyTokens(conf *config.PluginConfig, defaults []config.PairConfig) ([]config.PairConfig, error) {
+	if len(conf.Pairs) != 0 {
+		return nil, fmt.Errorf("the deprecated token addresses cannot be set together with the pairs")
+	}
+
+	tokens := map[string]string{"NTN": conf.NTNTokenAddress, "ATN": conf.ATNTokenAddress, "USDC": conf.USDCTokenAddress}
+	if len(defaults) == 0 {
+		defaults = []config.PairConfig{{Symbol: ATNUSDCSymbol}, {Symbol: NTNUSDCSymbol}}
+	}
+
+	var pairs []config.PairConfig
+	for _, p := range defaults {
+		currencies := strings.Split(p.Symbol, "-")
+		if len(currencies) != 2 {
+			continue
+		}
+		if token := tokens[currencies[0]]; len(token) != 0 {
+			p.BaseToken = token
+		}
+		if token := tokens[currencies[1]]; len(token) != 0 {
+			p.QuoteToken = token
+		}
+		if len(p.BaseToken) == 0 || len(p.QuoteToken) == 0 {
+			continue
+		}
+		pairs = append(pairs, p)
+	}
+
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("no pair is resolved from the deprecated token addresses")
+	}
+	return pairs, nil
+}
+
 // PluginServe doesn't return until the plugin is done being executed.
 func PluginServe(p *Plugin) {
 	var pluginMap = map[string]plugin.Plugin{
@@ -346,6 +426,20 @@ func ComputeDerivedPrice(ntnUSD, atnUSD string) (Price, error) {
 	return priceNTNATN, nil
 }
 
+// ToVolume converts the decimal trade volume reported by a data source to the integer volume taken by oracle server,
+// the fraction is truncated.
+func ToVolume(volume string) (string, error) {
+	v, err := decimal.NewFromString(volume)
+	if err != nil {
+		return "", err
+	}
+
+	if v.IsNegative() {
+		return "", fmt.Errorf("negative volume: %s", volume)
+	}
+	return v.BigInt().String(), nil
+}
+
 var ForexCurrencies = map[string]struct{}{
 	"AUD-USD": {},
 	"CAD-USD": {},
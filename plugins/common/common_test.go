@@ -1,8 +1,11 @@
 package common
 
 import (
+	"autonity-oracle/config"
+	"autonity-oracle/types"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestConvertSymbol(t *testing.T) {
@@ -32,3 +35,96 @@ func TestResolveSeparator(t *testing.T) {
 	symbol = "BTCUSD"
 	require.Equal(t, "", ResolveSeparator(symbol))
 }
+
+// quoteClient is a data source client that quotes prices with a fixed TS.
+type quoteClient struct {
+	quoteTS int64
+	fetched int
+}
+
+func (c *quoteClient) AvailableSymbols() ([]string, error) {
+	return []string{"EUR-USD", "JPY-USD"}, nil
+}
+
+func (c *quoteClient) FetchPrice(symbols []string) (Prices, error) {
+	c.fetched++
+	var prices Prices
+	for _, s := range symbols {
+		prices = append(prices, Price{Symbol: s, Price: "1.08", Volume: "1", Timestamp: c.quoteTS})
+	}
+	return prices, nil
+}
+
+func (c *quoteClient) KeyRequired() bool {
+	return false
+}
+
+func (c *quoteClient) Close() {}
+
+func TestFetchPricesWithQuoteTimestamp(t *testing.T) {
+	quoteTS := time.Now().Unix() - 3600
+	client := &quoteClient{quoteTS: quoteTS}
+	p := NewPlugin(&config.PluginConfig{Name: "test", DataUpdateInterval: 30}, client, "v0.0.1", types.SrcCEX, nil)
+	_, err := p.State(0)
+	require.NoError(t, err)
+
+	report, err := p.FetchPrices([]string{"EUR-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(report.Prices))
+	require.Equal(t, quoteTS, report.Prices[0].Timestamp)
+
+	// the cached price is served with the quote TS, even though the quote is older than the refresh interval.
+	report, err = p.FetchPrices([]string{"EUR-USD"})
+	require.NoError(t, err)
+	require.Equal(t, quoteTS, report.Prices[0].Timestamp)
+	require.Equal(t, 1, client.fetched)
+
+	// the fetching TS is taken if the data source does not quote a TS.
+	client.quoteTS = 0
+	now := time.Now().Unix()
+	report, err = p.FetchPrices([]string{"JPY-USD"})
+	require.NoError(t, err)
+	require.LessOrEqual(t, now, report.Prices[0].Timestamp)
+}
+
+func TestToVolume(t *testing.T) {
+	v, err := ToVolume("123456.789")
+	require.NoError(t, err)
+	require.Equal(t, "123456", v)
+
+	v, err = ToVolume("0.5")
+	require.NoError(t, err)
+	require.Equal(t, "0", v)
+
+	_, err = ToVolume("-1")
+	require.Error(t, err)
+
+	_, err = ToVolume("abc")
+	require.Error(t, err)
+}
+
+func TestMigrateLegacyTokens(t *testing.T) {
+	defaults := []config.PairConfig{
+		{Symbol: ATNUSDCSymbol, BaseToken: "0xA", QuoteToken: "0xU", BaseDecimals: 18, QuoteDecimals: 6},
+		{Symbol: NTNUSDCSymbol, BaseToken: "0xN", QuoteToken: "0xU", BaseDecimals: 18, QuoteDecimals: 6},
+	}
+
+	// the legacy addresses replace the tokens of the default pairs.
+	pairs, err := migrateLegacyTokens(&config.PluginConfig{NTNTokenAddress: "0x1", USDCTokenAddress: "0x3"}, defaults)
+	require.NoError(t, err)
+	require.Equal(t, []config.PairConfig{
+		{Symbol: ATNUSDCSymbol, BaseToken: "0xA", QuoteToken: "0x3", BaseDecimals: 18, QuoteDecimals: 6},
+		{Symbol: NTNUSDCSymbol, BaseToken: "0x1", QuoteToken: "0x3", BaseDecimals: 18, QuoteDecimals: 6},
+	}, pairs)
+
+	// without default pairs, the pairs with both tokens set are taken.
+	pairs, err = migrateLegacyTokens(&config.PluginConfig{ATNTokenAddress: "0x2", USDCTokenAddress: "0x3"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []config.PairConfig{{Symbol: ATNUSDCSymbol, BaseToken: "0x2", QuoteToken: "0x3"}}, pairs)
+
+	_, err = migrateLegacyTokens(&config.PluginConfig{ATNTokenAddress: "0x2"}, nil)
+	require.Error(t, err)
+
+	_, err = migrateLegacyTokens(&config.PluginConfig{ATNTokenAddress: "0x2", Pairs: defaults}, defaults)
+	require.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+// NewTLSConfig returns the TLS config which trusts the extra CA bundle on top of the system roots, and presents the
+// client certificate for mTLS, nil is returned if neither of them is configured.
+func NewTLSConfig(conf *config.PluginConfig) (*tls.Config, error) {
+	if conf.CACert == "" && conf.ClientCert == "" && conf.ClientKey == "" {
+		return nil, nil
+	}
+
+	tlsConf := &tls.Config{MinVersion: tls.VersionTLS12}
+	if conf.CACert != "" {
+		pem, err := os.ReadFile(conf.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificate in CA bundle %s", conf.CACert)
+		}
+		tlsConf.RootCAs = pool
+	}
+
+	if conf.ClientCert != "" || conf.ClientKey != "" {
+		if conf.ClientCert == "" || conf.ClientKey == "" {
+			return nil, fmt.Errorf("both clientCert and clientKey are required for mTLS")
+		}
+		cert, err := tls.LoadX509KeyPair(conf.ClientCert, conf.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %w", err)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConf, nil
+}
+
+// NewProxy returns the proxy function of the configured HTTP(S) or SOCKS5 proxy, the proxy is resolved from the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables if it is not configured.
+func NewProxy(conf *config.PluginConfig) (func(*http.Request) (*url.URL, error), error) {
+	if conf.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxy, err := url.Parse(conf.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+
+	switch proxy.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme: %s", proxy.Scheme)
+	}
+	return http.ProxyURL(proxy), nil
+}
+
+// ValidateNetConf checks the proxy and TLS configurations of the plugin config.
+func ValidateNetConf(conf *config.PluginConfig) error {
+	if _, err := NewProxy(conf); err != nil {
+		return err
+	}
+	_, err := NewTLSConfig(conf)
+	return err
+}
+
+// NewHTTPTransport returns a transport with the defaults of http.DefaultTransport, and the proxy and TLS
+// configurations of the plugin config.
+func NewHTTPTransport(conf *config.PluginConfig) (*http.Transport, error) {
+	proxy, err := NewProxy(conf)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, err := NewTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	if tlsConf != nil {
+		transport.TLSClientConfig = tlsConf
+	}
+	return transport, nil
+}
+
+// NewWSDialer returns a websocket dialer with the proxy and TLS configurations of the plugin config.
+func NewWSDialer(conf *config.PluginConfig) (*websocket.Dialer, error) {
+	proxy, err := NewProxy(conf)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, err := NewTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &websocket.Dialer{
+		Proxy:            proxy,
+		TLSClientConfig:  tlsConf,
+		HandshakeTimeout: wsHandshakeTimeout,
+	}, nil
+}
+
+// DialEthClient dials the L1 node at the scheme and endpoint of the plugin config over http(s) or ws(s), with the
+// proxy and TLS configurations of the plugin config.
+func DialEthClient(conf *config.PluginConfig) (*ethclient.Client, error) {
+	transport, err := NewHTTPTransport(conf)
+	if err != nil {
+		return nil, err
+	}
+	dialer, err := NewWSDialer(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := rpc.DialOptions(context.Background(), conf.Scheme+"://"+conf.Endpoint,
+		rpc.WithHTTPClient(&http.Client{Transport: transport}), rpc.WithWebsocketDialer(*dialer))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
@@ -0,0 +1,149 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeClientCert generates a self-signed client certificate, and writes the certificate and its key in PEM files.
+func writeClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "oracle"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return cert, certFile, keyFile
+}
+
+func TestNewHTTPTransportWithMTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, certFile, keyFile := writeClientCert(t, dir)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "oracle" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	// the CA bundle trusts the certificate of the test server.
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE",
+		Bytes: server.Certificate().Raw}), 0600))
+
+	conf := &config.PluginConfig{
+		Name:       "test",
+		Endpoint:   strings.TrimPrefix(server.URL, "https://"),
+		Timeout:    5,
+		CACert:     caFile,
+		ClientCert: certFile,
+		ClientKey:  keyFile,
+	}
+	client := NewClientFromConf(conf)
+	defer client.Conn.Close()
+	res, err := client.Conn.Request("https", &url.URL{Path: "/"})
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// the handshake fails without the client certificate.
+	conf.ClientCert, conf.ClientKey = "", ""
+	client = NewClientFromConf(conf)
+	defer client.Conn.Close()
+	_, err = client.Conn.Request("https", &url.URL{Path: "/"})
+	require.Error(t, err)
+}
+
+func TestNewHTTPTransportWithProxy(t *testing.T) {
+	var proxied int32
+	// the proxy serves the plain http requests on behalf of the data source.
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "data.source.invalid" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		atomic.AddInt32(&proxied, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	client := NewClientFromConf(&config.PluginConfig{Endpoint: "data.source.invalid", Timeout: 5, ProxyURL: proxy.URL})
+	defer client.Conn.Close()
+	res, err := client.Conn.Request("http", &url.URL{Path: "/price"})
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(&proxied))
+
+	dialer, err := NewWSDialer(&config.PluginConfig{ProxyURL: "socks5://127.0.0.1:1080"})
+	require.NoError(t, err)
+	u, err := dialer.Proxy(&http.Request{URL: &url.URL{Scheme: "wss", Host: "ws.kraken.com"}})
+	require.NoError(t, err)
+	require.Equal(t, "socks5://127.0.0.1:1080", u.String())
+}
+
+func TestValidateNetConf(t *testing.T) {
+	dir := t.TempDir()
+	_, certFile, keyFile := writeClientCert(t, dir)
+	invalidPEM := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidPEM, []byte("not a certificate"), 0600))
+
+	require.NoError(t, ValidateNetConf(&config.PluginConfig{}))
+	require.NoError(t, ValidateNetConf(&config.PluginConfig{ProxyURL: "http://proxy:3128", CACert: certFile,
+		ClientCert: certFile, ClientKey: keyFile}))
+
+	for _, conf := range []*config.PluginConfig{
+		{ProxyURL: "ftp://proxy:21"},
+		{ProxyURL: "://proxy"},
+		{CACert: filepath.Join(dir, "missing.pem")},
+		{CACert: invalidPEM},
+		{ClientCert: certFile},
+		{ClientCert: certFile, ClientKey: invalidPEM},
+	} {
+		require.Error(t, ValidateNetConf(conf), "%+v", conf)
+	}
+}
@@ -0,0 +1,280 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	DefaultWSHeartbeat  = 10 * time.Second // the interval to ping the data source, it is dropped if nothing is read in 3 intervals.
+	DefaultWSMaxBackoff = 30 * time.Second // the max delay between the reconnection attempts.
+	wsWriteTimeout      = 5 * time.Second
+	wsMissedHeartbeats  = 3
+	wsInitialBackoff    = time.Second
+	wsHandshakeTimeout  = 10 * time.Second
+)
+
+// DefaultRESTFallbackInterval is the interval of the REST API queries while the websocket feed is down, it is the polling
+// interval of the plugins before the websocket feeds, which was tested and passed the rate limit policy.
+const DefaultRESTFallbackInterval = 30 * time.Second
+
+// WSHandler implements the protocol of a data source's websocket market data feed.
+type WSHandler interface {
+	// Subscriptions returns the messages to subscribe the channels of the markets, they are sent on every connection.
+	Subscriptions() []interface{}
+	// Heartbeat returns the application level ping message, nil if the data source takes the websocket ping frames.
+	Heartbeat() interface{}
+	// Handle parses a message of the feed, and returns the latest trades or tickers it carries, the symbol of the
+	// returned price is the data source's market identifier.
+	Handle(msg []byte) (Prices, error)
+}
+
+// WSClient keeps a websocket connection to the data source with heartbeat and reconnection, and it maintains an in-memory
+// book of the latest trade or ticker per market from the subscribed channels. The book is cleared on disconnection, thus
+// it only serves the prices which are live on the current connection.
+type WSClient struct {
+	url       string
+	handler   WSHandler
+	heartbeat time.Duration
+	logger    hclog.Logger
+	dialer    *websocket.Dialer
+
+	bookMutex sync.RWMutex
+	book      map[string]Price
+	connected bool
+
+	connMutex sync.Mutex
+	conn      *websocket.Conn
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewWSClient creates the client of the websocket feed, the dialer carries the proxy and TLS configurations, a default
+// one is taken if it is nil.
+func NewWSClient(url string, handler WSHandler, heartbeat time.Duration, dialer *websocket.Dialer, logger hclog.Logger) *WSClient {
+	if heartbeat <= 0 {
+		heartbeat = DefaultWSHeartbeat
+	}
+
+	if dialer == nil {
+		dialer = &websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: wsHandshakeTimeout}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WSClient{
+		url:       url,
+		handler:   handler,
+		heartbeat: heartbeat,
+		logger:    logger,
+		dialer:    dialer,
+		book:      make(map[string]Price),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Start connects to the data source in the background, it keeps reconnecting until the client is closed.
+func (c *WSClient) Start() {
+	c.wg.Add(1)
+	go c.run()
+}
+
+// Latest returns the latest price of the market on the live connection.
+func (c *WSClient) Latest(market string) (Price, error) {
+	c.bookMutex.RLock()
+	defer c.bookMutex.RUnlock()
+	if !c.connected {
+		return Price{}, ErrDataNotAvailable
+	}
+
+	p, ok := c.book[market]
+	if !ok {
+		return Price{}, ErrDataNotAvailable
+	}
+	return p, nil
+}
+
+func (c *WSClient) Close() {
+	c.cancel()
+	c.connMutex.Lock()
+	if c.conn != nil {
+		c.conn.Close() // nolint
+	}
+	c.connMutex.Unlock()
+	c.wg.Wait()
+}
+
+func (c *WSClient) run() {
+	defer c.wg.Done()
+	backoff := wsInitialBackoff
+	for {
+		start := time.Now()
+		if err := c.serve(); err != nil {
+			c.logger.Info("websocket connection dropped", "url", c.url, "error", err.Error())
+		}
+		c.reset()
+
+		// the backoff is reset once a connection was kept for a while.
+		if time.Since(start) > DefaultWSMaxBackoff {
+			backoff = wsInitialBackoff
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > DefaultWSMaxBackoff {
+			backoff = DefaultWSMaxBackoff
+		}
+	}
+}
+
+// serve dials the data source, subscribes the channels and reads the feed until the connection is dropped.
+func (c *WSClient) serve() error {
+	conn, _, err := c.dialer.DialContext(c.ctx, c.url, nil)
+	if err != nil {
+		return err
+	}
+
+	c.connMutex.Lock()
+	if c.ctx.Err() != nil {
+		c.connMutex.Unlock()
+		conn.Close() // nolint
+		return nil
+	}
+	c.conn = conn
+	c.connMutex.Unlock()
+	defer conn.Close() // nolint
+
+	for _, sub := range c.handler.Subscriptions() {
+		if err = c.write(conn, sub); err != nil {
+			return fmt.Errorf("subscribe: %w", err)
+		}
+	}
+
+	c.bookMutex.Lock()
+	c.connected = true
+	c.bookMutex.Unlock()
+	c.logger.Info("websocket connected", "url", c.url)
+
+	deadline := c.heartbeat * wsMissedHeartbeats
+	conn.SetReadDeadline(time.Now().Add(deadline)) // nolint
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(deadline))
+	})
+
+	stopPing := make(chan struct{})
+	defer close(stopPing)
+	go c.ping(conn, stopPing)
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		conn.SetReadDeadline(time.Now().Add(deadline)) // nolint
+
+		prices, err := c.handler.Handle(msg)
+		if err != nil {
+			c.logger.Debug("cannot handle websocket message", "error", err.Error(), "msg", string(msg))
+			continue
+		}
+
+		if len(prices) == 0 {
+			continue
+		}
+		c.bookMutex.Lock()
+		for _, p := range prices {
+			c.book[p.Symbol] = p
+		}
+		c.bookMutex.Unlock()
+	}
+}
+
+// ping sends the heartbeat per interval, the connection is closed if it cannot be sent.
+func (c *WSClient) ping(conn *websocket.Conn, stop chan struct{}) {
+	ticker := time.NewTicker(c.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			var err error
+			if hb := c.handler.Heartbeat(); hb != nil {
+				err = c.write(conn, hb)
+			} else {
+				c.connMutex.Lock()
+				err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+				c.connMutex.Unlock()
+			}
+			if err != nil {
+				c.logger.Info("cannot send websocket heartbeat", "error", err.Error())
+				conn.Close() // nolint
+				return
+			}
+		}
+	}
+}
+
+func (c *WSClient) write(conn *websocket.Conn, msg interface{}) error {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)) // nolint
+	return conn.WriteJSON(msg)
+}
+
+// reset clears the book as the prices are not live anymore once the connection is dropped.
+func (c *WSClient) reset() {
+	c.bookMutex.Lock()
+	c.connected = false
+	c.book = make(map[string]Price)
+	c.bookMutex.Unlock()
+
+	c.connMutex.Lock()
+	c.conn = nil
+	c.connMutex.Unlock()
+}
+
+// RESTFallback queries the REST API of the data source in place of its websocket feed, the queries are throttled by
+// the interval apart from the cadence of the websocket book reads, and the result of the last query is served between
+// them.
+type RESTFallback struct {
+	interval time.Duration
+	fetch    func() (Prices, error)
+
+	lock      sync.Mutex
+	prices    Prices
+	err       error
+	fetchedAt time.Time
+}
+
+func NewRESTFallback(interval time.Duration, fetch func() (Prices, error)) *RESTFallback {
+	if interval <= 0 {
+		interval = DefaultRESTFallbackInterval
+	}
+	return &RESTFallback{interval: interval, fetch: fetch}
+}
+
+// Fetch queries the REST API if the interval passed since the last query, otherwise it returns the last result.
+func (f *RESTFallback) Fetch() (Prices, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if !f.fetchedAt.IsZero() && time.Since(f.fetchedAt) < f.interval {
+		return f.prices, f.err
+	}
+
+	f.prices, f.err = f.fetch()
+	f.fetchedAt = time.Now()
+	return f.prices, f.err
+}
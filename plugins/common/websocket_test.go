@@ -0,0 +1,129 @@
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+// testHandler is a feed of {"market": "X", "price": "1"} messages.
+type testHandler struct{}
+
+func (h *testHandler) Subscriptions() []interface{} {
+	return []interface{}{map[string]string{"op": "subscribe"}}
+}
+
+func (h *testHandler) Heartbeat() interface{} {
+	return map[string]string{"op": "ping"}
+}
+
+func (h *testHandler) Handle(msg []byte) (Prices, error) {
+	var m struct {
+		Market string `json:"market"`
+		Price  string `json:"price"`
+	}
+	if err := json.Unmarshal(msg, &m); err != nil {
+		return nil, err
+	}
+	if m.Market == "" {
+		return nil, nil
+	}
+	return Prices{{Symbol: m.Market, Price: m.Price}}, nil
+}
+
+func TestWSClient(t *testing.T) {
+	var subscriptions, pings int32
+	dropCh := make(chan struct{}, 1)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		go func() {
+			<-dropCh
+			conn.Close()
+		}()
+
+		for {
+			var msg map[string]string
+			if err = conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			switch msg["op"] {
+			case "subscribe":
+				n := atomic.AddInt32(&subscriptions, 1)
+				price := "1.0001"
+				if n > 1 {
+					price = "1.0002"
+				}
+				conn.WriteJSON(map[string]string{"event": "subscribed"})               // nolint
+				conn.WriteJSON(map[string]string{"market": "USDCUSD", "price": price}) // nolint
+			case "ping":
+				atomic.AddInt32(&pings, 1)
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := NewWSClient("ws"+strings.TrimPrefix(server.URL, "http"), &testHandler{}, 100*time.Millisecond,
+		nil, hclog.NewNullLogger())
+	_, err := client.Latest("USDCUSD")
+	require.ErrorIs(t, err, ErrDataNotAvailable)
+
+	client.Start()
+	require.Eventually(t, func() bool {
+		p, err := client.Latest("USDCUSD")
+		return err == nil && p.Price == "1.0001"
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = client.Latest("BTCUSD")
+	require.ErrorIs(t, err, ErrDataNotAvailable)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&pings) > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// the book is cleared once the connection is dropped, and the client reconnects and re-subscribes.
+	dropCh <- struct{}{}
+	require.Eventually(t, func() bool {
+		_, err := client.Latest("USDCUSD")
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		p, err := client.Latest("USDCUSD")
+		return err == nil && p.Price == "1.0002"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(2), atomic.LoadInt32(&subscriptions))
+
+	client.Close()
+	_, err = client.Latest("USDCUSD")
+	require.ErrorIs(t, err, ErrDataNotAvailable)
+}
+
+func TestRESTFallback(t *testing.T) {
+	var calls int32
+	fallback := NewRESTFallback(100*time.Millisecond, func() (Prices, error) {
+		atomic.AddInt32(&calls, 1)
+		return Prices{{Symbol: "X", Price: "1"}}, nil
+	})
+
+	// the queries within the interval are served by the last result.
+	for i := 0; i < 3; i++ {
+		prices, err := fallback.Fetch()
+		require.NoError(t, err)
+		require.Equal(t, "1", prices[0].Price)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	time.Sleep(150 * time.Millisecond)
+	_, err := fallback.Fetch()
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,381 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package swaperc20
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SwapERC20MetaData contains all meta data concerning the SwapERC20 contract.
+var SwapERC20MetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"nonce\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"signerWallet\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"signerToken\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"signerAmount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"protocolFee\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"senderWallet\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"senderToken\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"senderAmount\",\"type\":\"uint256\"}],\"name\":\"SwapERC20\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"protocolFee\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// SwapERC20ABI is the input ABI used to generate the binding from.
+// Deprecated: Use SwapERC20MetaData.ABI instead.
+var SwapERC20ABI = SwapERC20MetaData.ABI
+
+// SwapERC20 is an auto generated Go binding around an Ethereum contract.
+type SwapERC20 struct {
+	SwapERC20Caller     // Read-only binding to the contract
+	SwapERC20Transactor // Write-only binding to the contract
+	SwapERC20Filterer   // Log filterer for contract events
+}
+
+// SwapERC20Caller is an auto generated read-only Go binding around an Ethereum contract.
+type SwapERC20Caller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SwapERC20Transactor is an auto generated write-only Go binding around an Ethereum contract.
+type SwapERC20Transactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SwapERC20Filterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SwapERC20Filterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SwapERC20Session is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SwapERC20Session struct {
+	Contract     *SwapERC20        // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SwapERC20CallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SwapERC20CallerSession struct {
+	Contract *SwapERC20Caller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts    // Call options to use throughout this session
+}
+
+// SwapERC20TransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SwapERC20TransactorSession struct {
+	Contract     *SwapERC20Transactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts    // Transaction auth options to use throughout this session
+}
+
+// SwapERC20Raw is an auto generated low-level Go binding around an Ethereum contract.
+type SwapERC20Raw struct {
+	Contract *SwapERC20 // Generic contract binding to access the raw methods on
+}
+
+// SwapERC20CallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SwapERC20CallerRaw struct {
+	Contract *SwapERC20Caller // Generic read-only contract binding to access the raw methods on
+}
+
+// SwapERC20TransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SwapERC20TransactorRaw struct {
+	Contract *SwapERC20Transactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSwapERC20 creates a new instance of SwapERC20, bound to a specific deployed contract.
+func NewSwapERC20(address common.Address, backend bind.ContractBackend) (*SwapERC20, error) {
+	contract, err := bindSwapERC20(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &SwapERC20{SwapERC20Caller: SwapERC20Caller{contract: contract}, SwapERC20Transactor: SwapERC20Transactor{contract: contract}, SwapERC20Filterer: SwapERC20Filterer{contract: contract}}, nil
+}
+
+// NewSwapERC20Caller creates a new read-only instance of SwapERC20, bound to a specific deployed contract.
+func NewSwapERC20Caller(address common.Address, caller bind.ContractCaller) (*SwapERC20Caller, error) {
+	contract, err := bindSwapERC20(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SwapERC20Caller{contract: contract}, nil
+}
+
+// NewSwapERC20Transactor creates a new write-only instance of SwapERC20, bound to a specific deployed contract.
+func NewSwapERC20Transactor(address common.Address, transactor bind.ContractTransactor) (*SwapERC20Transactor, error) {
+	contract, err := bindSwapERC20(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SwapERC20Transactor{contract: contract}, nil
+}
+
+// NewSwapERC20Filterer creates a new log filterer instance of SwapERC20, bound to a specific deployed contract.
+func NewSwapERC20Filterer(address common.Address, filterer bind.ContractFilterer) (*SwapERC20Filterer, error) {
+	contract, err := bindSwapERC20(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SwapERC20Filterer{contract: contract}, nil
+}
+
+// bindSwapERC20 binds a generic wrapper to an already deployed contract.
+func bindSwapERC20(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SwapERC20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SwapERC20 *SwapERC20Raw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SwapERC20.Contract.SwapERC20Caller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SwapERC20 *SwapERC20Raw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SwapERC20.Contract.SwapERC20Transactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SwapERC20 *SwapERC20Raw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SwapERC20.Contract.SwapERC20Transactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SwapERC20 *SwapERC20CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SwapERC20.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SwapERC20 *SwapERC20TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SwapERC20.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SwapERC20 *SwapERC20TransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SwapERC20.Contract.contract.Transact(opts, method, params...)
+}
+
+// ProtocolFee is a free data retrieval call binding the contract method 0xb0e21e8a.
+//
+// Solidity: function protocolFee() view returns(uint256)
+func (_SwapERC20 *SwapERC20Caller) ProtocolFee(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _SwapERC20.contract.Call(opts, &out, "protocolFee")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ProtocolFee is a free data retrieval call binding the contract method 0xb0e21e8a.
+//
+// Solidity: function protocolFee() view returns(uint256)
+func (_SwapERC20 *SwapERC20Session) ProtocolFee() (*big.Int, error) {
+	return _SwapERC20.Contract.ProtocolFee(&_SwapERC20.CallOpts)
+}
+
+// ProtocolFee is a free data retrieval call binding the contract method 0xb0e21e8a.
+//
+// Solidity: function protocolFee() view returns(uint256)
+func (_SwapERC20 *SwapERC20CallerSession) ProtocolFee() (*big.Int, error) {
+	return _SwapERC20.Contract.ProtocolFee(&_SwapERC20.CallOpts)
+}
+
+// SwapERC20SwapERC20Iterator is returned from FilterSwapERC20 and is used to iterate over the raw logs and unpacked data for SwapERC20 events raised by the SwapERC20 contract.
+type SwapERC20SwapERC20Iterator struct {
+	Event *SwapERC20SwapERC20 // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SwapERC20SwapERC20Iterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SwapERC20SwapERC20)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SwapERC20SwapERC20)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SwapERC20SwapERC20Iterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SwapERC20SwapERC20Iterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SwapERC20SwapERC20 represents a SwapERC20 event raised by the SwapERC20 contract.
+type SwapERC20SwapERC20 struct {
+	Nonce        *big.Int
+	SignerWallet common.Address
+	SignerToken  common.Address
+	SignerAmount *big.Int
+	ProtocolFee  *big.Int
+	SenderWallet common.Address
+	SenderToken  common.Address
+	SenderAmount *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterSwapERC20 is a free log retrieval operation binding the contract event 0xb651f2787ff61b5ab14f3936f2daebdad3d84aeb74438e82870cc3b7aee71e90.
+//
+// Solidity: event SwapERC20(uint256 indexed nonce, address indexed signerWallet, address signerToken, uint256 signerAmount, uint256 protocolFee, address indexed senderWallet, address senderToken, uint256 senderAmount)
+func (_SwapERC20 *SwapERC20Filterer) FilterSwapERC20(opts *bind.FilterOpts, nonce []*big.Int, signerWallet []common.Address, senderWallet []common.Address) (*SwapERC20SwapERC20Iterator, error) {
+
+	var nonceRule []interface{}
+	for _, nonceItem := range nonce {
+		nonceRule = append(nonceRule, nonceItem)
+	}
+	var signerWalletRule []interface{}
+	for _, signerWalletItem := range signerWallet {
+		signerWalletRule = append(signerWalletRule, signerWalletItem)
+	}
+
+	var senderWalletRule []interface{}
+	for _, senderWalletItem := range senderWallet {
+		senderWalletRule = append(senderWalletRule, senderWalletItem)
+	}
+
+	logs, sub, err := _SwapERC20.contract.FilterLogs(opts, "SwapERC20", nonceRule, signerWalletRule, senderWalletRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SwapERC20SwapERC20Iterator{contract: _SwapERC20.contract, event: "SwapERC20", logs: logs, sub: sub}, nil
+}
+
+// WatchSwapERC20 is a free log subscription operation binding the contract event 0xb651f2787ff61b5ab14f3936f2daebdad3d84aeb74438e82870cc3b7aee71e90.
+//
+// Solidity: event SwapERC20(uint256 indexed nonce, address indexed signerWallet, address signerToken, uint256 signerAmount, uint256 protocolFee, address indexed senderWallet, address senderToken, uint256 senderAmount)
+func (_SwapERC20 *SwapERC20Filterer) WatchSwapERC20(opts *bind.WatchOpts, sink chan<- *SwapERC20SwapERC20, nonce []*big.Int, signerWallet []common.Address, senderWallet []common.Address) (event.Subscription, error) {
+
+	var nonceRule []interface{}
+	for _, nonceItem := range nonce {
+		nonceRule = append(nonceRule, nonceItem)
+	}
+	var signerWalletRule []interface{}
+	for _, signerWalletItem := range signerWallet {
+		signerWalletRule = append(signerWalletRule, signerWalletItem)
+	}
+
+	var senderWalletRule []interface{}
+	for _, senderWalletItem := range senderWallet {
+		senderWalletRule = append(senderWalletRule, senderWalletItem)
+	}
+
+	logs, sub, err := _SwapERC20.contract.WatchLogs(opts, "SwapERC20", nonceRule, signerWalletRule, senderWalletRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SwapERC20SwapERC20)
+				if err := _SwapERC20.contract.UnpackLog(event, "SwapERC20", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSwapERC20 is a log parse operation binding the contract event 0xb651f2787ff61b5ab14f3936f2daebdad3d84aeb74438e82870cc3b7aee71e90.
+//
+// Solidity: event SwapERC20(uint256 indexed nonce, address indexed signerWallet, address signerToken, uint256 signerAmount, uint256 protocolFee, address indexed senderWallet, address senderToken, uint256 senderAmount)
+func (_SwapERC20 *SwapERC20Filterer) ParseSwapERC20(log types.Log) (*SwapERC20SwapERC20, error) {
+	event := new(SwapERC20SwapERC20)
+	if err := _SwapERC20.contract.UnpackLog(event, "SwapERC20", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
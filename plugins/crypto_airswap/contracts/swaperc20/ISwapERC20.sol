@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: MIT
+pragma solidity >=0.8.0;
+
+interface ISwapERC20 {
+    event SwapERC20(
+        uint256 indexed nonce,
+        address indexed signerWallet,
+        address signerToken,
+        uint256 signerAmount,
+        uint256 protocolFee,
+        address indexed senderWallet,
+        address senderToken,
+        uint256 senderAmount
+    );
+
+    function protocolFee() external view returns (uint256);
+}
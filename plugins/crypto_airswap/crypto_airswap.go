@@ -0,0 +1,499 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/plugins/crypto_airswap/contracts/swaperc20"
+	"autonity-oracle/plugins/crypto_uniswap/contracts/erc20"
+	"autonity-oracle/types"
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ecommon "github.com/ethereum/go-ethereum/common"
+	tp "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
+	ring "github.com/zfjagann/golang-ring"
+)
+
+var (
+	orderBookCapacity = 64
+	backfillTimeout   = 30 * time.Second
+	headerTimeout     = 10 * time.Second
+	version           = "v0.2.9"
+)
+
+// configs for the RFQ marketplaces settled by the AirSwap SwapERC20 contract, the contract address and the token pairs
+// are required to be set in the oracle server's config file.
+var defaultConfig = config.PluginConfig{
+	Name:               "crypto_airswap",
+	Scheme:             "wss",                               // both ws and wss works for this plugin.
+	Endpoint:           "replace with your host:port/path",  // the websocket endpoint of your validator node.
+	Timeout:            10,                                  // 10s.
+	DataUpdateInterval: common.DefaultAMMDataUpdateInterval, // 1s, shorten the default data point refresh interval for AMM market data, as they can move very fast.
+	BackfillBlocks:     common.DefaultAMMBackfillBlocks,     // Backfill the order book with the swaps of recent 300 blocks on start and reconnects.
+}
+
+type Order struct {
+	price  decimal.Decimal // base to quote token ratio of an executed RFQ trade.
+	volume *big.Int        // trade volume in quote token of per swap event.
+}
+
+// market is the order book of a configured token pair.
+type market struct {
+	symbol              string
+	pairConf            config.PairConfig
+	baseToken           ecommon.Address
+	quoteToken          ecommon.Address
+	baseDecimals        uint8
+	quoteDecimals       uint8
+	orderBook           ring.Ring
+	lastAggregatedPrice *common.Price
+}
+
+// headerReader reads the block headers, it is the L1 client, thus the block time of the events can be resolved.
+type headerReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*tp.Header, error)
+}
+
+// AirSwapClient builds the VWAP of the configured token pairs from the SwapERC20 events of the RFQ trades settled by the
+// AirSwap SwapERC20 contract, the contract and the pairs are bound on-demand during runtime.
+type AirSwapClient struct {
+	conf   *config.PluginConfig
+	logger hclog.Logger
+
+	symbols    []string
+	markets    map[string]*market
+	priceMutex sync.RWMutex
+
+	client       *ethclient.Client
+	headers      headerReader
+	contract     *swaperc20.SwapERC20
+	chSwapEvent  chan *swaperc20.SwapERC20SwapERC20
+	subSwapEvent event.Subscription
+	doneCh       chan struct{}
+	ticker       *time.Ticker
+	lostSync     bool
+
+	backfillBlocks uint64 // the number of recent blocks to backfill the order books from the historical events.
+	lastBlock      uint64 // the block of the last handled event, the events till it are not backfilled again.
+	backfilledTo   uint64 // the last backfilled block, the events till it from the subscription are skipped.
+	timedBlock     uint64 // the last block whose time is resolved, the events of a block share its time.
+	blockTS        int64  // the unix TS of the timed block.
+}
+
+func NewAirSwapClient(conf *config.PluginConfig) (*AirSwapClient, error) {
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   conf.Name,
+		Level:  hclog.Info,
+		Output: os.Stdout,
+	})
+
+	if !ecommon.IsHexAddress(conf.SwapAddress) {
+		logger.Error("invalid SwapERC20 contract address", "address", conf.SwapAddress)
+		return nil, fmt.Errorf("invalid SwapERC20 contract address: %q", conf.SwapAddress)
+	}
+
+	markets, symbols, err := newMarkets(conf.Pairs)
+	if err != nil {
+		logger.Error("invalid pairs config", "error", err)
+		return nil, err
+	}
+
+	c := &AirSwapClient{
+		conf:    conf,
+		logger:  logger,
+		symbols: symbols,
+		markets: markets,
+		doneCh:  make(chan struct{}),
+	}
+	if conf.BackfillBlocks > 0 {
+		c.backfillBlocks = uint64(conf.BackfillBlocks)
+	}
+	return c, nil
+}
+
+// newMarkets checks the configured pairs, and returns their order books by symbol with the supported symbols.
+func newMarkets(pairs []config.PairConfig) (map[string]*market, []string, error) {
+	if len(pairs) == 0 {
+		return nil, nil, fmt.Errorf("no pairs are configured")
+	}
+
+	markets := make(map[string]*market)
+	var symbols []string
+	for _, p := range pairs {
+		if _, ok := markets[p.Symbol]; ok || p.Symbol == "" {
+			return nil, nil, fmt.Errorf("invalid or duplicated symbol: %q", p.Symbol)
+		}
+
+		if !ecommon.IsHexAddress(p.BaseToken) || !ecommon.IsHexAddress(p.QuoteToken) || p.BaseToken == p.QuoteToken {
+			return nil, nil, fmt.Errorf("invalid token address of symbol: %s", p.Symbol)
+		}
+
+		if p.BaseDecimals < 0 || p.BaseDecimals > math.MaxUint8 || p.QuoteDecimals < 0 || p.QuoteDecimals > math.MaxUint8 {
+			return nil, nil, fmt.Errorf("invalid token decimals of symbol: %s", p.Symbol)
+		}
+
+		m := &market{
+			symbol:        p.Symbol,
+			pairConf:      p,
+			baseToken:     ecommon.HexToAddress(p.BaseToken),
+			quoteToken:    ecommon.HexToAddress(p.QuoteToken),
+			baseDecimals:  uint8(p.BaseDecimals),  // nolint
+			quoteDecimals: uint8(p.QuoteDecimals), // nolint
+		}
+		m.orderBook.SetCapacity(orderBookCapacity)
+		markets[p.Symbol] = m
+		symbols = append(symbols, p.Symbol)
+	}
+	return markets, symbols, nil
+}
+
+func (e *AirSwapClient) KeyRequired() bool {
+	return false
+}
+
+func (e *AirSwapClient) AvailableSymbols() ([]string, error) {
+	return e.symbols, nil
+}
+
+// FetchPrice returns the recent aggregated price of the symbols, the symbols without any trade are skipped.
+func (e *AirSwapClient) FetchPrice(symbols []string) (common.Prices, error) {
+	if e.client == nil {
+		if err := e.bind(); err != nil {
+			return nil, err
+		}
+	}
+
+	e.priceMutex.RLock()
+	defer e.priceMutex.RUnlock()
+
+	var prices common.Prices
+	for _, symbol := range symbols {
+		m, ok := e.markets[symbol]
+		if !ok {
+			continue
+		}
+
+		if m.lastAggregatedPrice == nil {
+			e.logger.Info("no trade of the pair yet", "symbol", symbol)
+			continue
+		}
+		prices = append(prices, *m.lastAggregatedPrice)
+	}
+	return prices, nil
+}
+
+// bind dials the L1 node, resolves the token decimals, subscribes the SwapERC20 events and starts the watcher of them.
+func (e *AirSwapClient) bind() error {
+	client, err := common.DialEthClient(e.conf)
+	if err != nil {
+		e.logger.Error("cannot dial to L1 validator node", "error", err)
+		return err
+	}
+
+	for _, m := range e.markets {
+		if m.baseDecimals, err = tokenDecimals(client, m.baseToken, m.pairConf.BaseDecimals); err != nil {
+			e.logger.Info("cannot resolve token decimals", "symbol", m.symbol, "error", err)
+			client.Close()
+			return err
+		}
+		if m.quoteDecimals, err = tokenDecimals(client, m.quoteToken, m.pairConf.QuoteDecimals); err != nil {
+			e.logger.Info("cannot resolve token decimals", "symbol", m.symbol, "error", err)
+			client.Close()
+			return err
+		}
+	}
+
+	contract, err := swaperc20.NewSwapERC20(ecommon.HexToAddress(e.conf.SwapAddress), client)
+	if err != nil {
+		e.logger.Error("bind SwapERC20 contract", "error", err, "address", e.conf.SwapAddress)
+		client.Close()
+		return err
+	}
+
+	e.client = client
+	e.headers = client
+	e.contract = contract
+	if err = e.eventSubscription(); err != nil {
+		client.Close()
+		e.client = nil
+		return err
+	}
+
+	e.ticker = time.NewTicker(time.Second * 1) // 1s ticker used to repair L1 connectivity if it was disconnected.
+	go e.startWatcher()
+	e.logger.Info("bind SwapERC20 contract", "address", e.conf.SwapAddress, "symbols", e.symbols)
+	return nil
+}
+
+func tokenDecimals(client bind.ContractCaller, token ecommon.Address, configured int) (uint8, error) {
+	if configured > 0 {
+		return uint8(configured), nil // nolint
+	}
+
+	caller, err := erc20.NewERC20Caller(token, client)
+	if err != nil {
+		return 0, err
+	}
+
+	decimals, err := caller.Decimals(nil)
+	if err != nil {
+		return 0, fmt.Errorf("cannot read decimals of token %s: %w", token, err)
+	}
+	return decimals, nil
+}
+
+func (e *AirSwapClient) eventSubscription() error {
+	chSwapEvent := make(chan *swaperc20.SwapERC20SwapERC20)
+	subSwapEvent, err := e.contract.WatchSwapERC20(new(bind.WatchOpts), chSwapEvent, nil, nil, nil)
+	if err != nil {
+		e.logger.Error("cannot watch SwapERC20 event", "error", err)
+		return err
+	}
+
+	e.chSwapEvent = chSwapEvent
+	e.subSwapEvent = subSwapEvent
+	return nil
+}
+
+// startWatcher backfills the order books and then handles the events of the subscription, the backfill runs in the
+// watcher rather than on binding, thus the price fetching which binds the contract is not held by it.
+func (e *AirSwapClient) startWatcher() {
+	// the subscription is ahead of the backfill, thus no event is missed in between.
+	e.backfill()
+
+	for {
+		select {
+		case <-e.doneCh:
+			e.ticker.Stop()
+			e.logger.Info("air-swap events watcher stopped")
+			return
+		case err := <-e.subSwapEvent.Err():
+			if err != nil {
+				e.logger.Info("subscription error of SwapERC20 event", "error", err)
+				e.lostSync = true
+				e.subSwapEvent.Unsubscribe()
+			}
+		case swapEvent := <-e.chSwapEvent:
+			e.logger.Debug("receiving a SwapERC20 event", "event", swapEvent)
+			if swapEvent.Raw.BlockNumber <= e.backfilledTo {
+				continue
+			}
+			e.lastBlock = swapEvent.Raw.BlockNumber
+			e.handleSwapEvent(swapEvent)
+		case <-e.ticker.C:
+			e.checkHealth()
+		}
+	}
+}
+
+func (e *AirSwapClient) checkHealth() {
+	if !e.lostSync {
+		return
+	}
+
+	if err := e.eventSubscription(); err != nil {
+		e.logger.Info("rebuilding WS connectivity with L1 node", "error", err)
+		return
+	}
+
+	// catch up the swaps happened during the disconnection.
+	e.backfill()
+	e.lostSync = false
+}
+
+// backfill fetches the SwapERC20 events of the recent blocks, and replays them to the order books, thus the VWAP is
+// available since the contract is bound or re-subscribed rather than waiting for new trades.
+func (e *AirSwapClient) backfill() {
+	if e.backfillBlocks == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backfillTimeout)
+	defer cancel()
+
+	head, err := e.client.BlockNumber(ctx)
+	if err != nil {
+		e.logger.Info("cannot get the latest block for backfill", "error", err)
+		return
+	}
+
+	start := uint64(0)
+	if head >= e.backfillBlocks {
+		start = head - e.backfillBlocks + 1
+	}
+	// the events till the last handled block are in the order books already.
+	if e.lastBlock >= start {
+		start = e.lastBlock + 1
+	}
+	if start > head {
+		return
+	}
+
+	it, err := e.contract.FilterSwapERC20(&bind.FilterOpts{Start: start, End: &head, Context: ctx}, nil, nil, nil)
+	if err != nil {
+		e.logger.Info("cannot filter SwapERC20 events for backfill", "error", err)
+		return
+	}
+	var events []*swaperc20.SwapERC20SwapERC20
+	for it.Next() {
+		events = append(events, it.Event)
+	}
+	it.Close() // nolint
+
+	e.replay(events)
+	e.backfilledTo = head
+	e.lastBlock = head
+	e.logger.Info("backfilled order books", "from", start, "to", head, "events", len(events))
+}
+
+// replay handles the historical events in the order of their emission.
+func (e *AirSwapClient) replay(events []*swaperc20.SwapERC20SwapERC20) {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Raw.BlockNumber != events[j].Raw.BlockNumber {
+			return events[i].Raw.BlockNumber < events[j].Raw.BlockNumber
+		}
+		return events[i].Raw.Index < events[j].Raw.Index
+	})
+
+	for _, ev := range events {
+		e.handleSwapEvent(ev)
+	}
+}
+
+// handleSwapEvent puts the trade into the order book of the pair it settled, in either direction. The trades of the
+// un-configured token pairs are ignored.
+func (e *AirSwapClient) handleSwapEvent(swap *swaperc20.SwapERC20SwapERC20) {
+	for _, m := range e.markets {
+		var baseAmount, quoteAmount *big.Int
+		switch {
+		case swap.SignerToken == m.baseToken && swap.SenderToken == m.quoteToken:
+			baseAmount, quoteAmount = swap.SignerAmount, swap.SenderAmount
+		case swap.SignerToken == m.quoteToken && swap.SenderToken == m.baseToken:
+			baseAmount, quoteAmount = swap.SenderAmount, swap.SignerAmount
+		default:
+			continue
+		}
+
+		price, err := ratio(baseAmount, quoteAmount, m.baseDecimals, m.quoteDecimals)
+		if err != nil {
+			e.logger.Info("skip SwapERC20 event", "symbol", m.symbol, "tx", swap.Raw.TxHash, "error", err)
+			return
+		}
+
+		// the aggregated price is quoted at the time of its latest trade, thus a stale one can be rejected by its age.
+		ts, err := e.blockTime(swap.Raw.BlockNumber)
+		if err != nil {
+			e.logger.Info("skip SwapERC20 event", "symbol", m.symbol, "tx", swap.Raw.TxHash, "error", err)
+			return
+		}
+
+		aggPrice, volumes, err := aggregatePrice(&m.orderBook, Order{price: price, volume: new(big.Int).Set(quoteAmount)})
+		if err != nil {
+			e.logger.Error("aggregate order book price failed", "symbol", m.symbol, "error", err)
+			return
+		}
+
+		e.logger.Debug("newly aggregated price", "symbol", m.symbol, "price", aggPrice)
+		e.priceMutex.Lock()
+		m.lastAggregatedPrice = &common.Price{
+			Symbol:    m.symbol,
+			Price:     aggPrice.String(),
+			Volume:    volumes.String(),
+			Timestamp: ts,
+		}
+		e.priceMutex.Unlock()
+		return
+	}
+}
+
+// blockTime returns the unix TS of the block, the time of the last resolved block is kept as the events of a block
+// are handled in a row.
+func (e *AirSwapClient) blockTime(number uint64) (int64, error) {
+	if e.blockTS != 0 && e.timedBlock == number {
+		return e.blockTS, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), headerTimeout)
+	defer cancel()
+	header, err := e.headers.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return 0, fmt.Errorf("cannot get the header of block %d: %w", number, err)
+	}
+
+	e.timedBlock = number
+	e.blockTS = int64(header.Time) // nolint
+	return e.blockTS, nil
+}
+
+func (e *AirSwapClient) Close() {
+	if e.client == nil {
+		return
+	}
+	e.subSwapEvent.Unsubscribe()
+	e.client.Close()
+	e.doneCh <- struct{}{}
+}
+
+// ratio computes the price of the base token in quote token from the traded amounts in their smallest units.
+func ratio(baseAmount, quoteAmount *big.Int, baseDecimals, quoteDecimals uint8) (decimal.Decimal, error) {
+	var r decimal.Decimal
+	if baseAmount == nil || quoteAmount == nil || baseAmount.Cmp(common.Zero) <= 0 || quoteAmount.Cmp(common.Zero) <= 0 {
+		return r, fmt.Errorf("trade amount <= 0, skip price computing")
+	}
+
+	// ratio == (quoteAmount/10^quoteDecimals) / (baseAmount/10^baseDecimals)
+	//       == (quoteAmount*10^baseDecimals) / (baseAmount*10^quoteDecimals)
+	scaledBase := new(big.Int).Mul(baseAmount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(quoteDecimals)), nil))
+	scaledQuote := new(big.Int).Mul(quoteAmount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(baseDecimals)), nil))
+	price := new(big.Rat).SetFrac(scaledQuote, scaledBase)
+	return decimal.NewFromString(price.FloatString(common.CryptoToUsdcDecimals))
+}
+
+// aggregatePrice puts the order into the order book, and computes the VWAP of the recent orders in it.
+func aggregatePrice(orderBook *ring.Ring, order Order) (decimal.Decimal, *big.Int, error) {
+	orderBook.Enqueue(order)
+	recentOrders := orderBook.Values()
+	// nothing to aggregate
+	if len(recentOrders) == 1 {
+		return order.price, order.volume, nil
+	}
+
+	var totalValues decimal.Decimal
+	totalVol := new(big.Int)
+	for _, o := range recentOrders {
+		order, ok := o.(Order)
+		if !ok {
+			return decimal.Decimal{}, nil, fmt.Errorf("invalid order type")
+		}
+		totalValues = totalValues.Add(order.price.Mul(decimal.NewFromBigInt(order.volume, 0)))
+		totalVol.Add(totalVol, order.volume)
+	}
+
+	if totalVol.Cmp(common.Zero) == 0 {
+		return decimal.Decimal{}, nil, fmt.Errorf("total quote amount is zero, cannot compute ratio")
+	}
+	return totalValues.Div(decimal.NewFromBigInt(totalVol, 0)), totalVol, nil
+}
+
+func main() {
+	conf := common.ResolveConf(os.Args[0], &defaultConfig)
+	client, err := NewAirSwapClient(conf)
+	if err != nil {
+		return
+	}
+
+	adapter := common.NewPlugin(conf, client, version, types.SrcAMM, nil)
+	defer adapter.Close()
+	common.PluginServe(adapter)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/plugins/crypto_airswap/contracts/swaperc20"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	tp "github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ntnToken  = "0x0000000000000000000000000000000000000001"
+	atnToken  = "0x0000000000000000000000000000000000000002"
+	usdcToken = "0x0000000000000000000000000000000000000003"
+	wei       = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	usdc      = big.NewInt(1e6)
+)
+
+// blockHeaders serves the headers of the blocks whose time is 1000 plus the block number, and it counts the calls.
+type blockHeaders struct {
+	calls int
+	err   error
+}
+
+func (h *blockHeaders) HeaderByNumber(_ context.Context, number *big.Int) (*tp.Header, error) {
+	h.calls++
+	if h.err != nil {
+		return nil, h.err
+	}
+	return &tp.Header{Number: number, Time: 1000 + number.Uint64()}, nil
+}
+
+func newTestClient(t *testing.T) *AirSwapClient {
+	markets, symbols, err := newMarkets([]config.PairConfig{
+		{Symbol: common.ATNUSDCSymbol, BaseToken: atnToken, QuoteToken: usdcToken, BaseDecimals: 18, QuoteDecimals: 6},
+		{Symbol: common.NTNUSDCSymbol, BaseToken: ntnToken, QuoteToken: usdcToken, BaseDecimals: 18, QuoteDecimals: 6},
+	})
+	require.NoError(t, err)
+	return &AirSwapClient{logger: hclog.NewNullLogger(), markets: markets, symbols: symbols, headers: &blockHeaders{}}
+}
+
+func swapEvent(signerToken string, signerAmount *big.Int, senderToken string, senderAmount *big.Int, block uint64,
+	index uint) *swaperc20.SwapERC20SwapERC20 {
+	return &swaperc20.SwapERC20SwapERC20{
+		SignerToken:  ecommon.HexToAddress(signerToken),
+		SignerAmount: signerAmount,
+		SenderToken:  ecommon.HexToAddress(senderToken),
+		SenderAmount: senderAmount,
+		Raw:          tp.Log{BlockNumber: block, Index: index},
+	}
+}
+
+func amount(n int64, unit *big.Int) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), unit)
+}
+
+func TestNewAirSwapClient(t *testing.T) {
+	conf := config.PluginConfig{
+		Name:        "crypto_airswap",
+		SwapAddress: "0x0000000000000000000000000000000000000004",
+		Pairs:       []config.PairConfig{{Symbol: common.ATNUSDCSymbol, BaseToken: atnToken, QuoteToken: usdcToken}},
+	}
+	client, err := NewAirSwapClient(&conf)
+	require.NoError(t, err)
+	symbols, err := client.AvailableSymbols()
+	require.NoError(t, err)
+	require.Equal(t, []string{common.ATNUSDCSymbol}, symbols)
+
+	conf.SwapAddress = ""
+	_, err = NewAirSwapClient(&conf)
+	require.Error(t, err)
+
+	invalids := [][]config.PairConfig{
+		nil,
+		{{BaseToken: atnToken, QuoteToken: usdcToken}},
+		{{Symbol: common.ATNUSDCSymbol, BaseToken: atnToken, QuoteToken: atnToken}},
+		{{Symbol: common.ATNUSDCSymbol, BaseToken: atnToken, QuoteToken: usdcToken, QuoteDecimals: 256}},
+		{{Symbol: common.ATNUSDCSymbol, BaseToken: atnToken, QuoteToken: usdcToken},
+			{Symbol: common.ATNUSDCSymbol, BaseToken: ntnToken, QuoteToken: usdcToken}},
+	}
+	for _, pairs := range invalids {
+		_, _, err = newMarkets(pairs)
+		require.Error(t, err, pairs)
+	}
+}
+
+func TestAirSwapClientHandleSwapEvent(t *testing.T) {
+	client := newTestClient(t)
+
+	// the signer sells 100 ATN for 200 USDC, then the signer buys 100 ATN with 400 USDC.
+	client.handleSwapEvent(swapEvent(atnToken, amount(100, wei), usdcToken, amount(200, usdc), 1, 0))
+	client.handleSwapEvent(swapEvent(usdcToken, amount(400, usdc), atnToken, amount(100, wei), 1, 1))
+	// the trade of an un-configured pair is ignored.
+	client.handleSwapEvent(swapEvent(ntnToken, amount(100, wei), atnToken, amount(100, wei), 2, 0))
+	// the trade with zero amount is skipped.
+	client.handleSwapEvent(swapEvent(ntnToken, amount(0, wei), usdcToken, amount(100, usdc), 2, 1))
+
+	atn := client.markets[common.ATNUSDCSymbol].lastAggregatedPrice
+	require.NotNil(t, atn)
+	// VWAP: (2*200 + 4*400) / 600
+	require.Equal(t, "3.3333333333333333", atn.Price)
+	require.Equal(t, amount(600, usdc).String(), atn.Volume)
+	require.Nil(t, client.markets[common.NTNUSDCSymbol].lastAggregatedPrice)
+
+	// the price is quoted at the block time of its latest trade, the time of a block is resolved once.
+	require.Equal(t, int64(1001), atn.Timestamp)
+	require.Equal(t, 1, client.headers.(*blockHeaders).calls)
+}
+
+func TestAirSwapClientBlockTime(t *testing.T) {
+	client := newTestClient(t)
+	client.handleSwapEvent(swapEvent(atnToken, amount(100, wei), usdcToken, amount(200, usdc), 5, 0))
+	require.Equal(t, int64(1005), client.markets[common.ATNUSDCSymbol].lastAggregatedPrice.Timestamp)
+
+	// the trade of a block without time is skipped, rather than being quoted as a fresh one.
+	client.headers.(*blockHeaders).err = errors.New("header not found")
+	client.handleSwapEvent(swapEvent(atnToken, amount(100, wei), usdcToken, amount(400, usdc), 9, 0))
+	atn := client.markets[common.ATNUSDCSymbol].lastAggregatedPrice
+	require.Equal(t, "2", atn.Price)
+	require.Equal(t, int64(1005), atn.Timestamp)
+}
+
+func TestAirSwapClientReplay(t *testing.T) {
+	client := newTestClient(t)
+	for i := 0; i < orderBookCapacity+1; i++ {
+		client.handleSwapEvent(swapEvent(ntnToken, amount(1, wei), usdcToken, amount(1000, usdc), 1, uint(i)))
+	}
+
+	// the replayed events are handled in the order of their emission, the elder orders are out of the window.
+	client.replay([]*swaperc20.SwapERC20SwapERC20{
+		swapEvent(ntnToken, amount(1, wei), usdcToken, amount(20, usdc), 3, 0),
+		swapEvent(ntnToken, amount(1, wei), usdcToken, amount(10, usdc), 2, 5),
+	})
+	m := client.markets[common.NTNUSDCSymbol]
+	require.Equal(t, orderBookCapacity, len(m.orderBook.Values()))
+	last := m.orderBook.Values()[orderBookCapacity-1].(Order)
+	require.Equal(t, "20", last.price.String())
+}
+
+func TestRatio(t *testing.T) {
+	r, err := ratio(amount(4, wei), amount(10, usdc), 18, 6)
+	require.NoError(t, err)
+	require.Equal(t, "2.5", r.String())
+
+	_, err = ratio(big.NewInt(0), amount(10, usdc), 18, 6)
+	require.Error(t, err)
+}
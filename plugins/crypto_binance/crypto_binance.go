@@ -0,0 +1,193 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	version    = "v0.2.9"
+	path       = "api/v3/ticker/24hr"
+	queryParam = "symbols"
+)
+
+var defaultConfig = config.PluginConfig{
+	Name:               "crypto_binance",
+	Key:                "",
+	Scheme:             "https",
+	Endpoint:           "api.binance.com",
+	Timeout:            10, // 10s
+	DataUpdateInterval: 30, // 30s, far below the request weight limit of the public market data of binance.
+}
+
+// supportedSymbols maps the symbols to the binance markets, binance does not list fiat USD markets, thus the symbols
+// are quoted in USDT as their markets are, and they are converted to USD by the symbol routes via USDT-USD.
+var supportedSymbols = map[string]string{
+	common.USDCUSDTSymbol: "USDCUSDT",
+	"BTC-USDT":            "BTCUSDT",
+	"ETH-USDT":            "ETHUSDT",
+}
+
+type Ticker struct {
+	Symbol           string `json:"symbol"`
+	LastPrice        string `json:"lastPrice"`
+	WeightedAvgPrice string `json:"weightedAvgPrice"`
+	Volume           string `json:"volume"`      // the 24h trade volume in base asset.
+	QuoteVolume      string `json:"quoteVolume"` // the 24h trade volume in quote asset.
+	CloseTime        int64  `json:"closeTime"`   // the unix TS in milliseconds of the end of the 24h window.
+}
+
+type ErrorResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+type BinanceClient struct {
+	conf   *config.PluginConfig
+	client *common.Client
+	logger hclog.Logger
+}
+
+func NewBinanceClient(conf *config.PluginConfig) *BinanceClient {
+	client := common.NewClientFromConf(conf)
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   conf.Name,
+		Level:  hclog.Info,
+		Output: os.Stdout,
+	})
+
+	return &BinanceClient{conf: conf, client: client, logger: logger}
+}
+
+func (b *BinanceClient) KeyRequired() bool {
+	return false
+}
+
+func (b *BinanceClient) FetchPrice(symbols []string) (common.Prices, error) {
+	markets := make(map[string]string)
+	for _, s := range symbols {
+		if m, ok := supportedSymbols[s]; ok {
+			markets[m] = s
+		}
+	}
+	if len(markets) == 0 {
+		return nil, common.ErrKnownSymbols
+	}
+
+	u, err := b.buildURL(markets)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := b.client.Conn.Request(b.conf.Scheme, u)
+	if err != nil {
+		b.logger.Error("https request", "error", err.Error())
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		b.logger.Error("io read", "error", err.Error())
+		return nil, err
+	}
+
+	if err = common.CheckHTTPStatusCode(res.StatusCode); err != nil {
+		var errRes ErrorResponse
+		if json.Unmarshal(body, &errRes) == nil && errRes.Msg != "" {
+			b.logger.Error("data source return error", "error", err.Error(), "code", errRes.Code, "msg", errRes.Msg)
+		} else {
+			b.logger.Error("data source return error", "error", err.Error())
+		}
+		return nil, err
+	}
+
+	var tickers []Ticker
+	if err = json.Unmarshal(body, &tickers); err != nil {
+		b.logger.Error("unmarshal result", "error", err.Error())
+		return nil, err
+	}
+
+	var prices common.Prices
+	for _, t := range tickers {
+		symbol, ok := markets[t.Symbol]
+		if !ok {
+			continue
+		}
+
+		p, err := toPrice(symbol, &t)
+		if err != nil {
+			b.logger.Error("error filling price data", "symbol", symbol, "err", err.Error())
+			continue
+		}
+		prices = append(prices, p)
+	}
+	return prices, nil
+}
+
+func toPrice(symbol string, t *Ticker) (common.Price, error) {
+	var price common.Price
+	if t.LastPrice == "" {
+		return price, fmt.Errorf("%s price not found", symbol)
+	}
+
+	volume, err := common.ToVolume(t.QuoteVolume)
+	if err != nil {
+		return price, err
+	}
+
+	price.Symbol = symbol
+	price.Price = t.LastPrice
+	price.Volume = volume // the 24h trade volume in USDT.
+	price.Timestamp = t.CloseTime / 1000
+	return price, nil
+}
+
+func (b *BinanceClient) AvailableSymbols() ([]string, error) {
+	symbols := make([]string, 0, len(supportedSymbols))
+	for s := range supportedSymbols {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+func (b *BinanceClient) Close() {
+	b.client.Conn.Close()
+}
+
+func (b *BinanceClient) buildURL(markets map[string]string) (*url.URL, error) {
+	symbols := make([]string, 0, len(markets))
+	for m := range markets {
+		symbols = append(symbols, m)
+	}
+	sort.Strings(symbols)
+
+	param, err := json.Marshal(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &url.URL{}
+	endpoint.Path = path
+	query := endpoint.Query()
+	query.Set(queryParam, string(param))
+	endpoint.RawQuery = query.Encode()
+	return endpoint, nil
+}
+
+func main() {
+	conf := common.ResolveConf(os.Args[0], &defaultConfig)
+	adapter := common.NewPlugin(conf, NewBinanceClient(conf), version, types.SrcCEX, nil)
+	defer adapter.Close()
+	common.PluginServe(adapter)
+}
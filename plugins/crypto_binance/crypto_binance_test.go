@@ -0,0 +1,90 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinanceClientFetchPrice(t *testing.T) {
+	ticker, err := os.ReadFile("testdata/ticker_24hr.json")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+path || r.URL.Query().Get(queryParam) != `["BTCUSDT","USDCUSDT"]` {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(ticker) // nolint
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := NewBinanceClient(&conf)
+	defer client.Close()
+	prices, err := client.FetchPrice([]string{common.USDCUSDTSymbol, "BTC-USDT", "EUR-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(prices))
+
+	// the volume is the 24h quote volume in USDT, rather than the one of the base asset.
+	require.Equal(t, common.Price{Symbol: "BTC-USDT", Price: "66500.00000000", Volume: "1166029837", Timestamp: 1729330800},
+		prices[0])
+	require.Equal(t, common.Price{Symbol: common.USDCUSDTSymbol, Price: "1.00020000", Volume: "498300382",
+		Timestamp: 1729330800}, prices[1])
+}
+
+func TestBinanceToPrice(t *testing.T) {
+	_, err := toPrice("BTC-USDT", &Ticker{Symbol: "BTCUSDT", QuoteVolume: "1166029837.06"})
+	require.ErrorContains(t, err, "price not found")
+
+	_, err = toPrice("BTC-USDT", &Ticker{Symbol: "BTCUSDT", LastPrice: "66500", QuoteVolume: "-1"})
+	require.ErrorContains(t, err, "negative volume")
+
+	// the weighted average price of the 24h window is not taken, the last price is.
+	price, err := toPrice("BTC-USDT", &Ticker{Symbol: "BTCUSDT", LastPrice: "66500", WeightedAvgPrice: "66540.7",
+		Volume: "17523.44", QuoteVolume: "0.5", CloseTime: 1729330800999})
+	require.NoError(t, err)
+	require.Equal(t, common.Price{Symbol: "BTC-USDT", Price: "66500", Volume: "0", Timestamp: 1729330800}, price)
+}
+
+func TestBinanceClientError(t *testing.T) {
+	body, err := os.ReadFile("testdata/invalid_symbol.json")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(body) // nolint
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := NewBinanceClient(&conf)
+	defer client.Close()
+	_, err = client.FetchPrice([]string{common.USDCUSDTSymbol, "BTC-USDT"})
+	require.Error(t, err)
+
+	_, err = client.FetchPrice([]string{"EUR-USD"})
+	require.ErrorIs(t, err, common.ErrKnownSymbols)
+}
+
+func TestBinanceClientAvailableSymbols(t *testing.T) {
+	client := NewBinanceClient(&defaultConfig)
+	defer client.Close()
+	symbols, err := client.AvailableSymbols()
+	require.NoError(t, err)
+	require.Equal(t, []string{"BTC-USDT", "ETH-USDT", common.USDCUSDTSymbol}, symbols)
+}
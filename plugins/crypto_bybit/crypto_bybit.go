@@ -0,0 +1,183 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	version    = "v0.2.9"
+	path       = "v5/market/tickers"
+	queryParam = "category"
+	category   = "spot"
+	retCodeOK  = 0
+)
+
+var defaultConfig = config.PluginConfig{
+	Name:               "crypto_bybit",
+	Key:                "",
+	Scheme:             "https",
+	Endpoint:           "api.bybit.com",
+	Timeout:            10, // 10s
+	DataUpdateInterval: 30, // 30s, far below the rate limit of the public market data of bybit.
+}
+
+// supportedSymbols maps the symbols to the bybit spot markets, bybit does not list fiat USD spot markets, thus the
+// symbols are quoted in USDT as their markets are, and they are converted to USD by the symbol routes via USDT-USD.
+var supportedSymbols = map[string]string{
+	common.USDCUSDTSymbol: "USDCUSDT",
+	"BTC-USDT":            "BTCUSDT",
+	"ETH-USDT":            "ETHUSDT",
+}
+
+type Ticker struct {
+	Symbol      string `json:"symbol"`
+	LastPrice   string `json:"lastPrice"`
+	Volume24h   string `json:"volume24h"`   // the 24h trade volume in base coin.
+	Turnover24h string `json:"turnover24h"` // the 24h trade volume in quote coin.
+}
+
+type Result struct {
+	Category string   `json:"category"`
+	List     []Ticker `json:"list"`
+}
+
+type Response struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  Result `json:"result"`
+	Time    int64  `json:"time"` // the unix TS in milliseconds of the response.
+}
+
+type BybitClient struct {
+	conf   *config.PluginConfig
+	client *common.Client
+	logger hclog.Logger
+}
+
+func NewBybitClient(conf *config.PluginConfig) *BybitClient {
+	client := common.NewClientFromConf(conf)
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   conf.Name,
+		Level:  hclog.Info,
+		Output: os.Stdout,
+	})
+
+	return &BybitClient{conf: conf, client: client, logger: logger}
+}
+
+func (b *BybitClient) KeyRequired() bool {
+	return false
+}
+
+func (b *BybitClient) FetchPrice(symbols []string) (common.Prices, error) {
+	markets := make(map[string]string)
+	for _, s := range symbols {
+		if m, ok := supportedSymbols[s]; ok {
+			markets[m] = s
+		}
+	}
+	if len(markets) == 0 {
+		return nil, common.ErrKnownSymbols
+	}
+
+	res, err := b.client.Conn.Request(b.conf.Scheme, b.buildURL())
+	if err != nil {
+		b.logger.Error("https request", "error", err.Error())
+		return nil, err
+	}
+	defer res.Body.Close()
+	if err = common.CheckHTTPStatusCode(res.StatusCode); err != nil {
+		b.logger.Error("data source return error", "error", err.Error())
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		b.logger.Error("io read", "error", err.Error())
+		return nil, err
+	}
+
+	var result Response
+	if err = json.Unmarshal(body, &result); err != nil {
+		b.logger.Error("unmarshal result", "error", err.Error())
+		return nil, err
+	}
+
+	if result.RetCode != retCodeOK {
+		b.logger.Error("data source return error", "code", result.RetCode, "msg", result.RetMsg)
+		return nil, fmt.Errorf("error return from data source, code: %d, msg: %s", result.RetCode, result.RetMsg)
+	}
+
+	var prices common.Prices
+	for _, t := range result.Result.List {
+		symbol, ok := markets[t.Symbol]
+		if !ok {
+			continue
+		}
+
+		p, err := toPrice(symbol, &t, result.Time)
+		if err != nil {
+			b.logger.Error("error filling price data", "symbol", symbol, "err", err.Error())
+			continue
+		}
+		prices = append(prices, p)
+	}
+	return prices, nil
+}
+
+func toPrice(symbol string, t *Ticker, ts int64) (common.Price, error) {
+	var price common.Price
+	if t.LastPrice == "" {
+		return price, fmt.Errorf("%s price not found", symbol)
+	}
+
+	volume, err := common.ToVolume(t.Turnover24h)
+	if err != nil {
+		return price, err
+	}
+
+	price.Symbol = symbol
+	price.Price = t.LastPrice
+	price.Volume = volume // the 24h trade volume in USDT.
+	price.Timestamp = ts / 1000
+	return price, nil
+}
+
+func (b *BybitClient) AvailableSymbols() ([]string, error) {
+	symbols := make([]string, 0, len(supportedSymbols))
+	for s := range supportedSymbols {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+func (b *BybitClient) Close() {
+	b.client.Conn.Close()
+}
+
+func (b *BybitClient) buildURL() *url.URL {
+	endpoint := &url.URL{}
+	endpoint.Path = path
+	query := endpoint.Query()
+	query.Set(queryParam, category)
+	endpoint.RawQuery = query.Encode()
+	return endpoint
+}
+
+func main() {
+	conf := common.ResolveConf(os.Args[0], &defaultConfig)
+	adapter := common.NewPlugin(conf, NewBybitClient(conf), version, types.SrcCEX, nil)
+	defer adapter.Close()
+	common.PluginServe(adapter)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBybitClientFetchPrice(t *testing.T) {
+	ticker, err := os.ReadFile("testdata/tickers_spot.json")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+path || r.URL.Query().Get(queryParam) != category {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(ticker) // nolint
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := NewBybitClient(&conf)
+	defer client.Close()
+	prices, err := client.FetchPrice([]string{common.USDCUSDTSymbol, "BTC-USDT", "EUR-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(prices))
+
+	// the volume is the 24h turnover in USDT, and the TS is the one of the response as the tickers carry none.
+	require.Equal(t, common.Price{Symbol: "BTC-USDT", Price: "66501.3", Volume: "412306785", Timestamp: 1729330800},
+		prices[0])
+	require.Equal(t, common.Price{Symbol: common.USDCUSDTSymbol, Price: "1.0001", Volume: "87463290",
+		Timestamp: 1729330800}, prices[1])
+}
+
+func TestBybitToPrice(t *testing.T) {
+	_, err := toPrice("BTC-USDT", &Ticker{Symbol: "BTCUSDT", LastPrice: "66501.3", Turnover24h: ""}, 1729330800789)
+	require.Error(t, err)
+
+	price, err := toPrice("BTC-USDT", &Ticker{Symbol: "BTCUSDT", LastPrice: "66501.3", Volume24h: "6195.28",
+		Turnover24h: "412306785.53"}, 1729330800789)
+	require.NoError(t, err)
+	require.Equal(t, common.Price{Symbol: "BTC-USDT", Price: "66501.3", Volume: "412306785", Timestamp: 1729330800}, price)
+}
+
+func TestBybitClientError(t *testing.T) {
+	// bybit reports the errors in the envelope.
+	body, err := os.ReadFile("testdata/error.json")
+	require.NoError(t, err)
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		w.Write(body) // nolint
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := NewBybitClient(&conf)
+	defer client.Close()
+	_, err = client.FetchPrice([]string{common.USDCUSDTSymbol})
+	require.ErrorContains(t, err, "10006")
+
+	_, err = client.FetchPrice([]string{"EUR-USD"})
+	require.ErrorIs(t, err, common.ErrKnownSymbols)
+
+	status.Store(http.StatusForbidden)
+	_, err = client.FetchPrice([]string{common.USDCUSDTSymbol})
+	require.ErrorIs(t, err, common.ErrAccessLimited)
+}
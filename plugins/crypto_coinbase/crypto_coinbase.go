@@ -5,17 +5,25 @@ import (
 	"autonity-oracle/plugins/common"
 	"autonity-oracle/types"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
 )
 
 const (
-	version = "v0.2.7"
-	path    = "v2/prices/USDC-USD/spot"
+	version       = "v0.2.9"
+	path          = "v2/prices/USDC-USD/spot"
+	wsURL         = "wss://ws-feed.exchange.coinbase.com"
+	tickerChannel = "ticker"
+	// coinbase exchange does not list USDC-USD as USDC is redeemed 1:1 for USD on coinbase, thus the USDC-USD price
+	// is derived from the USDT-USD and USDT-USDC markets.
+	usdtUSD  = "USDT-USD"
+	usdtUSDC = "USDT-USDC"
 )
 
 var defaultConfig = config.PluginConfig{
@@ -24,7 +32,7 @@ var defaultConfig = config.PluginConfig{
 	Scheme:             "https",
 	Endpoint:           "api.coinbase.com",
 	Timeout:            10, // 10s
-	DataUpdateInterval: 30, // 30s, tested and passed the rate limit policy of public data service of coinbase.
+	DataUpdateInterval: 1,  // 1s, the cadence of the websocket ticker book reads, the REST fallback keeps its own 30s interval.
 }
 
 type PriceData struct {
@@ -37,28 +45,138 @@ type Response struct {
 	Data PriceData `json:"data"`
 }
 
+// WSTicker is the ticker message of the coinbase exchange websocket feed, it is pushed on every trade of the market.
+type WSTicker struct {
+	Type      string `json:"type"`
+	ProductID string `json:"product_id"`
+	Price     string `json:"price"`
+	Time      string `json:"time"`
+}
+
+// tickerHandler subscribes the ticker and heartbeat channels of the markets, the heartbeat channel keeps the feed
+// active while the markets are not traded, and the connection is kept alive with websocket ping frames.
+type tickerHandler struct{}
+
+func (h *tickerHandler) Subscriptions() []interface{} {
+	return []interface{}{map[string]interface{}{
+		"type":        "subscribe",
+		"product_ids": []string{usdtUSD, usdtUSDC},
+		"channels":    []string{"heartbeat", tickerChannel},
+	}}
+}
+
+func (h *tickerHandler) Heartbeat() interface{} {
+	return nil
+}
+
+func (h *tickerHandler) Handle(msg []byte) (common.Prices, error) {
+	var t WSTicker
+	if err := json.Unmarshal(msg, &t); err != nil {
+		return nil, err
+	}
+
+	if t.Type != tickerChannel || t.Price == "" {
+		return nil, nil
+	}
+
+	p := common.Price{Symbol: t.ProductID, Price: t.Price}
+	if ts, err := time.Parse(time.RFC3339Nano, t.Time); err == nil {
+		p.Timestamp = ts.Unix()
+	}
+	return common.Prices{p}, nil
+}
+
 type CoinBaseClient struct {
 	conf   *config.PluginConfig
 	client *common.Client
+	ws     *common.WSClient
+	rest   *common.RESTFallback
 	logger hclog.Logger
 }
 
 func NewCoinBaseClient(conf *config.PluginConfig) *CoinBaseClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	return newCoinBaseClient(conf, wsURL)
+}
+
+func newCoinBaseClient(conf *config.PluginConfig, wsURL string) *CoinBaseClient {
+	client := common.NewClientFromConf(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Info,
 		Output: os.Stdout,
 	})
 
-	return &CoinBaseClient{conf: conf, client: client, logger: logger}
+	dialer, err := common.NewWSDialer(conf)
+	if err != nil {
+		panic("invalid network config: " + err.Error())
+	}
+
+	ws := common.NewWSClient(wsURL, &tickerHandler{}, common.DefaultWSHeartbeat, dialer, logger)
+	ws.Start()
+	cc := &CoinBaseClient{conf: conf, client: client, ws: ws, logger: logger}
+	cc.rest = common.NewRESTFallback(common.DefaultRESTFallbackInterval, cc.fetchREST)
+	return cc
 }
 
 func (c *CoinBaseClient) KeyRequired() bool {
 	return false
 }
 
+// FetchPrice returns the USDC-USD price derived from the websocket ticker book with the USDT-USD price of the book,
+// which converts the USDT quoted symbols of the other data sources to USD. It falls back to the REST API if the
+// websocket connection is not live, the REST API is queried once per fallback interval and it serves USDC-USD only.
 func (c *CoinBaseClient) FetchPrice(_ []string) (common.Prices, error) {
+	if p, err := c.latest(); err == nil {
+		prices := common.Prices{p}
+		if usd, err := c.ws.Latest(usdtUSD); err == nil {
+			usd.Symbol = common.USDTUSDSymbol
+			usd.Volume = types.DefaultVolume.String()
+			prices = append(prices, usd)
+		}
+		return prices, nil
+	}
+
+	c.logger.Debug("websocket ticker is not available, fall back to REST API")
+	return c.rest.Fetch()
+}
+
+// latest derives USDC-USD from USDT-USD / USDT-USDC, the timestamp is the elder one of the two tickers.
+func (c *CoinBaseClient) latest() (common.Price, error) {
+	usd, err := c.ws.Latest(usdtUSD)
+	if err != nil {
+		return common.Price{}, err
+	}
+	usdc, err := c.ws.Latest(usdtUSDC)
+	if err != nil {
+		return common.Price{}, err
+	}
+
+	usdPrice, err := decimal.NewFromString(usd.Price)
+	if err != nil {
+		return common.Price{}, err
+	}
+	usdcPrice, err := decimal.NewFromString(usdc.Price)
+	if err != nil {
+		return common.Price{}, err
+	}
+	if !usdcPrice.IsPositive() {
+		return common.Price{}, fmt.Errorf("invalid %s price: %s", usdtUSDC, usdc.Price)
+	}
+
+	timestamp := usd.Timestamp
+	if usdc.Timestamp < timestamp {
+		timestamp = usdc.Timestamp
+	}
+
+	return common.Price{
+		Timestamp: timestamp,
+		Symbol:    common.DefaultUSDCSymbol,
+		Price:     usdPrice.Div(usdcPrice).String(),
+		Volume:    types.DefaultVolume.String(),
+	}, nil
+}
+
+func (c *CoinBaseClient) fetchREST() (common.Prices, error) {
 	var prices common.Prices
 	u := c.buildURL()
 	res, err := c.client.Conn.Request(c.conf.Scheme, u)
@@ -96,10 +214,11 @@ func (c *CoinBaseClient) FetchPrice(_ []string) (common.Prices, error) {
 }
 
 func (c *CoinBaseClient) AvailableSymbols() ([]string, error) {
-	return []string{common.DefaultUSDCSymbol}, nil
+	return []string{common.DefaultUSDCSymbol, common.USDTUSDSymbol}, nil
 }
 
 func (c *CoinBaseClient) Close() {
+	c.ws.Close()
 	c.client.Conn.Close()
 }
 
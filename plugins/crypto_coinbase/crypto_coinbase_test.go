@@ -1,9 +1,18 @@
 package main
 
 import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
-	"testing"
 )
 
 func TestNewCoinBaseClient(t *testing.T) {
@@ -16,3 +25,137 @@ func TestNewCoinBaseClient(t *testing.T) {
 	_, err = decimal.NewFromString(prices[0].Price)
 	require.NoError(t, err)
 }
+
+func TestTickerHandler(t *testing.T) {
+	handler := &tickerHandler{}
+
+	msg, err := os.ReadFile("testdata/ws_ticker_usdt_usd.json")
+	require.NoError(t, err)
+	prices, err := handler.Handle(msg)
+	require.NoError(t, err)
+	require.Equal(t, common.Prices{{Symbol: usdtUSD, Price: "1.0002", Timestamp: 1792402212}}, prices)
+
+	msg, err = os.ReadFile("testdata/ws_heartbeat.json")
+	require.NoError(t, err)
+	prices, err = handler.Handle(msg)
+	require.NoError(t, err)
+	require.Empty(t, prices)
+
+	_, err = handler.Handle([]byte("not json"))
+	require.Error(t, err)
+}
+
+// newWSServer serves the ticker fixtures of the subscribed markets.
+func newWSServer(t *testing.T, markets ...string) *httptest.Server {
+	tickers := map[string][]byte{}
+	for market, file := range map[string]string{
+		usdtUSD:  "testdata/ws_ticker_usdt_usd.json",
+		usdtUSDC: "testdata/ws_ticker_usdt_usdc.json",
+	} {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		tickers[market] = data
+	}
+
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var msg struct {
+				Type       string   `json:"type"`
+				ProductIDs []string `json:"product_ids"`
+			}
+			if err = conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.Type != "subscribe" {
+				continue
+			}
+			for _, m := range markets {
+				conn.WriteMessage(websocket.TextMessage, tickers[m]) // nolint
+			}
+		}
+	}))
+}
+
+func TestCoinBaseClientWebsocket(t *testing.T) {
+	server := newWSServer(t, usdtUSD, usdtUSDC)
+	defer server.Close()
+
+	conf := defaultConfig
+	conf.Endpoint = "127.0.0.1:1" // the REST API is not reachable.
+	client := newCoinBaseClient(&conf, "ws"+strings.TrimPrefix(server.URL, "http"))
+	defer client.Close()
+
+	require.Eventually(t, func() bool {
+		_, err := client.latest()
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	prices, err := client.FetchPrice([]string{common.DefaultUSDCSymbol, common.USDTUSDSymbol})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(prices))
+	require.Equal(t, common.DefaultUSDCSymbol, prices[0].Symbol)
+	expected := decimal.RequireFromString("1.0002").Div(decimal.RequireFromString("1.0001"))
+	require.Equal(t, expected.String(), prices[0].Price)
+	require.Equal(t, int64(1792402210), prices[0].Timestamp)
+
+	// the USDT-USD market converts the USDT quoted symbols of the other data sources.
+	require.Equal(t, common.USDTUSDSymbol, prices[1].Symbol)
+	require.Equal(t, "1.0002", prices[1].Price)
+}
+
+func TestCoinBaseClientRESTFallback(t *testing.T) {
+	spot, err := os.ReadFile("testdata/spot.json")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/"+path {
+			w.Write(spot) // nolint
+			return
+		}
+
+		// the websocket only serves one of the markets, thus the USDC-USD price cannot be derived from it.
+		if websocket.IsWebSocketUpgrade(r) {
+			upgrader := websocket.Upgrader{}
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			usd, _ := os.ReadFile("testdata/ws_ticker_usdt_usd.json")
+			conn.WriteMessage(websocket.TextMessage, usd) // nolint
+			for {
+				if _, _, err = conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := newCoinBaseClient(&conf, "ws"+strings.TrimPrefix(server.URL, "http"))
+	defer client.Close()
+
+	require.Eventually(t, func() bool {
+		_, err := client.ws.Latest(usdtUSD)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	prices, err := client.FetchPrice([]string{common.DefaultUSDCSymbol})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(prices))
+	require.Equal(t, common.DefaultUSDCSymbol, prices[0].Symbol)
+	require.Equal(t, "1.00", prices[0].Price)
+}
@@ -9,7 +9,6 @@ import (
 	"net/url"
 	"os"
 	"strconv"
-	"time"
 
 	"github.com/hashicorp/go-hclog"
 )
@@ -47,7 +46,7 @@ type CoinGeckoClient struct {
 }
 
 func NewCoinGeckoClient(conf *config.PluginConfig) *CoinGeckoClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewClientFromConf(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Info,
@@ -1,8 +1,13 @@
 package main
 
 import (
+	simConfig "autonity-oracle/data_source_simulator/binance_simulator/config"
+	"autonity-oracle/data_source_simulator/binance_simulator/generator_manager"
+	"autonity-oracle/data_source_simulator/binance_simulator/httpsrv"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -16,3 +21,22 @@ func TestNewCoinGeckoClient(t *testing.T) {
 	_, err = decimal.NewFromString(prices[0].Price)
 	require.NoError(t, err)
 }
+
+func TestCoinGeckoClientWithSimulator(t *testing.T) {
+	gm := generator_manager.NewScenarioGeneratorManager(&simConfig.Scenario{Symbols: map[string]*simConfig.SymbolScenario{
+		"USDC-USD": {Start: 0.9999},
+	}}, 0)
+	srv := httptest.NewServer(httpsrv.NewHttpServer(gm, 0, 0, 0).Handler)
+	defer srv.Close()
+
+	conf := defaultConfig
+	conf.Scheme = "http"
+	conf.Endpoint = strings.TrimPrefix(srv.URL, "http://")
+	client := NewCoinGeckoClient(&conf)
+	defer client.Close()
+	prices, err := client.FetchPrice([]string{"USDC-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(prices))
+	require.Equal(t, "USDC-USD", prices[0].Symbol)
+	require.True(t, decimal.RequireFromString("0.9999").Equal(decimal.RequireFromString(prices[0].Price)))
+}
@@ -4,6 +4,7 @@ import (
 	"autonity-oracle/config"
 	"autonity-oracle/plugins/common"
 	"autonity-oracle/types"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,10 +16,13 @@ import (
 )
 
 const (
-	version         = "v0.2.7"
+	version         = "v0.2.9"
 	path            = "0/public/Ticker"
 	queryParam      = "pair"
 	supportedSymbol = "USDCUSD"
+	wsURL           = "wss://ws.kraken.com/v2"
+	wsSymbol        = "USDC/USD"
+	tickerChannel   = "ticker"
 )
 
 var defaultConfig = config.PluginConfig{
@@ -27,7 +31,7 @@ var defaultConfig = config.PluginConfig{
 	Scheme:             "https",
 	Endpoint:           "api.kraken.com",
 	Timeout:            10, // 10s
-	DataUpdateInterval: 30, // 30s, tested and passed the rate limit policy of public data service of kraken.
+	DataUpdateInterval: 1,  // 1s, the cadence of the websocket ticker book reads, the REST fallback keeps its own 30s interval.
 }
 
 type Result struct {
@@ -47,28 +51,113 @@ type Response struct {
 	Result map[string]Result `json:"result"`
 }
 
+// WSTicker is the ticker of the websocket v2 API, the prices are decoded as json.Number to keep the precision.
+type WSTicker struct {
+	Symbol    string      `json:"symbol"`
+	Last      json.Number `json:"last"`
+	Volume    json.Number `json:"volume"`
+	VWAP      json.Number `json:"vwap"`
+	Timestamp string      `json:"timestamp"`
+}
+
+type WSMessage struct {
+	Channel string     `json:"channel"`
+	Type    string     `json:"type"`
+	Data    []WSTicker `json:"data"`
+}
+
+// tickerHandler subscribes the ticker channel of the websocket v2 API, kraken pushes a snapshot on subscription and an
+// update on every trade, and it pushes heartbeats per second while nothing is traded.
+type tickerHandler struct{}
+
+func (h *tickerHandler) Subscriptions() []interface{} {
+	return []interface{}{map[string]interface{}{
+		"method": "subscribe",
+		"params": map[string]interface{}{"channel": tickerChannel, "symbol": []string{wsSymbol}},
+	}}
+}
+
+func (h *tickerHandler) Heartbeat() interface{} {
+	return map[string]string{"method": "ping"}
+}
+
+func (h *tickerHandler) Handle(msg []byte) (common.Prices, error) {
+	var m WSMessage
+	decoder := json.NewDecoder(bytes.NewReader(msg))
+	decoder.UseNumber()
+	if err := decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	if m.Channel != tickerChannel {
+		return nil, nil
+	}
+
+	var prices common.Prices
+	for _, t := range m.Data {
+		if t.Last == "" {
+			continue
+		}
+
+		p := common.Price{Symbol: t.Symbol, Price: t.Last.String()}
+		if ts, err := time.Parse(time.RFC3339Nano, t.Timestamp); err == nil {
+			p.Timestamp = ts.Unix()
+		}
+		prices = append(prices, p)
+	}
+	return prices, nil
+}
+
 type KrakenClient struct {
 	conf   *config.PluginConfig
 	client *common.Client
+	ws     *common.WSClient
+	rest   *common.RESTFallback
 	logger hclog.Logger
 }
 
 func NewKrakenClient(conf *config.PluginConfig) *KrakenClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	return newKrakenClient(conf, wsURL)
+}
+
+func newKrakenClient(conf *config.PluginConfig, wsURL string) *KrakenClient {
+	client := common.NewClientFromConf(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Info,
 		Output: os.Stdout,
 	})
 
-	return &KrakenClient{conf: conf, client: client, logger: logger}
+	dialer, err := common.NewWSDialer(conf)
+	if err != nil {
+		panic("invalid network config: " + err.Error())
+	}
+
+	ws := common.NewWSClient(wsURL, &tickerHandler{}, common.DefaultWSHeartbeat, dialer, logger)
+	ws.Start()
+	kc := &KrakenClient{conf: conf, client: client, ws: ws, logger: logger}
+	kc.rest = common.NewRESTFallback(common.DefaultRESTFallbackInterval, kc.fetchREST)
+	return kc
 }
 
 func (k *KrakenClient) KeyRequired() bool {
 	return false
 }
 
+// FetchPrice returns the latest ticker from the websocket book, it falls back to the REST API if the websocket
+// connection is not live, the REST API is queried once per fallback interval.
 func (k *KrakenClient) FetchPrice(_ []string) (common.Prices, error) {
+	if p, err := k.ws.Latest(wsSymbol); err == nil {
+		p.Symbol = common.DefaultUSDCSymbol
+		p.Volume = types.DefaultVolume.String()
+		return common.Prices{p}, nil
+	}
+
+	k.logger.Debug("websocket ticker is not available, fall back to REST API")
+	return k.rest.Fetch()
+}
+
+func (k *KrakenClient) fetchREST() (common.Prices, error) {
 	var prices common.Prices
 	u := k.buildURL()
 	res, err := k.client.Conn.Request(k.conf.Scheme, u)
@@ -132,6 +221,7 @@ func (k *KrakenClient) AvailableSymbols() ([]string, error) {
 }
 
 func (k *KrakenClient) Close() {
+	k.ws.Close()
 	k.client.Conn.Close()
 }
 
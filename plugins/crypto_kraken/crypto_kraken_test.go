@@ -1,9 +1,19 @@
 package main
 
 import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
-	"testing"
 )
 
 func TestNewKrakenClient(t *testing.T) {
@@ -16,3 +26,111 @@ func TestNewKrakenClient(t *testing.T) {
 	_, err = decimal.NewFromString(prices[0].Price)
 	require.NoError(t, err)
 }
+
+func TestTickerHandler(t *testing.T) {
+	handler := &tickerHandler{}
+
+	msg, err := os.ReadFile("testdata/ws_ticker.json")
+	require.NoError(t, err)
+	prices, err := handler.Handle(msg)
+	require.NoError(t, err)
+	require.Equal(t, common.Prices{{Symbol: wsSymbol, Price: "0.99992", Timestamp: 1792402212}}, prices)
+
+	msg, err = os.ReadFile("testdata/ws_heartbeat.json")
+	require.NoError(t, err)
+	prices, err = handler.Handle(msg)
+	require.NoError(t, err)
+	require.Empty(t, prices)
+
+	_, err = handler.Handle([]byte("not json"))
+	require.Error(t, err)
+}
+
+// newWSServer serves the ticker fixture to the subscriptions of the ticker channel.
+func newWSServer(t *testing.T) *httptest.Server {
+	ticker, err := os.ReadFile("testdata/ws_ticker.json")
+	require.NoError(t, err)
+
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var msg struct {
+				Method string `json:"method"`
+				Params struct {
+					Channel string   `json:"channel"`
+					Symbol  []string `json:"symbol"`
+				} `json:"params"`
+			}
+			if err = conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.Method == "subscribe" && msg.Params.Channel == tickerChannel && len(msg.Params.Symbol) == 1 &&
+				msg.Params.Symbol[0] == wsSymbol {
+				conn.WriteMessage(websocket.TextMessage, ticker) // nolint
+			}
+		}
+	}))
+}
+
+func TestKrakenClientWebsocket(t *testing.T) {
+	server := newWSServer(t)
+	defer server.Close()
+
+	conf := defaultConfig
+	conf.Endpoint = "127.0.0.1:1" // the REST API is not reachable.
+	client := newKrakenClient(&conf, "ws"+strings.TrimPrefix(server.URL, "http"))
+	defer client.Close()
+
+	require.Eventually(t, func() bool {
+		_, err := client.ws.Latest(wsSymbol)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	prices, err := client.FetchPrice([]string{common.DefaultUSDCSymbol})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(prices))
+	require.Equal(t, common.DefaultUSDCSymbol, prices[0].Symbol)
+	require.Equal(t, "0.99992", prices[0].Price)
+	require.Equal(t, int64(1792402212), prices[0].Timestamp)
+}
+
+func TestKrakenClientRESTFallback(t *testing.T) {
+	ticker, err := os.ReadFile("testdata/ticker.json")
+	require.NoError(t, err)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path != "/"+path || r.URL.Query().Get(queryParam) != supportedSymbol {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(ticker) // nolint
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := newKrakenClient(&conf, "ws://127.0.0.1:1") // the websocket is not reachable.
+	defer client.Close()
+
+	prices, err := client.FetchPrice([]string{common.DefaultUSDCSymbol})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(prices))
+	require.Equal(t, common.DefaultUSDCSymbol, prices[0].Symbol)
+	require.Equal(t, "0.99993", prices[0].Price)
+
+	// the REST API is not queried again within the fallback interval.
+	_, err = client.FetchPrice([]string{common.DefaultUSDCSymbol})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
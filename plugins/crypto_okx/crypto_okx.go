@@ -0,0 +1,184 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	version    = "v0.2.9"
+	path       = "api/v5/market/tickers"
+	queryParam = "instType"
+	instType   = "SPOT"
+	codeOK     = "0"
+)
+
+var defaultConfig = config.PluginConfig{
+	Name:               "crypto_okx",
+	Key:                "",
+	Scheme:             "https",
+	Endpoint:           "www.okx.com",
+	Timeout:            10, // 10s
+	DataUpdateInterval: 30, // 30s, far below the rate limit of the public market data of okx.
+}
+
+// supportedSymbols maps the symbols to the okx spot instruments, okx does not list fiat USD spot markets, thus the
+// symbols are quoted in USDT as their instruments are, and they are converted to USD by the symbol routes via USDT-USD.
+var supportedSymbols = map[string]string{
+	common.USDCUSDTSymbol: "USDC-USDT",
+	"BTC-USDT":            "BTC-USDT",
+	"ETH-USDT":            "ETH-USDT",
+}
+
+type Ticker struct {
+	InstID    string `json:"instId"`
+	Last      string `json:"last"`
+	Vol24h    string `json:"vol24h"`    // the 24h trade volume in base currency.
+	VolCcy24h string `json:"volCcy24h"` // the 24h trade volume in quote currency of spot instruments.
+	TS        string `json:"ts"`        // the unix TS in milliseconds of the ticker.
+}
+
+type Response struct {
+	Code string   `json:"code"`
+	Msg  string   `json:"msg"`
+	Data []Ticker `json:"data"`
+}
+
+type OKXClient struct {
+	conf   *config.PluginConfig
+	client *common.Client
+	logger hclog.Logger
+}
+
+func NewOKXClient(conf *config.PluginConfig) *OKXClient {
+	client := common.NewClientFromConf(conf)
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   conf.Name,
+		Level:  hclog.Info,
+		Output: os.Stdout,
+	})
+
+	return &OKXClient{conf: conf, client: client, logger: logger}
+}
+
+func (o *OKXClient) KeyRequired() bool {
+	return false
+}
+
+func (o *OKXClient) FetchPrice(symbols []string) (common.Prices, error) {
+	instruments := make(map[string]string)
+	for _, s := range symbols {
+		if inst, ok := supportedSymbols[s]; ok {
+			instruments[inst] = s
+		}
+	}
+	if len(instruments) == 0 {
+		return nil, common.ErrKnownSymbols
+	}
+
+	res, err := o.client.Conn.Request(o.conf.Scheme, o.buildURL())
+	if err != nil {
+		o.logger.Error("https request", "error", err.Error())
+		return nil, err
+	}
+	defer res.Body.Close()
+	if err = common.CheckHTTPStatusCode(res.StatusCode); err != nil {
+		o.logger.Error("data source return error", "error", err.Error())
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		o.logger.Error("io read", "error", err.Error())
+		return nil, err
+	}
+
+	var result Response
+	if err = json.Unmarshal(body, &result); err != nil {
+		o.logger.Error("unmarshal result", "error", err.Error())
+		return nil, err
+	}
+
+	if result.Code != codeOK {
+		o.logger.Error("data source return error", "code", result.Code, "msg", result.Msg)
+		return nil, fmt.Errorf("error return from data source, code: %s, msg: %s", result.Code, result.Msg)
+	}
+
+	var prices common.Prices
+	for _, t := range result.Data {
+		symbol, ok := instruments[t.InstID]
+		if !ok {
+			continue
+		}
+
+		p, err := toPrice(symbol, &t)
+		if err != nil {
+			o.logger.Error("error filling price data", "symbol", symbol, "err", err.Error())
+			continue
+		}
+		prices = append(prices, p)
+	}
+	return prices, nil
+}
+
+func toPrice(symbol string, t *Ticker) (common.Price, error) {
+	var price common.Price
+	if t.Last == "" {
+		return price, fmt.Errorf("%s price not found", symbol)
+	}
+
+	volume, err := common.ToVolume(t.VolCcy24h)
+	if err != nil {
+		return price, err
+	}
+
+	ts, err := strconv.ParseInt(t.TS, 10, 64)
+	if err != nil {
+		return price, err
+	}
+
+	price.Symbol = symbol
+	price.Price = t.Last
+	price.Volume = volume // the 24h trade volume in USDT.
+	price.Timestamp = ts / 1000
+	return price, nil
+}
+
+func (o *OKXClient) AvailableSymbols() ([]string, error) {
+	symbols := make([]string, 0, len(supportedSymbols))
+	for s := range supportedSymbols {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+func (o *OKXClient) Close() {
+	o.client.Conn.Close()
+}
+
+func (o *OKXClient) buildURL() *url.URL {
+	endpoint := &url.URL{}
+	endpoint.Path = path
+	query := endpoint.Query()
+	query.Set(queryParam, instType)
+	endpoint.RawQuery = query.Encode()
+	return endpoint
+}
+
+func main() {
+	conf := common.ResolveConf(os.Args[0], &defaultConfig)
+	adapter := common.NewPlugin(conf, NewOKXClient(conf), version, types.SrcCEX, nil)
+	defer adapter.Close()
+	common.PluginServe(adapter)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOKXClientFetchPrice(t *testing.T) {
+	ticker, err := os.ReadFile("testdata/tickers_spot.json")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+path || r.URL.Query().Get(queryParam) != instType {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(ticker) // nolint
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := NewOKXClient(&conf)
+	defer client.Close()
+	prices, err := client.FetchPrice([]string{common.USDCUSDTSymbol, "BTC-USDT", "EUR-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(prices))
+
+	// the volume is the 24h volume in the quote currency, i.e. USDT, of the spot instruments.
+	require.Equal(t, common.Price{Symbol: common.USDCUSDTSymbol, Price: "1.0002", Volume: "137629651",
+		Timestamp: 1729330800}, prices[0])
+	require.Equal(t, common.Price{Symbol: "BTC-USDT", Price: "66498.1", Volume: "392144031", Timestamp: 1729330800},
+		prices[1])
+}
+
+func TestOKXToPrice(t *testing.T) {
+	_, err := toPrice("BTC-USDT", &Ticker{InstID: "BTC-USDT", Last: "66498.1", VolCcy24h: "abc", TS: "1729330800478"})
+	require.Error(t, err)
+
+	// okx quotes the TS of the ticker as a string.
+	_, err = toPrice("BTC-USDT", &Ticker{InstID: "BTC-USDT", Last: "66498.1", VolCcy24h: "392144031.58", TS: ""})
+	require.Error(t, err)
+
+	price, err := toPrice("BTC-USDT", &Ticker{InstID: "BTC-USDT", Last: "66498.1", Vol24h: "5894.1",
+		VolCcy24h: "392144031.58", TS: "1729330800478"})
+	require.NoError(t, err)
+	require.Equal(t, common.Price{Symbol: "BTC-USDT", Price: "66498.1", Volume: "392144031", Timestamp: 1729330800}, price)
+}
+
+func TestOKXClientError(t *testing.T) {
+	// okx reports the errors in the envelope.
+	body, err := os.ReadFile("testdata/error.json")
+	require.NoError(t, err)
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		w.Write(body) // nolint
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := NewOKXClient(&conf)
+	defer client.Close()
+	_, err = client.FetchPrice([]string{common.USDCUSDTSymbol})
+	require.ErrorContains(t, err, "50011")
+
+	_, err = client.FetchPrice([]string{"EUR-USD"})
+	require.ErrorIs(t, err, common.ErrKnownSymbols)
+
+	status.Store(http.StatusTooManyRequests)
+	_, err = client.FetchPrice([]string{common.USDCUSDTSymbol})
+	require.ErrorIs(t, err, common.ErrAccessLimited)
+}
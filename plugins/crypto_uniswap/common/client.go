@@ -3,20 +3,24 @@ package common
 import (
 	"autonity-oracle/config"
 	"autonity-oracle/plugins/common"
+	"autonity-oracle/plugins/crypto_uniswap/contracts/erc20"
 	"autonity-oracle/plugins/crypto_uniswap/contracts/factory"
 	"autonity-oracle/plugins/crypto_uniswap/contracts/pair"
 	"autonity-oracle/types"
+	"context"
 	"fmt"
 	"math"
 	"math/big"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ecommon "github.com/ethereum/go-ethereum/common"
+	tp "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/event"
 	"github.com/hashicorp/go-hclog"
 	"github.com/shopspring/decimal"
 	ring "github.com/zfjagann/golang-ring"
@@ -24,21 +28,45 @@ import (
 
 var (
 	orderBookCapacity = 64
-	Version           = "v0.2.7"
-	supportedSymbols  = common.DefaultCryptoSymbols
+	backfillTimeout   = 30 * time.Second
+	Version           = "v0.2.9"
+
+	// the topics of the swap and the sync events of the pair, they are tracked by a single log subscription.
+	swapTopic, syncTopic = pairEventTopics()
 )
 
+func pairEventTopics() (ecommon.Hash, ecommon.Hash) {
+	parsed, err := pair.PairMetaData.GetAbi()
+	if err != nil {
+		panic("invalid pair ABI: " + err.Error())
+	}
+	return parsed.Events["Swap"].ID, parsed.Events["Sync"].ID
+}
+
 type Order struct {
-	cryptoToUsdcPrice decimal.Decimal // ATN-USDCx or NTN-USDCx ratio.
-	volume            *big.Int        // trade volume in usdc of per swap event.
+	cryptoToUsdcPrice decimal.Decimal // base to quote token ratio, e.g. ATN-USDCx or NTN-USDCx ratio.
+	volume            *big.Int        // trade volume in quote token of per swap event.
 }
 
 type UniswapClient struct {
 	conf   *config.PluginConfig
 	logger hclog.Logger
 
-	atnUSDCPairContract *WrappedPair
-	ntnUSDCPairContract *WrappedPair
+	pairConfs map[string]config.PairConfig
+	symbols   []string
+	pairs     map[string]*WrappedPair
+}
+
+// NewClient creates the data source client of UniSwap V2 pairs or V3 pools upon the configured pool version.
+func NewClient(conf *config.PluginConfig) (common.DataSourceClient, error) {
+	switch conf.PoolVersion {
+	case common.UniswapV3:
+		return NewUniswapV3Client(conf)
+	case 0, common.UniswapV2:
+		return NewUniswapClient(conf)
+	default:
+		return nil, fmt.Errorf("unknown uniswap pool version: %d", conf.PoolVersion)
+	}
 }
 
 func NewUniswapClient(conf *config.PluginConfig) (*UniswapClient, error) {
@@ -48,113 +76,185 @@ func NewUniswapClient(conf *config.PluginConfig) (*UniswapClient, error) {
 		Output: os.Stdout,
 	})
 
+	pairConfs, symbols, err := resolvePairConfs(conf.Pairs)
+	if err != nil {
+		logger.Error("invalid pairs config", "error", err)
+		return nil, err
+	}
+
 	// just load config and logger for uniswap client, as the crypto-pair markets can be
 	// resolved during runtime now on-demand.
 	return &UniswapClient{
-		conf:   conf,
-		logger: logger,
+		conf:      conf,
+		logger:    logger,
+		pairConfs: pairConfs,
+		symbols:   symbols,
+		pairs:     make(map[string]*WrappedPair),
 	}, nil
 }
 
+// DefaultPairs returns the configs of the ATN-USDC and NTN-USDC pairs with the token addresses of the target blockchain.
+func DefaultPairs(ntnToken, atnToken, usdcToken string) []config.PairConfig {
+	return []config.PairConfig{
+		{
+			Symbol:        common.ATNUSDCSymbol,
+			BaseToken:     atnToken,
+			QuoteToken:    usdcToken,
+			BaseDecimals:  common.AutonityCryptoDecimals,
+			QuoteDecimals: common.USDCDecimals,
+		},
+		{
+			Symbol:        common.NTNUSDCSymbol,
+			BaseToken:     ntnToken,
+			QuoteToken:    usdcToken,
+			BaseDecimals:  common.AutonityCryptoDecimals,
+			QuoteDecimals: common.USDCDecimals,
+		},
+	}
+}
+
+// resolvePairConfs checks the configured pairs, and returns them by symbol with the symbols supported by the plugin. If
+// both ATN-USDC and NTN-USDC are configured, NTN-ATN is supported too as it is derived from them.
+func resolvePairConfs(pairs []config.PairConfig) (map[string]config.PairConfig, []string, error) {
+	if len(pairs) == 0 {
+		return nil, nil, fmt.Errorf("no pairs are configured")
+	}
+
+	pairConfs := make(map[string]config.PairConfig)
+	var symbols []string
+	for _, p := range pairs {
+		if _, ok := pairConfs[p.Symbol]; ok || p.Symbol == "" {
+			return nil, nil, fmt.Errorf("invalid or duplicated symbol: %q", p.Symbol)
+		}
+
+		if !ecommon.IsHexAddress(p.BaseToken) || !ecommon.IsHexAddress(p.QuoteToken) {
+			return nil, nil, fmt.Errorf("invalid token address of symbol: %s", p.Symbol)
+		}
+
+		if p.BaseDecimals < 0 || p.BaseDecimals > math.MaxUint8 || p.QuoteDecimals < 0 || p.QuoteDecimals > math.MaxUint8 {
+			return nil, nil, fmt.Errorf("invalid token decimals of symbol: %s", p.Symbol)
+		}
+		pairConfs[p.Symbol] = p
+		symbols = append(symbols, p.Symbol)
+	}
+
+	_, atn := pairConfs[common.ATNUSDCSymbol]
+	_, ntn := pairConfs[common.NTNUSDCSymbol]
+	_, ntnATN := pairConfs[common.NTNATNSymbol]
+	if atn && ntn && !ntnATN {
+		symbols = append(symbols, common.NTNATNSymbol)
+	}
+	return pairConfs, symbols, nil
+}
+
+// resolveDecimals reads the decimals of the pair's tokens from the ERC20 contracts if they are omitted in the config.
+func resolveDecimals(client bind.ContractCaller, conf config.PairConfig) (uint8, uint8, error) {
+	baseDecimals, err := tokenDecimals(client, conf.BaseToken, conf.BaseDecimals)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	quoteDecimals, err := tokenDecimals(client, conf.QuoteToken, conf.QuoteDecimals)
+	if err != nil {
+		return 0, 0, err
+	}
+	return baseDecimals, quoteDecimals, nil
+}
+
+func tokenDecimals(client bind.ContractCaller, token string, configured int) (uint8, error) {
+	if configured > 0 {
+		return uint8(configured), nil // nolint
+	}
+
+	caller, err := erc20.NewERC20Caller(ecommon.HexToAddress(token), client)
+	if err != nil {
+		return 0, err
+	}
+
+	decimals, err := caller.Decimals(nil)
+	if err != nil {
+		return 0, fmt.Errorf("cannot read decimals of token %s: %w", token, err)
+	}
+	return decimals, nil
+}
+
 func (e *UniswapClient) KeyRequired() bool {
 	return false
 }
 
 func (e *UniswapClient) AvailableSymbols() ([]string, error) {
-	return supportedSymbols, nil
+	return e.symbols, nil
 }
 
-func (e *UniswapClient) tryFetch(pair *WrappedPair, symbol string) (common.Price, error) {
+func (e *UniswapClient) tryFetch(symbol string) (common.Price, error) {
 	// if the pair haven't been bind with the marketplace, try to bind it.
-	if pair == nil {
-		url := e.conf.Scheme + "://" + e.conf.Endpoint
-		usdcTokenAddress := ecommon.HexToAddress(e.conf.USDCTokenAddress)
-		factoryAddress := ecommon.HexToAddress(e.conf.SwapAddress)
-
-		if symbol == common.ATNUSDCSymbol {
-			atnTokenAddress := ecommon.HexToAddress(e.conf.ATNTokenAddress)
-			atnUsdcPair, err := NewWrappedPair(symbol, atnTokenAddress, usdcTokenAddress, factoryAddress, url, e.logger)
-			if err != nil {
-				return common.Price{}, err
-			}
-			e.atnUSDCPairContract = atnUsdcPair
-			return atnUsdcPair.aggregatedPrice()
-		}
-
-		// pair for NTN-USDC comes here
-		ntnTokenAddress := ecommon.HexToAddress(e.conf.NTNTokenAddress)
-		ntnUsdcPair, err := NewWrappedPair(symbol, ntnTokenAddress, usdcTokenAddress, factoryAddress, url, e.logger)
-		if err != nil {
-			return common.Price{}, err
-		}
-		e.ntnUSDCPairContract = ntnUsdcPair
-		return ntnUsdcPair.aggregatedPrice()
+	if p, ok := e.pairs[symbol]; ok {
+		// pair contract already bind, try to get recent aggregated price.
+		return p.aggregatedPrice()
 	}
 
-	// pair contract already bind, try to get recent aggregated price.
-	return pair.aggregatedPrice()
+	factoryAddress := ecommon.HexToAddress(e.conf.SwapAddress)
+	p, err := NewWrappedPair(e.pairConfs[symbol], factoryAddress, e.conf, e.logger)
+	if err != nil {
+		return common.Price{}, err
+	}
+	e.pairs[symbol] = p
+	return p.aggregatedPrice()
 }
 
 // FetchPrice fetch the price of the supported symbols of this plugin.
 func (e *UniswapClient) FetchPrice(symbols []string) (common.Prices, error) {
-	var prices common.Prices
+	return fetchPairPrices(symbols, e.pairConfs, e.tryFetch, e.logger), nil
+}
 
-	var atnUSDCPrice common.Price
-	var ntnUSDCPrice common.Price
-	var err error
+// fetchPairPrices fetches the prices of the configured pairs, and derives NTN-ATN from ATN-USDC and NTN-USDC.
+func fetchPairPrices(symbols []string, pairConfs map[string]config.PairConfig, fetch func(string) (common.Price, error),
+	logger hclog.Logger) common.Prices {
+	var prices common.Prices
+	fetched := make(map[string]common.Price)
 	for _, symbol := range symbols {
-		if symbol == common.ATNUSDCSymbol {
-			atnUSDCPrice, err = e.tryFetch(e.atnUSDCPairContract, symbol)
-			if err != nil {
-				e.logger.Info("fetch price", "symbol", symbol, "err", err)
-				continue
-			}
-
-			prices = append(prices, atnUSDCPrice)
+		if _, ok := pairConfs[symbol]; !ok {
 			continue
 		}
 
-		if symbol == common.NTNUSDCSymbol {
-			ntnUSDCPrice, err = e.tryFetch(e.ntnUSDCPairContract, symbol)
-			if err != nil {
-				e.logger.Info("fetch price", "symbol", symbol, "err", err)
-				continue
-			}
-			prices = append(prices, ntnUSDCPrice)
+		price, err := fetch(symbol)
+		if err != nil {
+			logger.Info("fetch price", "symbol", symbol, "err", err)
 			continue
 		}
+		fetched[symbol] = price
+		prices = append(prices, price)
 	}
 
-	if len(prices) == 2 {
+	atnUSDCPrice, atn := fetched[common.ATNUSDCSymbol]
+	ntnUSDCPrice, ntn := fetched[common.NTNUSDCSymbol]
+	if _, ok := pairConfs[common.NTNATNSymbol]; !ok && atn && ntn {
 		ntnATNPrice, err := common.ComputeDerivedPrice(ntnUSDCPrice.Price, atnUSDCPrice.Price)
 		if err != nil {
-			e.logger.Error("failed to compute NTN-ATN price", "error", err)
-			return prices, nil
+			logger.Error("failed to compute NTN-ATN price", "error", err)
+			return prices
 		}
 		ntnATNPrice.Volume = atnUSDCPrice.Volume
 		prices = append(prices, ntnATNPrice)
 	}
-
-	return prices, nil
+	return prices
 }
 
 func (e *UniswapClient) Close() {
-	if e.ntnUSDCPairContract != nil {
-		e.ntnUSDCPairContract.Close()
-	}
-
-	if e.atnUSDCPairContract != nil {
-		e.atnUSDCPairContract.Close()
+	for _, p := range e.pairs {
+		p.Close()
 	}
 }
 
 type WrappedPair struct {
 	logger              hclog.Logger
 	symbol              string
-	baseTokenAddress    ecommon.Address // the base token address, it is ATN or NTN.
+	baseTokenAddress    ecommon.Address // the base token address, e.g. ATN or NTN.
+	baseDecimals        uint8
+	quoteDecimals       uint8
 	client              *ethclient.Client
-	chSwapEvent         chan *pair.PairSwap // chan of the swap event of the tracked pair
-	subSwapEvent        event.Subscription  // subscription of the swap event of the tracked pair.
+	chEvents            chan tp.Log           // chan of the swap and sync events of the tracked pair in their emission order.
+	subEvents           ethereum.Subscription // subscription of the swap and sync events of the tracked pair.
 	doneCh              chan struct{}
 	ticker              *time.Ticker
 	lostSync            bool
@@ -168,15 +268,45 @@ type WrappedPair struct {
 	token1         ecommon.Address
 	token0Reserves *big.Int
 	token1Reserves *big.Int
+
+	// the pool emits a sync event with the updated reserves ahead of each swap event in the same transaction, the
+	// reserves before the last sync event are kept to check the swap amounts against the reported reserves.
+	lastSync       *pair.PairSync
+	prevReserves0  *big.Int
+	prevReserves1  *big.Int
+	driftsDetected uint64
+
+	backfillBlocks uint64 // the number of recent blocks to backfill the order book from the historical events.
+	lastBlock      uint64 // the block of the last handled event, the events till it are not backfilled again.
+	backfilledTo   uint64 // the last backfilled block, the events till it from the subscriptions are skipped.
 }
 
-func NewWrappedPair(symbol string, baseTokenAddress ecommon.Address, quoteTokenAddress ecommon.Address, factoryAddress ecommon.Address,
-	url string, logger hclog.Logger) (*WrappedPair, error) {
-	client, err := ethclient.Dial(url)
+// pairEvent is a swap or a sync event of the pair parsed from its log.
+type pairEvent struct {
+	swap *pair.PairSwap
+	sync *pair.PairSync
+	log  tp.Log
+}
+
+func NewWrappedPair(pairConf config.PairConfig, factoryAddress ecommon.Address, conf *config.PluginConfig,
+	logger hclog.Logger) (*WrappedPair, error) {
+	symbol := pairConf.Symbol
+	baseTokenAddress := ecommon.HexToAddress(pairConf.BaseToken)
+	quoteTokenAddress := ecommon.HexToAddress(pairConf.QuoteToken)
+
+	client, err := common.DialEthClient(conf)
 	if err != nil {
 		logger.Error("cannot dial to L1 validator node", "error", err)
 		return nil, err
 	}
+
+	baseDecimals, quoteDecimals, err := resolveDecimals(client, pairConf)
+	if err != nil {
+		logger.Info("cannot resolve token decimals", "error", err, "symbol", symbol)
+		client.Close()
+		return nil, err
+	}
+
 	// bind uniswap factory contract, it manages the pair contracts in the AMM.
 	factoryContract, err := factory.NewFactory(factoryAddress, client)
 	if err != nil {
@@ -230,6 +360,8 @@ func NewWrappedPair(symbol string, baseTokenAddress ecommon.Address, quoteTokenA
 		logger:           logger,
 		symbol:           symbol,
 		baseTokenAddress: baseTokenAddress,
+		baseDecimals:     baseDecimals,
+		quoteDecimals:    quoteDecimals,
 		client:           client,
 		doneCh:           make(chan struct{}),
 		ticker:           time.NewTicker(time.Second * 1), // 1s ticker used to repair L1 connectivity if it was disconnected.
@@ -242,6 +374,9 @@ func NewWrappedPair(symbol string, baseTokenAddress ecommon.Address, quoteTokenA
 		token1Reserves: reserves.Reserve1,
 	}
 	wPair.orderBooks.SetCapacity(orderBookCapacity)
+	if conf.BackfillBlocks > 0 {
+		wPair.backfillBlocks = uint64(conf.BackfillBlocks)
+	}
 
 	if err = wPair.EventSubscription(); err != nil {
 		wPair.logger.Error("failed to subscribe to swap event", "error", err, "base", baseTokenAddress)
@@ -254,39 +389,81 @@ func NewWrappedPair(symbol string, baseTokenAddress ecommon.Address, quoteTokenA
 	return wPair, nil
 }
 
+// EventSubscription subscribes the swap and the sync events, the sync event is the source of truth of the pool's
+// reserves. They are subscribed by a single log filter, thus they are delivered in the order of (block, log index)
+// as they were emitted, and a swap event always comes after the sync event of its transaction.
 func (e *WrappedPair) EventSubscription() error {
-	// subscribe on-chain swap event.
-	chSwapEvent := make(chan *pair.PairSwap)
-	subSwapEvent, err := e.pairContract.WatchSwap(new(bind.WatchOpts), chSwapEvent, nil, nil)
+	chEvents := make(chan tp.Log)
+	subEvents, err := e.client.SubscribeFilterLogs(context.Background(), e.eventsQuery(), chEvents)
 	if err != nil {
-		e.logger.Error("cannot watch pair swap event", "error", err)
+		e.logger.Error("cannot watch pair swap and sync events", "error", err)
 		return err
 	}
 
-	e.chSwapEvent = chSwapEvent
-	e.subSwapEvent = subSwapEvent
-
+	e.chEvents = chEvents
+	e.subEvents = subEvents
 	return nil
 }
 
+// eventsQuery is the log filter of the swap and the sync events of the pair.
+func (e *WrappedPair) eventsQuery() ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		Addresses: []ecommon.Address{e.pairAddress},
+		Topics:    [][]ecommon.Hash{{swapTopic, syncTopic}},
+	}
+}
+
+// parseEvent parses the log of a swap or a sync event.
+func (e *WrappedPair) parseEvent(log tp.Log) (pairEvent, error) {
+	if len(log.Topics) == 0 {
+		return pairEvent{}, fmt.Errorf("log without topics")
+	}
+
+	ev := pairEvent{log: log}
+	var err error
+	switch log.Topics[0] {
+	case swapTopic:
+		ev.swap, err = e.pairContract.ParseSwap(log)
+	case syncTopic:
+		ev.sync, err = e.pairContract.ParseSync(log)
+	default:
+		err = fmt.Errorf("unknown event topic: %s", log.Topics[0])
+	}
+	return ev, err
+}
+
+// StartWatcher backfills the order book and then handles the events of the subscriptions, the backfill runs in the
+// watcher rather than on binding, thus the price fetching which binds the pair is not held by it. The pair serves the
+// instant price until the backfilled orders are aggregated.
 func (e *WrappedPair) StartWatcher() {
+	// the subscriptions are ahead of the backfill, thus no event is missed in between.
+	e.backfill()
+
 	for {
 		select {
 		case <-e.doneCh:
 			e.ticker.Stop()
 			e.logger.Info("uni-swap events watcher stopped")
 			return
-		case err := <-e.subSwapEvent.Err():
+		case err := <-e.subEvents.Err():
 			if err != nil {
-				e.logger.Info("subscription error of swap event", "error", err)
+				e.logger.Info("subscription error of swap and sync events", "error", err)
 				e.handleConnectivityError()
-				e.subSwapEvent.Unsubscribe()
+				e.subEvents.Unsubscribe()
 			}
-		case swapEvent := <-e.chSwapEvent:
-			e.logger.Debug("receiving a swap event", "event", swapEvent)
-			if err := e.handleSwapEvent(swapEvent); err != nil {
-				e.logger.Error("handle swap event failed", "error", err)
+		case log := <-e.chEvents:
+			// the events of a re-organized block are dropped, the reserves are corrected by the next sync event.
+			if log.Removed || log.BlockNumber <= e.backfilledTo {
+				continue
 			}
+			ev, err := e.parseEvent(log)
+			if err != nil {
+				e.logger.Error("cannot parse pair event", "error", err)
+				continue
+			}
+			e.logger.Debug("receiving a pair event", "block", log.BlockNumber, "index", log.Index)
+			e.lastBlock = log.BlockNumber
+			e.handleEvent(ev)
 
 		case <-e.ticker.C:
 			e.checkHealth()
@@ -298,76 +475,63 @@ func (e *WrappedPair) Close() {
 	if e.client != nil {
 		e.client.Close()
 	}
-	e.subSwapEvent.Unsubscribe()
+	e.subEvents.Unsubscribe()
 	e.doneCh <- struct{}{}
 }
 
-func (e *WrappedPair) handleSwapEvent(swap *pair.PairSwap) error {
-	var order Order
-
-	if swap.Amount0Out.Cmp(common.Zero) > 0 {
-		// Subtract Amount0Out from token0Reserves
-		temp0 := new(big.Int).Set(e.token0Reserves)
-		e.token0Reserves.Set(temp0.Sub(temp0, swap.Amount0Out))
+// handleEvent handles a swap or a sync event, the events are handled in the order of their emission.
+func (e *WrappedPair) handleEvent(ev pairEvent) {
+	if ev.sync != nil {
+		e.handleSyncEvent(ev.sync)
+		return
+	}
 
-		// Add Amount1In to token1Reserves
-		temp1 := new(big.Int).Set(e.token1Reserves)
-		e.token1Reserves.Set(temp1.Add(temp1, swap.Amount1In))
+	if err := e.handleSwapEvent(ev.swap); err != nil {
+		e.logger.Error("handle swap event failed", "error", err)
+	}
+}
 
-		// token0 is ATN/NTN, token1 is USDCx
-		if e.token0 == e.baseTokenAddress {
-			cryptoReserve := new(big.Int).Set(e.token0Reserves)
-			usdcReserve := new(big.Int).Set(e.token1Reserves)
+// handleSyncEvent takes the reserves reported by the pool. The sync events without swap, e.g. from Mint, Burn, Skim or
+// Sync, only update reserves.
+func (e *WrappedPair) handleSyncEvent(sync *pair.PairSync) {
+	e.priceMutex.Lock()
+	e.prevReserves0 = e.token0Reserves
+	e.prevReserves1 = e.token1Reserves
+	e.token0Reserves = new(big.Int).Set(sync.Reserve0)
+	e.token1Reserves = new(big.Int).Set(sync.Reserve1)
+	e.priceMutex.Unlock()
+	e.lastSync = sync
+}
 
-			price, err := ratio(cryptoReserve, usdcReserve)
-			if err != nil {
-				return err
-			}
-			order.cryptoToUsdcPrice = price
-			order.volume = swap.Amount1In
-		} else {
-			// token0 is USDCx, token1 is ATN/NTN
-			cryptoReserve := new(big.Int).Set(e.token1Reserves)
-			usdcReserve := new(big.Int).Set(e.token0Reserves)
-
-			price, err := ratio(cryptoReserve, usdcReserve)
-			if err != nil {
-				return err
-			}
-			order.cryptoToUsdcPrice = price
-			order.volume = swap.Amount0Out
-		}
+// handleSwapEvent computes the order of the swap from the reserves reported by the sync event of the same transaction,
+// which is handled ahead of it as the events are handled in their emission order. The swap is dropped if the sync event
+// of it was missed.
+func (e *WrappedPair) handleSwapEvent(swap *pair.PairSwap) error {
+	if e.lastSync == nil || e.lastSync.Raw.TxHash != swap.Raw.TxHash || e.lastSync.Raw.Index > swap.Raw.Index {
+		e.logger.Info("drop swap event without sync event", "tx", swap.Raw.TxHash)
+		return nil
 	}
 
-	if swap.Amount1Out.Cmp(common.Zero) > 0 {
-		// update reserves.
-		temp0 := new(big.Int).Set(e.token0Reserves)
-		temp1 := new(big.Int).Set(e.token1Reserves)
-		e.token0Reserves.Set(temp0.Add(temp0, swap.Amount0In))
-		e.token1Reserves.Set(temp1.Sub(temp1, swap.Amount1Out))
+	e.checkDrift(swap)
 
-		// token1 is ATN/NTN, token0 is USDCx
-		if e.token1 == e.baseTokenAddress {
-			cryptoReserve := new(big.Int).Set(e.token1Reserves)
-			usdcReserve := new(big.Int).Set(e.token0Reserves)
-			price, err := ratio(cryptoReserve, usdcReserve)
-			if err != nil {
-				return err
-			}
-			order.cryptoToUsdcPrice = price
-			order.volume = swap.Amount0In
-		} else {
-			// token1 is USDCx, token0 is ATN/NTN
-			cryptoReserve := new(big.Int).Set(e.token0Reserves)
-			usdcReserve := new(big.Int).Set(e.token1Reserves)
-			price, err := ratio(cryptoReserve, usdcReserve)
-			if err != nil {
-				return err
-			}
-			order.cryptoToUsdcPrice = price
-			order.volume = swap.Amount1Out
-		}
+	var order Order
+	var cryptoReserve, usdcReserve *big.Int
+	if e.token0 == e.baseTokenAddress {
+		// token0 is ATN/NTN, token1 is USDCx
+		cryptoReserve = new(big.Int).Set(e.token0Reserves)
+		usdcReserve = new(big.Int).Set(e.token1Reserves)
+	} else {
+		// token0 is USDCx, token1 is ATN/NTN
+		cryptoReserve = new(big.Int).Set(e.token1Reserves)
+		usdcReserve = new(big.Int).Set(e.token0Reserves)
+	}
+
+	price, err := ratio(cryptoReserve, usdcReserve, e.baseDecimals, e.quoteDecimals)
+	if err != nil {
+		return err
 	}
+	order.cryptoToUsdcPrice = price
+	order.volume = usdcVolume(swap, e.token0 != e.baseTokenAddress)
 
 	aggPrice, volumes, err := aggregatePrice(&e.orderBooks, order)
 	if err != nil {
@@ -381,6 +545,109 @@ func (e *WrappedPair) handleSwapEvent(swap *pair.PairSwap) error {
 	return nil
 }
 
+// usdcVolume returns the traded amount of USDCx of the swap.
+func usdcVolume(swap *pair.PairSwap, usdcIsToken0 bool) *big.Int {
+	if usdcIsToken0 {
+		return new(big.Int).Add(swap.Amount0In, swap.Amount0Out)
+	}
+	return new(big.Int).Add(swap.Amount1In, swap.Amount1Out)
+}
+
+// checkDrift computes the reserves from the ones before the sync event with the swap amounts, and compares them with
+// the reserves reported by the sync event, a drift happens if any event that changes the pool was missed.
+func (e *WrappedPair) checkDrift(swap *pair.PairSwap) {
+	if e.prevReserves0 == nil || e.prevReserves1 == nil {
+		return
+	}
+
+	computed0 := new(big.Int).Add(e.prevReserves0, swap.Amount0In)
+	computed0.Sub(computed0, swap.Amount0Out)
+	computed1 := new(big.Int).Add(e.prevReserves1, swap.Amount1In)
+	computed1.Sub(computed1, swap.Amount1Out)
+
+	if computed0.Cmp(e.token0Reserves) != 0 || computed1.Cmp(e.token1Reserves) != 0 {
+		e.driftsDetected++
+		e.logger.Warn("reserves drift detected", "symbol", e.symbol, "tx", swap.Raw.TxHash,
+			"computed0", computed0, "reported0", e.token0Reserves, "computed1", computed1, "reported1", e.token1Reserves,
+			"drifts", e.driftsDetected)
+	}
+}
+
+// backfill fetches the swap and sync events of the recent blocks, and replays them to the order book, thus the VWAP
+// is meaningful since the pair is bound or re-synced rather than falling back to the instant reserve ratio.
+func (e *WrappedPair) backfill() {
+	if e.backfillBlocks == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backfillTimeout)
+	defer cancel()
+
+	head, err := e.client.BlockNumber(ctx)
+	if err != nil {
+		e.logger.Info("cannot get the latest block for backfill", "error", err)
+		return
+	}
+
+	start := uint64(0)
+	if head >= e.backfillBlocks {
+		start = head - e.backfillBlocks + 1
+	}
+	// the events till the last handled block are in the order book already.
+	if e.lastBlock >= start {
+		start = e.lastBlock + 1
+	}
+	if start > head {
+		return
+	}
+
+	query := e.eventsQuery()
+	query.FromBlock = new(big.Int).SetUint64(start)
+	query.ToBlock = new(big.Int).SetUint64(head)
+	logs, err := e.client.FilterLogs(ctx, query)
+	if err != nil {
+		e.logger.Info("cannot filter swap and sync events for backfill", "error", err)
+		return
+	}
+
+	events := make([]pairEvent, 0, len(logs))
+	for _, log := range logs {
+		ev, err := e.parseEvent(log)
+		if err != nil {
+			e.logger.Info("cannot parse backfilled pair event", "error", err)
+			continue
+		}
+		events = append(events, ev)
+	}
+
+	e.replay(events)
+	e.backfilledTo = head
+	e.lastBlock = head
+	e.logger.Info("backfilled order book", "symbol", e.symbol, "from", start, "to", head, "events", len(events),
+		"orders", len(e.orderBooks.Values()))
+}
+
+// replay handles the historical events in the order of their emission.
+func (e *WrappedPair) replay(events []pairEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].log.BlockNumber != events[j].log.BlockNumber {
+			return events[i].log.BlockNumber < events[j].log.BlockNumber
+		}
+		return events[i].log.Index < events[j].log.Index
+	})
+
+	first := true
+	for _, ev := range events {
+		e.handleEvent(ev)
+		// the reserves before the first replayed sync event are unknown, skip the drift check of it.
+		if ev.sync != nil && first {
+			e.prevReserves0 = nil
+			e.prevReserves1 = nil
+			first = false
+		}
+	}
+}
+
 func (e *WrappedPair) updatePrice(price string, volumes *big.Int) {
 	e.priceMutex.Lock()
 	defer e.priceMutex.Unlock()
@@ -400,14 +667,29 @@ func (e *WrappedPair) checkHealth() {
 			return
 		}
 
-		// re-sync reserves from pools.
+		// re-sync reserves from pools, the events emitted during the disconnection are missed.
 		reserves, err := e.pairContract.GetReserves(nil)
 		if err != nil {
 			e.logger.Error("re-sync pair contract", "error", err)
 			return
 		}
+
+		e.priceMutex.Lock()
+		if reserves.Reserve0.Cmp(e.token0Reserves) != 0 || reserves.Reserve1.Cmp(e.token1Reserves) != 0 {
+			e.driftsDetected++
+			e.logger.Warn("reserves drift detected on re-sync", "symbol", e.symbol, "local0", e.token0Reserves,
+				"reported0", reserves.Reserve0, "local1", e.token1Reserves, "reported1", reserves.Reserve1,
+				"drifts", e.driftsDetected)
+		}
 		e.token0Reserves = reserves.Reserve0
 		e.token1Reserves = reserves.Reserve1
+		e.priceMutex.Unlock()
+		e.prevReserves0 = nil
+		e.prevReserves1 = nil
+		e.lastSync = nil
+
+		// catch up the swaps happened during the disconnection.
+		e.backfill()
 
 		e.lostSync = false
 		return
@@ -427,18 +709,18 @@ func (e *WrappedPair) instantPrice() (common.Price, error) {
 	var usdcReserve *big.Int
 
 	if e.token0 == e.baseTokenAddress {
-		// ATN or NTN is token0, compute ATN-USDC or NTN-USDC ratio with reserves0 and reserves1.
+		// base token is token0, compute the base to quote ratio with reserves0 and reserves1.
 		cryptoReserve = e.token0Reserves
 		usdcReserve = e.token1Reserves
 	} else {
-		// ATN or NTN is token1, compute ATN-USDC or NTN-USDC ratio with reserves0 and reserves1.
+		// base token is token1, compute the base to quote ratio with reserves1 and reserves0.
 		cryptoReserve = e.token1Reserves
 		usdcReserve = e.token0Reserves
 	}
 
-	p, err := ratio(cryptoReserve, usdcReserve)
+	p, err := ratio(cryptoReserve, usdcReserve, e.baseDecimals, e.quoteDecimals)
 	if err != nil {
-		e.logger.Error("cannot compute exchange ratio of ATN-USDC", "error", err)
+		e.logger.Error("cannot compute exchange ratio", "symbol", e.symbol, "error", err)
 		return price, err
 	}
 
@@ -460,7 +742,7 @@ func (e *WrappedPair) aggregatedPrice() (common.Price, error) {
 	return *e.lastAggregatedPrice, nil
 }
 
-func ratio(cryptoReserve, usdcReserve *big.Int) (decimal.Decimal, error) {
+func ratio(cryptoReserve, usdcReserve *big.Int, cryptoDecimals, usdcDecimals uint8) (decimal.Decimal, error) {
 	var r decimal.Decimal
 
 	if cryptoReserve.Cmp(common.Zero) <= 0 || usdcReserve.Cmp(common.Zero) <= 0 {
@@ -469,8 +751,8 @@ func ratio(cryptoReserve, usdcReserve *big.Int) (decimal.Decimal, error) {
 
 	// ratio == (usdcReserve/usdcDecimals) / (cryptoReserve/cryptoDecimals)
 	//       == (usdcReserve*cryptoDecimals) / (cryptoReserve*usdcDecimals)
-	scaledCryptoReserve := new(big.Int).Mul(cryptoReserve, big.NewInt(int64(math.Pow(10, float64(common.USDCDecimals)))))
-	scaledUsdcReserve := new(big.Int).Mul(usdcReserve, big.NewInt(int64(math.Pow(10, float64(common.AutonityCryptoDecimals)))))
+	scaledCryptoReserve := new(big.Int).Mul(cryptoReserve, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(usdcDecimals)), nil))
+	scaledUsdcReserve := new(big.Int).Mul(usdcReserve, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(cryptoDecimals)), nil))
 
 	// Calculate the exchange ratio as a big.Rat
 	price := new(big.Rat).SetFrac(scaledUsdcReserve, scaledCryptoReserve)
@@ -3,11 +3,17 @@ package common
 import (
 	config2 "autonity-oracle/config"
 	"autonity-oracle/plugins/common"
+	"autonity-oracle/plugins/crypto_uniswap/contracts/pair"
 	"autonity-oracle/types"
-	"github.com/shopspring/decimal"
-	"github.com/stretchr/testify/require"
+	"math/big"
 	"testing"
 	"time"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	tp "github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewUniswapClientWithFullMarkets(t *testing.T) {
@@ -20,10 +26,8 @@ func TestNewUniswapClientWithFullMarkets(t *testing.T) {
 		Timeout:            10,
 		DataUpdateInterval: 30,
 		// set the ATN token address with an un exist value, to let the market cannot be discovered.
-		ATNTokenAddress:  "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2",
-		NTNTokenAddress:  types.AutonityContractAddress.Hex(),
-		USDCTokenAddress: "0xB855D5e83363A4494e09f0Bb3152A70d3f161940",
-		SwapAddress:      "0x218F76e357594C82Cc29A88B90dd67b180827c88",
+		Pairs:       DefaultPairs(types.AutonityContractAddress.Hex(), "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2", "0xB855D5e83363A4494e09f0Bb3152A70d3f161940"),
+		SwapAddress: "0x218F76e357594C82Cc29A88B90dd67b180827c88",
 	}
 
 	client, err := NewUniswapClient(&config)
@@ -33,7 +37,7 @@ func TestNewUniswapClientWithFullMarkets(t *testing.T) {
 
 	for i := 0; i < 5; i++ {
 		time.Sleep(1 * time.Second)
-		prices, err := client.FetchPrice(supportedSymbols)
+		prices, err := client.FetchPrice(common.DefaultCryptoSymbols)
 		require.NoError(t, err)
 		require.Equal(t, 3, len(prices))
 		for _, price := range prices {
@@ -53,11 +57,9 @@ func TestNewUniswapClientWithATNMarket(t *testing.T) {
 		Endpoint:           "rpc-internal-1.piccadilly.autonity.org/ws",
 		Timeout:            10,
 		DataUpdateInterval: 30,
-		ATNTokenAddress:    "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2",
 		// set the NTN token address with an un exist value, to let the market cannot be discovered.
-		NTNTokenAddress:  "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1df",
-		USDCTokenAddress: "0xB855D5e83363A4494e09f0Bb3152A70d3f161940",
-		SwapAddress:      "0x218F76e357594C82Cc29A88B90dd67b180827c88",
+		Pairs:       DefaultPairs("0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1df", "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2", "0xB855D5e83363A4494e09f0Bb3152A70d3f161940"),
+		SwapAddress: "0x218F76e357594C82Cc29A88B90dd67b180827c88",
 	}
 
 	client, err := NewUniswapClient(&config)
@@ -89,10 +91,8 @@ func TestNewUniswapClientWithNTNMarket(t *testing.T) {
 		Timeout:            10,
 		DataUpdateInterval: 30,
 		// set the ATN token address with an un exist value, to let the market cannot be discovered.
-		ATNTokenAddress:  "0xaE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d3",
-		NTNTokenAddress:  "0xbE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d3",
-		USDCTokenAddress: "0xc855D5e83363A4494e09f0Bb3152A70d3f161941",
-		SwapAddress:      "0x218F76e357594C82Cc29A88B90dd67b180827c88",
+		Pairs:       DefaultPairs("0xbE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d3", "0xaE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d3", "0xc855D5e83363A4494e09f0Bb3152A70d3f161941"),
+		SwapAddress: "0x218F76e357594C82Cc29A88B90dd67b180827c88",
 	}
 
 	client, err := NewUniswapClient(&config)
@@ -119,10 +119,8 @@ func TestNewUniswapClientWithNoMarkets(t *testing.T) {
 		Timeout:            10,
 		DataUpdateInterval: 30,
 		// set the ATN token address with an un exist value, to let the market cannot be discovered.
-		ATNTokenAddress:  "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d3",
-		NTNTokenAddress:  types.AutonityContractAddress.Hex(),
-		USDCTokenAddress: "0xB855D5e83363A4494e09f0Bb3152A70d3f161940",
-		SwapAddress:      "0x218F76e357594C82Cc29A88B90dd67b180827c88",
+		Pairs:       DefaultPairs(types.AutonityContractAddress.Hex(), "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d3", "0xB855D5e83363A4494e09f0Bb3152A70d3f161940"),
+		SwapAddress: "0x218F76e357594C82Cc29A88B90dd67b180827c88",
 	}
 
 	client, err := NewUniswapClient(&config)
@@ -153,9 +151,7 @@ func TestNewUniswapClientWithWrongSwapAddress(t *testing.T) {
 		Endpoint:           "rpc-internal-1.piccadilly.autonity.org/ws",
 		Timeout:            10,
 		DataUpdateInterval: 30,
-		ATNTokenAddress:    "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2",
-		NTNTokenAddress:    types.AutonityContractAddress.Hex(),
-		USDCTokenAddress:   "0xB855D5e83363A4494e09f0Bb3152A70d3f161940",
+		Pairs:              DefaultPairs(types.AutonityContractAddress.Hex(), "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2", "0xB855D5e83363A4494e09f0Bb3152A70d3f161940"),
 		// set to wrong swap address.
 		SwapAddress: "0x218F76e357594C82Cc29A88B90dd67b180827c89",
 	}
@@ -183,10 +179,8 @@ func TestNewUniswapClientWithWrongRPCEndpoint(t *testing.T) {
 		Timeout:            10,
 		DataUpdateInterval: 30,
 		// set the ATN token address with an un exist value, to let the market cannot be discovered.
-		ATNTokenAddress:  "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2",
-		NTNTokenAddress:  types.AutonityContractAddress.Hex(),
-		USDCTokenAddress: "0xB855D5e83363A4494e09f0Bb3152A70d3f161940",
-		SwapAddress:      "0x218F76e357594C82Cc29A88B90dd67b180827c88",
+		Pairs:       DefaultPairs(types.AutonityContractAddress.Hex(), "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2", "0xB855D5e83363A4494e09f0Bb3152A70d3f161940"),
+		SwapAddress: "0x218F76e357594C82Cc29A88B90dd67b180827c88",
 	}
 
 	client, err := NewUniswapClient(&config)
@@ -197,8 +191,197 @@ func TestNewUniswapClientWithWrongRPCEndpoint(t *testing.T) {
 	// The client would not panic with the wrong rpc endpoint.
 	for i := 0; i < 5; i++ {
 		time.Sleep(1 * time.Second)
-		prices, err := client.FetchPrice(supportedSymbols)
+		prices, err := client.FetchPrice(common.DefaultCryptoSymbols)
 		require.NoError(t, err)
 		require.Equal(t, 0, len(prices))
 	}
 }
+
+func newTestPair(reserve0, reserve1 int64) *WrappedPair {
+	p := &WrappedPair{
+		logger:           hclog.NewNullLogger(),
+		symbol:           common.NTNUSDCSymbol,
+		baseTokenAddress: ecommon.HexToAddress("0x01"),
+		baseDecimals:     common.AutonityCryptoDecimals,
+		quoteDecimals:    common.USDCDecimals,
+		token0:           ecommon.HexToAddress("0x01"),
+		token1:           ecommon.HexToAddress("0x02"),
+		token0Reserves:   big.NewInt(reserve0),
+		token1Reserves:   big.NewInt(reserve1),
+	}
+	p.orderBooks.SetCapacity(orderBookCapacity)
+	return p
+}
+
+func swapEvent(tx ecommon.Hash, block uint64, index uint, in0, in1, out0, out1 int64) *pair.PairSwap {
+	return &pair.PairSwap{
+		Amount0In:  big.NewInt(in0),
+		Amount1In:  big.NewInt(in1),
+		Amount0Out: big.NewInt(out0),
+		Amount1Out: big.NewInt(out1),
+		Raw:        tp.Log{TxHash: tx, BlockNumber: block, Index: index},
+	}
+}
+
+func syncEvent(tx ecommon.Hash, block uint64, index uint, reserve0, reserve1 int64) *pair.PairSync {
+	return &pair.PairSync{
+		Reserve0: big.NewInt(reserve0),
+		Reserve1: big.NewInt(reserve1),
+		Raw:      tp.Log{TxHash: tx, BlockNumber: block, Index: index},
+	}
+}
+
+func TestWrappedPairReservesFromSyncEvents(t *testing.T) {
+	// the amounts are scaled down to fit in int64, NTN has 18 decimals while USDC has 6 decimals.
+	ntn := int64(1e16)
+	usdc := int64(1e6)
+
+	t.Run("swap after its sync event", func(t *testing.T) {
+		p := newTestPair(100*ntn, 1000*usdc)
+		tx := ecommon.HexToHash("0x1")
+		// sell 10 NTN for 90 USDC.
+		p.handleSyncEvent(syncEvent(tx, 1, 0, 110*ntn, 910*usdc))
+		require.NoError(t, p.handleSwapEvent(swapEvent(tx, 1, 1, 10*ntn, 0, 0, 90*usdc)))
+		require.Equal(t, uint64(0), p.driftsDetected)
+
+		price, err := p.aggregatedPrice()
+		require.NoError(t, err)
+		require.Equal(t, "90000000", price.Volume)
+		expected, err := ratio(big.NewInt(110*ntn), big.NewInt(910*usdc), common.AutonityCryptoDecimals, common.USDCDecimals)
+		require.NoError(t, err)
+		require.Equal(t, expected.String(), price.Price)
+	})
+
+	t.Run("reserves changed by missed events are detected", func(t *testing.T) {
+		p := newTestPair(100*ntn, 1000*usdc)
+		tx := ecommon.HexToHash("0x1")
+		// a mint happened in between which was not tracked, the sync event reports the true reserves.
+		p.handleSyncEvent(syncEvent(tx, 1, 0, 220*ntn, 1910*usdc))
+		require.NoError(t, p.handleSwapEvent(swapEvent(tx, 1, 1, 10*ntn, 0, 0, 90*usdc)))
+		require.Equal(t, uint64(1), p.driftsDetected)
+		require.Equal(t, big.NewInt(220*ntn), p.token0Reserves)
+		require.Equal(t, big.NewInt(1910*usdc), p.token1Reserves)
+	})
+
+	t.Run("sync events without swap update reserves", func(t *testing.T) {
+		p := newTestPair(100*ntn, 1000*usdc)
+		p.handleSyncEvent(syncEvent(ecommon.HexToHash("0x1"), 1, 0, 200*ntn, 2000*usdc))
+		require.Nil(t, p.lastAggregatedPrice)
+		price, err := p.aggregatedPrice()
+		require.NoError(t, err)
+		expected, err := ratio(big.NewInt(200*ntn), big.NewInt(2000*usdc), common.AutonityCryptoDecimals, common.USDCDecimals)
+		require.NoError(t, err)
+		require.Equal(t, expected.String(), price.Price)
+	})
+
+	t.Run("swap whose sync event was missed is dropped", func(t *testing.T) {
+		p := newTestPair(100*ntn, 1000*usdc)
+		p.handleSyncEvent(syncEvent(ecommon.HexToHash("0x1"), 1, 0, 110*ntn, 910*usdc))
+		require.NoError(t, p.handleSwapEvent(swapEvent(ecommon.HexToHash("0x2"), 2, 1, 10*ntn, 0, 0, 90*usdc)))
+		require.Nil(t, p.lastAggregatedPrice)
+	})
+}
+
+func TestWrappedPairReplay(t *testing.T) {
+	ntn := int64(1e16)
+	usdc := int64(1e6)
+	p := newTestPair(500*ntn, 5000*usdc)
+
+	tx1 := ecommon.HexToHash("0x1")
+	tx2 := ecommon.HexToHash("0x2")
+	tx3 := ecommon.HexToHash("0x3")
+	// the historical events are sorted into their emission order by the replay.
+	events := []pairEvent{
+		{swap: swapEvent(tx1, 10, 1, 10*ntn, 0, 0, 90*usdc)},
+		{swap: swapEvent(tx3, 12, 3, 0, 200*usdc, 20*ntn, 0)},
+		{sync: syncEvent(tx1, 10, 0, 110*ntn, 910*usdc)},
+		{sync: syncEvent(tx2, 11, 0, 220*ntn, 1820*usdc)}, // a mint without swap.
+		{sync: syncEvent(tx3, 12, 2, 200*ntn, 2020*usdc)},
+	}
+	for i := range events {
+		if events[i].swap != nil {
+			events[i].log = events[i].swap.Raw
+		} else {
+			events[i].log = events[i].sync.Raw
+		}
+	}
+
+	p.replay(events)
+	require.Equal(t, 2, len(p.orderBooks.Values()))
+	require.Equal(t, uint64(0), p.driftsDetected)
+	require.Equal(t, big.NewInt(200*ntn), p.token0Reserves)
+	require.Equal(t, big.NewInt(2020*usdc), p.token1Reserves)
+
+	price, err := p.aggregatedPrice()
+	require.NoError(t, err)
+	require.Equal(t, "290000000", price.Volume)
+}
+
+func TestWrappedPairEventLogs(t *testing.T) {
+	ntn := int64(1e16)
+	usdc := int64(1e6)
+	p := newTestPair(100*ntn, 1000*usdc)
+	contract, err := pair.NewPair(ecommon.HexToAddress("0x03"), nil)
+	require.NoError(t, err)
+	p.pairContract = contract
+
+	parsed, err := pair.PairMetaData.GetAbi()
+	require.NoError(t, err)
+	tx := ecommon.HexToHash("0x1")
+	syncData, err := parsed.Events["Sync"].Inputs.NonIndexed().Pack(big.NewInt(110*ntn), big.NewInt(910*usdc))
+	require.NoError(t, err)
+	swapData, err := parsed.Events["Swap"].Inputs.NonIndexed().Pack(big.NewInt(10*ntn), big.NewInt(0), big.NewInt(0),
+		big.NewInt(90*usdc))
+	require.NoError(t, err)
+	sender := ecommon.BytesToHash(ecommon.HexToAddress("0x04").Bytes())
+	logs := []tp.Log{
+		{Topics: []ecommon.Hash{syncTopic}, Data: syncData, TxHash: tx, BlockNumber: 1, Index: 0},
+		{Topics: []ecommon.Hash{swapTopic, sender, sender}, Data: swapData, TxHash: tx, BlockNumber: 1, Index: 1},
+	}
+
+	// the events delivered by the log subscription in their emission order are matched without buffering.
+	for _, log := range logs {
+		ev, err := p.parseEvent(log)
+		require.NoError(t, err)
+		p.handleEvent(ev)
+	}
+	require.Equal(t, uint64(0), p.driftsDetected)
+	price, err := p.aggregatedPrice()
+	require.NoError(t, err)
+	require.Equal(t, "90000000", price.Volume)
+
+	_, err = p.parseEvent(tp.Log{Topics: []ecommon.Hash{ecommon.HexToHash("0x05")}})
+	require.Error(t, err)
+}
+
+func TestResolvePairConfs(t *testing.T) {
+	ntn, atn, usdc := "0x0000000000000000000000000000000000000001", "0x0000000000000000000000000000000000000002",
+		"0x0000000000000000000000000000000000000003"
+
+	t.Run("default pairs derive NTN-ATN", func(t *testing.T) {
+		confs, symbols, err := resolvePairConfs(DefaultPairs(ntn, atn, usdc))
+		require.NoError(t, err)
+		require.Equal(t, 2, len(confs))
+		require.Equal(t, []string{common.ATNUSDCSymbol, common.NTNUSDCSymbol, common.NTNATNSymbol}, symbols)
+	})
+
+	t.Run("generic pair", func(t *testing.T) {
+		_, symbols, err := resolvePairConfs([]config2.PairConfig{{Symbol: "WETH-USDC", BaseToken: atn, QuoteToken: usdc}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"WETH-USDC"}, symbols)
+	})
+
+	t.Run("invalid pairs", func(t *testing.T) {
+		invalids := [][]config2.PairConfig{
+			nil,
+			{{BaseToken: atn, QuoteToken: usdc}},
+			{{Symbol: "WETH-USDC", BaseToken: "weth", QuoteToken: usdc}},
+			{{Symbol: "WETH-USDC", BaseToken: atn, QuoteToken: usdc, BaseDecimals: 256}},
+			{{Symbol: "WETH-USDC", BaseToken: atn, QuoteToken: usdc}, {Symbol: "WETH-USDC", BaseToken: ntn, QuoteToken: usdc}},
+		}
+		for _, pairs := range invalids {
+			_, _, err := resolvePairConfs(pairs)
+			require.Error(t, err, pairs)
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/plugins/crypto_uniswap/contracts/factoryv3"
+	"autonity-oracle/plugins/crypto_uniswap/contracts/poolv3"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	q192           = new(big.Int).Lsh(big.NewInt(1), 192)
+	floatPrecision = uint(256)
+	tickBase, _    = new(big.Float).SetPrec(floatPrecision).SetString("1.0001")
+
+	errTWAPUnavailable = errors.New("TWAP is not available")
+)
+
+// UniswapV3Client prices the crypto symbols from uniswap V3 pools, it reads the TWAP from the pool's observations
+// rather than tracking the swap events, and it reports the in range liquidity of the pool as the volume.
+type UniswapV3Client struct {
+	conf   *config.PluginConfig
+	logger hclog.Logger
+
+	pairConfs map[string]config.PairConfig
+	symbols   []string
+	client    *ethclient.Client
+	pools     map[string]*V3Pool
+}
+
+// V3Pool is a uniswap V3 pool of an ERC20 token pair on a fee tier.
+type V3Pool struct {
+	symbol        string
+	address       ecommon.Address
+	contract      *poolv3.PoolV3
+	baseIsToken0  bool
+	baseDecimals  uint8
+	quoteDecimals uint8
+}
+
+func NewUniswapV3Client(conf *config.PluginConfig) (*UniswapV3Client, error) {
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   conf.Name,
+		Level:  hclog.Info,
+		Output: os.Stdout,
+	})
+
+	pairConfs, symbols, err := resolvePairConfs(conf.Pairs)
+	if err != nil {
+		logger.Error("invalid pairs config", "error", err)
+		return nil, err
+	}
+
+	// the pools are resolved during runtime on-demand, as they could be created after the plugin is loaded.
+	return &UniswapV3Client{
+		conf:      conf,
+		logger:    logger,
+		pairConfs: pairConfs,
+		symbols:   symbols,
+		pools:     make(map[string]*V3Pool),
+	}, nil
+}
+
+func (e *UniswapV3Client) KeyRequired() bool {
+	return false
+}
+
+func (e *UniswapV3Client) AvailableSymbols() ([]string, error) {
+	return e.symbols, nil
+}
+
+// FetchPrice fetch the price of the supported symbols of this plugin.
+func (e *UniswapV3Client) FetchPrice(symbols []string) (common.Prices, error) {
+	return fetchPairPrices(symbols, e.pairConfs, e.fetch, e.logger), nil
+}
+
+func (e *UniswapV3Client) fetch(symbol string) (common.Price, error) {
+	pool, err := e.pool(symbol)
+	if err != nil {
+		return common.Price{}, err
+	}
+
+	price, err := pool.price(uint32(e.conf.TWAPWindow)) // nolint
+	if errors.Is(err, errTWAPUnavailable) {
+		// the pool is still bound, it could have enough observations for the window on the next fetching.
+		e.logger.Warn("skip the pool for this sample", "symbol", symbol, "pool", pool.address, "error", err)
+		return common.Price{}, err
+	}
+	if err != nil {
+		// the connectivity could be lost, re-dial and re-bind the pools on next fetching.
+		e.Close()
+		return common.Price{}, err
+	}
+	return price, nil
+}
+
+// pool returns the bound pool of the symbol, it dials the L1 node and binds the pool if they are not yet ready.
+func (e *UniswapV3Client) pool(symbol string) (*V3Pool, error) {
+	if p, ok := e.pools[symbol]; ok {
+		return p, nil
+	}
+
+	if e.client == nil {
+		client, err := common.DialEthClient(e.conf)
+		if err != nil {
+			e.logger.Error("cannot dial to L1 validator node", "error", err)
+			return nil, err
+		}
+		e.client = client
+	}
+
+	pairConf := e.pairConfs[symbol]
+	baseDecimals, quoteDecimals, err := resolveDecimals(e.client, pairConf)
+	if err != nil {
+		e.logger.Info("cannot resolve token decimals", "symbol", symbol, "error", err)
+		return nil, err
+	}
+
+	p, err := NewV3Pool(e.client, symbol, ecommon.HexToAddress(pairConf.BaseToken), ecommon.HexToAddress(pairConf.QuoteToken),
+		ecommon.HexToAddress(e.conf.SwapAddress), e.conf.FeeTier, baseDecimals, quoteDecimals)
+	if err != nil {
+		e.logger.Info("cannot bind uniswap V3 pool", "symbol", symbol, "fee", e.conf.FeeTier, "error", err)
+		return nil, err
+	}
+
+	e.logger.Info("bind uniswap V3 pool", "symbol", symbol, "fee", e.conf.FeeTier, "address", p.address)
+	e.pools[symbol] = p
+	return p, nil
+}
+
+func (e *UniswapV3Client) Close() {
+	if e.client != nil {
+		e.client.Close()
+		e.client = nil
+	}
+	e.pools = make(map[string]*V3Pool)
+}
+
+// NewV3Pool resolves the pool of the token pair on the fee tier from the factory, and binds it.
+func NewV3Pool(client *ethclient.Client, symbol string, baseToken, quoteToken, factoryAddress ecommon.Address, feeTier int,
+	baseDecimals, quoteDecimals uint8) (*V3Pool, error) {
+	factoryContract, err := factoryv3.NewFactoryV3(factoryAddress, client)
+	if err != nil {
+		return nil, err
+	}
+
+	poolAddress, err := factoryContract.GetPool(nil, baseToken, quoteToken, big.NewInt(int64(feeTier)))
+	if err != nil {
+		return nil, err
+	}
+
+	if poolAddress == (ecommon.Address{}) {
+		return nil, fmt.Errorf("pool is not created yet, pair: %s, %s, fee: %d", baseToken, quoteToken, feeTier)
+	}
+
+	poolContract, err := poolv3.NewPoolV3(poolAddress, client)
+	if err != nil {
+		return nil, err
+	}
+
+	token0, err := poolContract.Token0(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &V3Pool{
+		symbol:        symbol,
+		address:       poolAddress,
+		contract:      poolContract,
+		baseIsToken0:  token0 == baseToken,
+		baseDecimals:  baseDecimals,
+		quoteDecimals: quoteDecimals,
+	}, nil
+}
+
+// price returns the TWAP of the pool over the window from its observations, or the spot price from slot0 if the
+// window is 0. The volume is the in range liquidity. The pool is not priced by slot0 in place of an unavailable TWAP,
+// e.g. the pool does not have enough observations for the window, as the spot price can be moved within a block.
+func (p *V3Pool) price(window uint32) (common.Price, error) {
+	var price decimal.Decimal
+	var err error
+	if window > 0 {
+		if price, err = p.twap(window); err != nil {
+			return common.Price{}, err
+		}
+	} else {
+		slot0, e := p.contract.Slot0(nil)
+		if e != nil {
+			return common.Price{}, e
+		}
+		price, err = sqrtPriceX96ToPrice(slot0.SqrtPriceX96, p.baseIsToken0, p.baseDecimals, p.quoteDecimals)
+		if err != nil {
+			return common.Price{}, err
+		}
+	}
+
+	liquidity, err := p.contract.Liquidity(nil)
+	if err != nil {
+		return common.Price{}, err
+	}
+
+	return common.Price{
+		Symbol: p.symbol,
+		Price:  price.String(),
+		Volume: liquidity.String(),
+	}, nil
+}
+
+// twap returns the TWAP of the pool over the window, the observe call reverted by the pool, e.g. it does not have the
+// observations as old as the window, and the invalid observations are reported as errTWAPUnavailable, while the other
+// errors, e.g. the lost connectivity, are returned as they are.
+func (p *V3Pool) twap(window uint32) (decimal.Decimal, error) {
+	observations, err := p.contract.Observe(nil, []uint32{window, 0})
+	if err != nil {
+		if strings.Contains(err.Error(), "execution reverted") {
+			return decimal.Decimal{}, fmt.Errorf("%w: %v", errTWAPUnavailable, err)
+		}
+		return decimal.Decimal{}, err
+	}
+
+	tick, err := meanTick(observations.TickCumulatives, window)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("%w: %v", errTWAPUnavailable, err)
+	}
+	return tickToPrice(tick, p.baseIsToken0, p.baseDecimals, p.quoteDecimals)
+}
+
+// meanTick computes the arithmetic mean tick over the window from the tick cumulatives, it rounds to negative infinity
+// as the uniswap V3 oracle library does.
+func meanTick(tickCumulatives []*big.Int, window uint32) (int64, error) {
+	if len(tickCumulatives) != 2 || window == 0 {
+		return 0, fmt.Errorf("invalid tick cumulatives")
+	}
+
+	delta := new(big.Int).Sub(tickCumulatives[1], tickCumulatives[0])
+	w := big.NewInt(int64(window))
+	tick, mod := new(big.Int).QuoRem(delta, w, new(big.Int))
+	if delta.Sign() < 0 && mod.Sign() != 0 {
+		tick.Sub(tick, big.NewInt(1))
+	}
+	return tick.Int64(), nil
+}
+
+// tickToPrice converts the tick to the price of the base token in quote token, the price of token0 in token1 at a tick
+// is 1.0001^tick in their smallest units.
+func tickToPrice(tick int64, baseIsToken0 bool, baseDecimals, quoteDecimals uint8) (decimal.Decimal, error) {
+	abs := tick
+	if abs < 0 {
+		abs = -abs
+	}
+
+	// exponentiation by squaring.
+	result := new(big.Float).SetPrec(floatPrecision).SetInt64(1)
+	base := new(big.Float).Copy(tickBase)
+	for abs > 0 {
+		if abs&1 == 1 {
+			result.Mul(result, base)
+		}
+		base.Mul(base, base)
+		abs >>= 1
+	}
+
+	raw, _ := result.Rat(nil)
+	if tick < 0 {
+		raw.Inv(raw)
+	}
+	return scalePrice(raw, baseIsToken0, baseDecimals, quoteDecimals)
+}
+
+// sqrtPriceX96ToPrice converts the sqrt price in Q64.96 to the price of the base token in quote token, the price of
+// token0 in token1 in their smallest units is (sqrtPriceX96 / 2^96)^2.
+func sqrtPriceX96ToPrice(sqrtPriceX96 *big.Int, baseIsToken0 bool, baseDecimals, quoteDecimals uint8) (decimal.Decimal, error) {
+	if sqrtPriceX96 == nil || sqrtPriceX96.Sign() <= 0 {
+		return decimal.Decimal{}, fmt.Errorf("invalid sqrt price")
+	}
+
+	raw := new(big.Rat).SetFrac(new(big.Int).Mul(sqrtPriceX96, sqrtPriceX96), q192)
+	return scalePrice(raw, baseIsToken0, baseDecimals, quoteDecimals)
+}
+
+// scalePrice converts the raw price of token0 in token1 in their smallest units to the price of base token in quote token.
+func scalePrice(raw *big.Rat, baseIsToken0 bool, baseDecimals, quoteDecimals uint8) (decimal.Decimal, error) {
+	if raw.Sign() <= 0 {
+		return decimal.Decimal{}, fmt.Errorf("price <= 0, skip price computing")
+	}
+
+	price := new(big.Rat).Set(raw)
+	if !baseIsToken0 {
+		price.Inv(price)
+	}
+
+	// price == (quote / 10^quoteDecimals) / (base / 10^baseDecimals) == raw * 10^baseDecimals / 10^quoteDecimals
+	scale := new(big.Rat).SetFrac(
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(baseDecimals)), nil),
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(quoteDecimals)), nil))
+	price.Mul(price, scale)
+	return decimal.NewFromString(price.FloatString(common.CryptoToUsdcDecimals))
+}
@@ -0,0 +1,124 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/crypto_uniswap/contracts/poolv3"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// revertedCaller fails every call to the pool with the error, e.g. a revert, and it counts the calls.
+type revertedCaller struct {
+	err   error
+	calls int
+}
+
+func (c *revertedCaller) CodeAt(_ context.Context, _ ecommon.Address, _ *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (c *revertedCaller) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func newRevertedPool(t *testing.T, caller *revertedCaller) *V3Pool {
+	poolCaller, err := poolv3.NewPoolV3Caller(ecommon.HexToAddress("0x01"), caller)
+	require.NoError(t, err)
+	return &V3Pool{symbol: "ATN-USDC", contract: &poolv3.PoolV3{PoolV3Caller: *poolCaller}}
+}
+
+func TestV3PoolSkippedWithoutTWAP(t *testing.T) {
+	caller := &revertedCaller{err: errors.New("execution reverted: OLD")}
+	pool := newRevertedPool(t, caller)
+
+	// the pool is not priced by slot0 once the observe call is reverted.
+	_, err := pool.price(60)
+	require.ErrorIs(t, err, errTWAPUnavailable)
+	require.Equal(t, 1, caller.calls)
+
+	// the pool stays bound for the next fetching.
+	client := &UniswapV3Client{conf: &config.PluginConfig{TWAPWindow: 60}, logger: hclog.NewNullLogger(),
+		pools: map[string]*V3Pool{"ATN-USDC": pool}}
+	_, err = client.fetch("ATN-USDC")
+	require.ErrorIs(t, err, errTWAPUnavailable)
+	require.Contains(t, client.pools, "ATN-USDC")
+
+	// the other errors, e.g. the lost connectivity, are not taken as an unavailable TWAP, the pools are re-bound.
+	caller.err = errors.New("connection refused")
+	_, err = pool.price(60)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, errTWAPUnavailable)
+	_, err = client.fetch("ATN-USDC")
+	require.Error(t, err)
+	require.Empty(t, client.pools)
+}
+
+func TestSqrtPriceX96ToPrice(t *testing.T) {
+	q96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	sqrtPriceX96 := new(big.Int).Mul(q96, big.NewInt(2)) // 4 units of token1 per unit of token0.
+
+	// token0 is the base token with 18 decimals, token1 is the quote token with 6 decimals.
+	price, err := sqrtPriceX96ToPrice(sqrtPriceX96, true, 18, 6)
+	require.NoError(t, err)
+	require.Equal(t, "4000000000000", price.String())
+
+	// token1 is the base token with 18 decimals, token0 is the quote token with 6 decimals.
+	price, err = sqrtPriceX96ToPrice(sqrtPriceX96, false, 18, 6)
+	require.NoError(t, err)
+	require.Equal(t, "250000000000", price.String())
+
+	// same decimals.
+	price, err = sqrtPriceX96ToPrice(sqrtPriceX96, false, 18, 18)
+	require.NoError(t, err)
+	require.Equal(t, "0.25", price.String())
+
+	_, err = sqrtPriceX96ToPrice(big.NewInt(0), true, 18, 6)
+	require.Error(t, err)
+}
+
+func TestTickToPrice(t *testing.T) {
+	price, err := tickToPrice(0, true, 18, 18)
+	require.NoError(t, err)
+	require.Equal(t, "1", price.String())
+
+	// 1.0001^23027 is about 10.
+	price, err = tickToPrice(23027, true, 18, 18)
+	require.NoError(t, err)
+	require.True(t, price.Sub(decimal.NewFromInt(10)).Abs().LessThan(decimal.RequireFromString("0.001")), price.String())
+
+	price, err = tickToPrice(-23027, true, 18, 18)
+	require.NoError(t, err)
+	require.True(t, price.Sub(decimal.RequireFromString("0.1")).Abs().LessThan(decimal.RequireFromString("0.0001")), price.String())
+
+	// the base token is token1, the price is inverted.
+	inverted, err := tickToPrice(23027, false, 18, 18)
+	require.NoError(t, err)
+	require.True(t, inverted.Sub(price).Abs().LessThan(decimal.RequireFromString("0.000000000001")), inverted.String())
+}
+
+func TestMeanTick(t *testing.T) {
+	tick, err := meanTick([]*big.Int{big.NewInt(100), big.NewInt(160)}, 60)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), tick)
+
+	tick, err = meanTick([]*big.Int{big.NewInt(0), big.NewInt(7)}, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), tick)
+
+	// rounds to negative infinity.
+	tick, err = meanTick([]*big.Int{big.NewInt(0), big.NewInt(-7)}, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(-4), tick)
+
+	_, err = meanTick([]*big.Int{big.NewInt(0)}, 2)
+	require.Error(t, err)
+}
@@ -0,0 +1,407 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package factoryv3
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// FactoryV3MetaData contains all meta data concerning the FactoryV3 contract.
+var FactoryV3MetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"token0\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"token1\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint24\",\"name\":\"fee\",\"type\":\"uint24\"},{\"indexed\":false,\"internalType\":\"int24\",\"name\":\"tickSpacing\",\"type\":\"int24\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"pool\",\"type\":\"address\"}],\"name\":\"PoolCreated\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint24\",\"name\":\"fee\",\"type\":\"uint24\"}],\"name\":\"feeAmountTickSpacing\",\"outputs\":[{\"internalType\":\"int24\",\"name\":\"\",\"type\":\"int24\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"tokenA\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"tokenB\",\"type\":\"address\"},{\"internalType\":\"uint24\",\"name\":\"fee\",\"type\":\"uint24\"}],\"name\":\"getPool\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"pool\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// FactoryV3ABI is the input ABI used to generate the binding from.
+// Deprecated: Use FactoryV3MetaData.ABI instead.
+var FactoryV3ABI = FactoryV3MetaData.ABI
+
+// FactoryV3 is an auto generated Go binding around an Ethereum contract.
+type FactoryV3 struct {
+	FactoryV3Caller     // Read-only binding to the contract
+	FactoryV3Transactor // Write-only binding to the contract
+	FactoryV3Filterer   // Log filterer for contract events
+}
+
+// FactoryV3Caller is an auto generated read-only Go binding around an Ethereum contract.
+type FactoryV3Caller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// FactoryV3Transactor is an auto generated write-only Go binding around an Ethereum contract.
+type FactoryV3Transactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// FactoryV3Filterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type FactoryV3Filterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// FactoryV3Session is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type FactoryV3Session struct {
+	Contract     *FactoryV3        // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// FactoryV3CallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type FactoryV3CallerSession struct {
+	Contract *FactoryV3Caller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts    // Call options to use throughout this session
+}
+
+// FactoryV3TransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type FactoryV3TransactorSession struct {
+	Contract     *FactoryV3Transactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts    // Transaction auth options to use throughout this session
+}
+
+// FactoryV3Raw is an auto generated low-level Go binding around an Ethereum contract.
+type FactoryV3Raw struct {
+	Contract *FactoryV3 // Generic contract binding to access the raw methods on
+}
+
+// FactoryV3CallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type FactoryV3CallerRaw struct {
+	Contract *FactoryV3Caller // Generic read-only contract binding to access the raw methods on
+}
+
+// FactoryV3TransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type FactoryV3TransactorRaw struct {
+	Contract *FactoryV3Transactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewFactoryV3 creates a new instance of FactoryV3, bound to a specific deployed contract.
+func NewFactoryV3(address common.Address, backend bind.ContractBackend) (*FactoryV3, error) {
+	contract, err := bindFactoryV3(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &FactoryV3{FactoryV3Caller: FactoryV3Caller{contract: contract}, FactoryV3Transactor: FactoryV3Transactor{contract: contract}, FactoryV3Filterer: FactoryV3Filterer{contract: contract}}, nil
+}
+
+// NewFactoryV3Caller creates a new read-only instance of FactoryV3, bound to a specific deployed contract.
+func NewFactoryV3Caller(address common.Address, caller bind.ContractCaller) (*FactoryV3Caller, error) {
+	contract, err := bindFactoryV3(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &FactoryV3Caller{contract: contract}, nil
+}
+
+// NewFactoryV3Transactor creates a new write-only instance of FactoryV3, bound to a specific deployed contract.
+func NewFactoryV3Transactor(address common.Address, transactor bind.ContractTransactor) (*FactoryV3Transactor, error) {
+	contract, err := bindFactoryV3(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &FactoryV3Transactor{contract: contract}, nil
+}
+
+// NewFactoryV3Filterer creates a new log filterer instance of FactoryV3, bound to a specific deployed contract.
+func NewFactoryV3Filterer(address common.Address, filterer bind.ContractFilterer) (*FactoryV3Filterer, error) {
+	contract, err := bindFactoryV3(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &FactoryV3Filterer{contract: contract}, nil
+}
+
+// bindFactoryV3 binds a generic wrapper to an already deployed contract.
+func bindFactoryV3(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := FactoryV3MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_FactoryV3 *FactoryV3Raw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _FactoryV3.Contract.FactoryV3Caller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_FactoryV3 *FactoryV3Raw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _FactoryV3.Contract.FactoryV3Transactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_FactoryV3 *FactoryV3Raw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _FactoryV3.Contract.FactoryV3Transactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_FactoryV3 *FactoryV3CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _FactoryV3.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_FactoryV3 *FactoryV3TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _FactoryV3.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_FactoryV3 *FactoryV3TransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _FactoryV3.Contract.contract.Transact(opts, method, params...)
+}
+
+// FeeAmountTickSpacing is a free data retrieval call binding the contract method 0x22afcccb.
+//
+// Solidity: function feeAmountTickSpacing(uint24 fee) view returns(int24)
+func (_FactoryV3 *FactoryV3Caller) FeeAmountTickSpacing(opts *bind.CallOpts, fee *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _FactoryV3.contract.Call(opts, &out, "feeAmountTickSpacing", fee)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// FeeAmountTickSpacing is a free data retrieval call binding the contract method 0x22afcccb.
+//
+// Solidity: function feeAmountTickSpacing(uint24 fee) view returns(int24)
+func (_FactoryV3 *FactoryV3Session) FeeAmountTickSpacing(fee *big.Int) (*big.Int, error) {
+	return _FactoryV3.Contract.FeeAmountTickSpacing(&_FactoryV3.CallOpts, fee)
+}
+
+// FeeAmountTickSpacing is a free data retrieval call binding the contract method 0x22afcccb.
+//
+// Solidity: function feeAmountTickSpacing(uint24 fee) view returns(int24)
+func (_FactoryV3 *FactoryV3CallerSession) FeeAmountTickSpacing(fee *big.Int) (*big.Int, error) {
+	return _FactoryV3.Contract.FeeAmountTickSpacing(&_FactoryV3.CallOpts, fee)
+}
+
+// GetPool is a free data retrieval call binding the contract method 0x1698ee82.
+//
+// Solidity: function getPool(address tokenA, address tokenB, uint24 fee) view returns(address pool)
+func (_FactoryV3 *FactoryV3Caller) GetPool(opts *bind.CallOpts, tokenA common.Address, tokenB common.Address, fee *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _FactoryV3.contract.Call(opts, &out, "getPool", tokenA, tokenB, fee)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetPool is a free data retrieval call binding the contract method 0x1698ee82.
+//
+// Solidity: function getPool(address tokenA, address tokenB, uint24 fee) view returns(address pool)
+func (_FactoryV3 *FactoryV3Session) GetPool(tokenA common.Address, tokenB common.Address, fee *big.Int) (common.Address, error) {
+	return _FactoryV3.Contract.GetPool(&_FactoryV3.CallOpts, tokenA, tokenB, fee)
+}
+
+// GetPool is a free data retrieval call binding the contract method 0x1698ee82.
+//
+// Solidity: function getPool(address tokenA, address tokenB, uint24 fee) view returns(address pool)
+func (_FactoryV3 *FactoryV3CallerSession) GetPool(tokenA common.Address, tokenB common.Address, fee *big.Int) (common.Address, error) {
+	return _FactoryV3.Contract.GetPool(&_FactoryV3.CallOpts, tokenA, tokenB, fee)
+}
+
+// FactoryV3PoolCreatedIterator is returned from FilterPoolCreated and is used to iterate over the raw logs and unpacked data for PoolCreated events raised by the FactoryV3 contract.
+type FactoryV3PoolCreatedIterator struct {
+	Event *FactoryV3PoolCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *FactoryV3PoolCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(FactoryV3PoolCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(FactoryV3PoolCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *FactoryV3PoolCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *FactoryV3PoolCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// FactoryV3PoolCreated represents a PoolCreated event raised by the FactoryV3 contract.
+type FactoryV3PoolCreated struct {
+	Token0      common.Address
+	Token1      common.Address
+	Fee         *big.Int
+	TickSpacing *big.Int
+	Pool        common.Address
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterPoolCreated is a free log retrieval operation binding the contract event 0x783cca1c0412dd0d695e784568c96da2e9c22ff989357a2e8b1d9b2b4e6b7118.
+//
+// Solidity: event PoolCreated(address indexed token0, address indexed token1, uint24 indexed fee, int24 tickSpacing, address pool)
+func (_FactoryV3 *FactoryV3Filterer) FilterPoolCreated(opts *bind.FilterOpts, token0 []common.Address, token1 []common.Address, fee []*big.Int) (*FactoryV3PoolCreatedIterator, error) {
+
+	var token0Rule []interface{}
+	for _, token0Item := range token0 {
+		token0Rule = append(token0Rule, token0Item)
+	}
+	var token1Rule []interface{}
+	for _, token1Item := range token1 {
+		token1Rule = append(token1Rule, token1Item)
+	}
+	var feeRule []interface{}
+	for _, feeItem := range fee {
+		feeRule = append(feeRule, feeItem)
+	}
+
+	logs, sub, err := _FactoryV3.contract.FilterLogs(opts, "PoolCreated", token0Rule, token1Rule, feeRule)
+	if err != nil {
+		return nil, err
+	}
+	return &FactoryV3PoolCreatedIterator{contract: _FactoryV3.contract, event: "PoolCreated", logs: logs, sub: sub}, nil
+}
+
+// WatchPoolCreated is a free log subscription operation binding the contract event 0x783cca1c0412dd0d695e784568c96da2e9c22ff989357a2e8b1d9b2b4e6b7118.
+//
+// Solidity: event PoolCreated(address indexed token0, address indexed token1, uint24 indexed fee, int24 tickSpacing, address pool)
+func (_FactoryV3 *FactoryV3Filterer) WatchPoolCreated(opts *bind.WatchOpts, sink chan<- *FactoryV3PoolCreated, token0 []common.Address, token1 []common.Address, fee []*big.Int) (event.Subscription, error) {
+
+	var token0Rule []interface{}
+	for _, token0Item := range token0 {
+		token0Rule = append(token0Rule, token0Item)
+	}
+	var token1Rule []interface{}
+	for _, token1Item := range token1 {
+		token1Rule = append(token1Rule, token1Item)
+	}
+	var feeRule []interface{}
+	for _, feeItem := range fee {
+		feeRule = append(feeRule, feeItem)
+	}
+
+	logs, sub, err := _FactoryV3.contract.WatchLogs(opts, "PoolCreated", token0Rule, token1Rule, feeRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(FactoryV3PoolCreated)
+				if err := _FactoryV3.contract.UnpackLog(event, "PoolCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePoolCreated is a log parse operation binding the contract event 0x783cca1c0412dd0d695e784568c96da2e9c22ff989357a2e8b1d9b2b4e6b7118.
+//
+// Solidity: event PoolCreated(address indexed token0, address indexed token1, uint24 indexed fee, int24 tickSpacing, address pool)
+func (_FactoryV3 *FactoryV3Filterer) ParsePoolCreated(log types.Log) (*FactoryV3PoolCreated, error) {
+	event := new(FactoryV3PoolCreated)
+	if err := _FactoryV3.contract.UnpackLog(event, "PoolCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
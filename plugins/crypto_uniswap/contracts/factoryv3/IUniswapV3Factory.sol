@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: GPL-2.0-or-later
+pragma solidity >=0.5.0;
+
+interface IUniswapV3Factory {
+    event PoolCreated(address indexed token0, address indexed token1, uint24 indexed fee, int24 tickSpacing, address pool);
+
+    function feeAmountTickSpacing(uint24 fee) external view returns (int24);
+
+    function getPool(address tokenA, address tokenB, uint24 fee) external view returns (address pool);
+}
@@ -8,22 +8,30 @@ import (
 	"os"
 )
 
+var (
+	ntnTokenAddress  = types.AutonityContractAddress.Hex()          // Same as 0xBd770416a3345F91E4B34576cb804a576fa48EB1, Autonity contract address.
+	atnTokenAddress  = "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2" // Wrapped ATN ERC20 contract address on the target blockchain.
+	usdcTokenAddress = "0xB855D5e83363A4494e09f0Bb3152A70d3f161940" // Bridged USDC ERC20 contract address on the target blockchain.
+)
+
 // configs for the ATN-USDC marketplace in Autonity main network.
 var defaultConfig = config.PluginConfig{
 	Name:               "crypto_uniswap",
-	Scheme:             "wss",                                        // both ws and wss works for this plugin.
-	Endpoint:           "replace with your host:port/path",           // default websocket endpoint for autonity main network.
-	Timeout:            10,                                           // 10s.
-	DataUpdateInterval: common.DefaultAMMDataUpdateInterval,          // 1s, shorten the default data point refresh interval for AMM market data, as they can move very fast.
-	NTNTokenAddress:    types.AutonityContractAddress.Hex(),          // Same as 0xBd770416a3345F91E4B34576cb804a576fa48EB1, Autonity contract address.
-	ATNTokenAddress:    "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2", // Wrapped ATN ERC20 contract address on the target blockchain.
-	USDCTokenAddress:   "0xB855D5e83363A4494e09f0Bb3152A70d3f161940", // Bridged USDC ERC20 contract address on the target blockchain.
-	SwapAddress:        "0x218F76e357594C82Cc29A88B90dd67b180827c88", // UniSwap factory contract address on the target blockchain.
+	Scheme:             "wss",                                                                   // both ws and wss works for this plugin.
+	Endpoint:           "replace with your host:port/path",                                      // default websocket endpoint for autonity main network.
+	Timeout:            10,                                                                      // 10s.
+	DataUpdateInterval: common.DefaultAMMDataUpdateInterval,                                     // 1s, shorten the default data point refresh interval for AMM market data, as they can move very fast.
+	Pairs:              client.DefaultPairs(ntnTokenAddress, atnTokenAddress, usdcTokenAddress), // ATN-USDC and NTN-USDC, NTN-ATN is derived from them.
+	SwapAddress:        "0x218F76e357594C82Cc29A88B90dd67b180827c88",                            // UniSwap factory contract address on the target blockchain.
+	BackfillBlocks:     common.DefaultAMMBackfillBlocks,                                         // Backfill the order book with the swaps of recent 300 blocks on start and reconnects.
+	PoolVersion:        common.UniswapV2,                                                        // UniSwap V2 pairs, set it to 3 with a V3 factory address to price from V3 pools.
+	FeeTier:            common.DefaultV3FeeTier,                                                 // 0.3%, the fee tier of the V3 pools.
+	TWAPWindow:         common.DefaultV3TWAPWindow,                                              // 60s, the TWAP window read from the V3 pools' observations.
 }
 
 func main() {
 	conf := common.ResolveConf(os.Args[0], &defaultConfig)
-	c, err := client.NewUniswapClient(conf)
+	c, err := client.NewClient(conf)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	_ "time/tzdata" // the publication time is resolved in the BoC's time zone regardless of the host's tz database.
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	version      = "v0.2.9"
+	path         = "valet/observations/%s/json"
+	recentParam  = "recent"
+	seriesPrefix = "FX"
+	seriesSuffix = "CAD"
+	dateLayout   = "2006-01-02"
+	bocZone      = "America/Toronto"
+	publishAt    = 16*time.Hour + 30*time.Minute // the daily exchange rates are published by 16:30 ET on every business day.
+)
+
+var defaultConfig = config.PluginConfig{
+	Name:               "forex_boc",
+	Key:                "",
+	Scheme:             "https",
+	Endpoint:           "www.bankofcanada.ca",
+	Timeout:            10,   // 10s
+	DataUpdateInterval: 3600, // 1h, the exchange rates are published once a day.
+}
+
+// currencies are the currencies quoted by the BoC in the FX<currency>CAD series for the default forex symbols.
+var currencies = []string{"USD", "EUR", "JPY", "GBP", "AUD", "SEK"}
+
+type Value struct {
+	V string `json:"v"`
+}
+
+// Response is the observations of the Valet API, each observation holds the date and the values per series, e.g.
+// {"d": "2024-10-18", "FXUSDCAD": {"v": "1.3803"}}.
+type Response struct {
+	Observations []map[string]json.RawMessage `json:"observations"`
+	Message      string                       `json:"message"`
+}
+
+type BoCClient struct {
+	conf     *config.PluginConfig
+	client   *common.Client
+	logger   hclog.Logger
+	location *time.Location
+}
+
+func NewBoCClient(conf *config.PluginConfig) *BoCClient {
+	client := common.NewClientFromConf(conf)
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   conf.Name,
+		Level:  hclog.Info,
+		Output: os.Stdout,
+	})
+
+	location, err := time.LoadLocation(bocZone)
+	if err != nil {
+		logger.Warn("cannot load BoC time zone, take EST", "error", err.Error())
+		location = time.FixedZone("EST", -5*3600)
+	}
+
+	return &BoCClient{conf: conf, client: client, logger: logger, location: location}
+}
+
+func (b *BoCClient) KeyRequired() bool {
+	return false
+}
+
+func (b *BoCClient) FetchPrice(symbols []string) (common.Prices, error) {
+	res, err := b.client.Conn.Request(b.conf.Scheme, b.buildURL())
+	if err != nil {
+		b.logger.Error("https request", "error", err.Error())
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		b.logger.Error("io read", "error", err.Error())
+		return nil, err
+	}
+
+	var result Response
+	if err = common.CheckHTTPStatusCode(res.StatusCode); err != nil {
+		if json.Unmarshal(body, &result) == nil && result.Message != "" {
+			b.logger.Error("data source return error", "error", err.Error(), "msg", result.Message)
+		} else {
+			b.logger.Error("data source return error", "error", err.Error())
+		}
+		return nil, err
+	}
+
+	if err = json.Unmarshal(body, &result); err != nil {
+		b.logger.Error("unmarshal observations", "error", err.Error())
+		return nil, err
+	}
+
+	ts, rates, err := b.parse(&result)
+	if err != nil {
+		b.logger.Error("parse observations", "error", err.Error())
+		return nil, err
+	}
+
+	var prices common.Prices
+	for _, s := range symbols {
+		p, err := symbolToPrice(s, rates, ts)
+		if err != nil {
+			b.logger.Error("symbol to price", "error", err.Error())
+			continue
+		}
+		prices = append(prices, p)
+	}
+	return prices, nil
+}
+
+// parse returns the publication TS and the rates in CAD per currency of the latest observation, the CAD is added with
+// rate 1.
+func (b *BoCClient) parse(res *Response) (int64, map[string]decimal.Decimal, error) {
+	if len(res.Observations) == 0 {
+		return 0, nil, common.ErrDataNotAvailable
+	}
+	observation := res.Observations[len(res.Observations)-1]
+
+	var d string
+	if err := json.Unmarshal(observation["d"], &d); err != nil {
+		return 0, nil, fmt.Errorf("invalid observation date: %w", err)
+	}
+	date, err := time.ParseInLocation(dateLayout, d, b.location)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid observation date %q: %w", d, err)
+	}
+
+	rates := map[string]decimal.Decimal{seriesSuffix: decimal.NewFromInt(1)}
+	for _, c := range currencies {
+		raw, ok := observation[seriesPrefix+c+seriesSuffix]
+		if !ok {
+			continue
+		}
+
+		var v Value
+		if err = json.Unmarshal(raw, &v); err != nil {
+			return 0, nil, fmt.Errorf("invalid rate of %s: %w", c, err)
+		}
+		rate, err := decimal.NewFromString(v.V)
+		if err != nil || !rate.IsPositive() {
+			return 0, nil, fmt.Errorf("invalid rate %q of %s", v.V, c)
+		}
+		rates[c] = rate
+	}
+	return date.Add(publishAt).Unix(), rates, nil
+}
+
+// symbolToPrice converts the rates in CAD per currency to the price of the symbol, e.g. EUR-USD == EURCAD / USDCAD.
+func symbolToPrice(s string, rates map[string]decimal.Decimal, ts int64) (common.Price, error) {
+	var price common.Price
+	codes := strings.Split(s, common.ResolveSeparator(s))
+	if len(codes) != 2 {
+		return price, fmt.Errorf("invalid symbol %s", s)
+	}
+
+	from, ok := rates[codes[0]]
+	if !ok {
+		return price, fmt.Errorf("unknown symbol %s", s)
+	}
+	to, ok := rates[codes[1]]
+	if !ok {
+		return price, fmt.Errorf("unknown symbol %s", s)
+	}
+
+	price.Symbol = s
+	price.Price = from.Div(to).String()
+	price.Volume = types.DefaultVolume.String()
+	price.Timestamp = ts
+	return price, nil
+}
+
+// AvailableSymbols returns the adapted symbols for current data source.
+func (b *BoCClient) AvailableSymbols() ([]string, error) {
+	return common.DefaultForexSymbols, nil
+}
+
+func (b *BoCClient) Close() {
+	b.client.Conn.Close()
+}
+
+func (b *BoCClient) buildURL() *url.URL {
+	series := make([]string, 0, len(currencies))
+	for _, c := range currencies {
+		series = append(series, seriesPrefix+c+seriesSuffix)
+	}
+
+	endpoint := &url.URL{}
+	endpoint.Path = fmt.Sprintf(path, strings.Join(series, ","))
+	query := endpoint.Query()
+	query.Set(recentParam, "1")
+	endpoint.RawQuery = query.Encode()
+	return endpoint
+}
+
+func main() {
+	conf := common.ResolveConf(os.Args[0], &defaultConfig)
+	adapter := common.NewPlugin(conf, NewBoCClient(conf), version, types.SrcAnchor, nil)
+	defer adapter.Close()
+	common.PluginServe(adapter)
+}
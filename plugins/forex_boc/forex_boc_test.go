@@ -0,0 +1,73 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoCClientFetchPrice(t *testing.T) {
+	observations, err := os.ReadFile("testdata/observations.json")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/valet/observations/FXUSDCAD,FXEURCAD,FXJPYCAD,FXGBPCAD,FXAUDCAD,FXSEKCAD/json" ||
+			r.URL.Query().Get(recentParam) != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(observations) // nolint
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := NewBoCClient(&conf)
+	defer client.Close()
+	prices, err := client.FetchPrice([]string{"EUR-USD", "JPY-USD", "GBP-USD", "AUD-USD", "CAD-USD", "SEK-USD", "XAU-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 6, len(prices))
+
+	expected := map[string]string{
+		"EUR-USD": "1.086231884057971",
+		"JPY-USD": "0.0066855072463768",
+		"GBP-USD": "1.3050724637681159",
+		"AUD-USD": "0.6702173913043478",
+		"CAD-USD": "0.7246376811594203",
+		"SEK-USD": "0.0952898550724638",
+	}
+	for _, p := range prices {
+		require.Equal(t, expected[p.Symbol], p.Price, p.Symbol)
+		// published at 16:30 EDT of the observation date.
+		require.Equal(t, int64(1729283400), p.Timestamp)
+	}
+}
+
+func TestBoCClientError(t *testing.T) {
+	body, err := os.ReadFile("testdata/not_found.json")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(body) // nolint
+	}))
+	defer server.Close()
+
+	conf := config.PluginConfig{
+		Name:     defaultConfig.Name,
+		Scheme:   "http",
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Timeout:  defaultConfig.Timeout,
+	}
+	client := NewBoCClient(&conf)
+	defer client.Close()
+	_, err = client.FetchPrice([]string{"EUR-USD"})
+	require.Error(t, err)
+}
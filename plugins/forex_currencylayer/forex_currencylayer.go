@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/shopspring/decimal"
@@ -56,7 +55,7 @@ type CLClient struct {
 }
 
 func NewCLClient(conf *config.PluginConfig) *CLClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewClientFromConf(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Info,
@@ -141,6 +140,7 @@ func (cl *CLClient) symbolsToPrice(s string, res *CLResult) (common.Price, error
 
 	price.Symbol = s
 	price.Volume = types.DefaultVolume.String()
+	price.Timestamp = res.Timestamp
 	switch from {
 	case "EUR":
 		price.Price = decimal.NewFromInt(1).Div(res.Quotes.USDEUR).String()
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	_ "time/tzdata" // the publication time is resolved in the ECB's time zone regardless of the host's tz database.
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	version    = "v0.2.9"
+	path       = "stats/eurofxref/eurofxref-daily.xml"
+	dateLayout = "2006-01-02"
+	ecbZone    = "Europe/Berlin"
+	publishAt  = 16 * time.Hour // the reference rates are published at around 16:00 CET on every TARGET working day.
+)
+
+var defaultConfig = config.PluginConfig{
+	Name:               "forex_ecb",
+	Key:                "",
+	Scheme:             "https",
+	Endpoint:           "www.ecb.europa.eu",
+	Timeout:            10,   // 10s
+	DataUpdateInterval: 3600, // 1h, the reference rates are published once a day.
+}
+
+type Rate struct {
+	Currency string `xml:"currency,attr"`
+	Rate     string `xml:"rate,attr"`
+}
+
+type DailyCube struct {
+	Time  string `xml:"time,attr"`
+	Rates []Rate `xml:"Cube"`
+}
+
+// Envelope is the daily reference rates of the ECB, the rates are quoted in the units of currency per EUR.
+type Envelope struct {
+	XMLName xml.Name  `xml:"Envelope"`
+	Cube    DailyCube `xml:"Cube>Cube"`
+}
+
+type ECBClient struct {
+	conf     *config.PluginConfig
+	client   *common.Client
+	logger   hclog.Logger
+	location *time.Location
+}
+
+func NewECBClient(conf *config.PluginConfig) *ECBClient {
+	client := common.NewClientFromConf(conf)
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   conf.Name,
+		Level:  hclog.Info,
+		Output: os.Stdout,
+	})
+
+	location, err := time.LoadLocation(ecbZone)
+	if err != nil {
+		logger.Warn("cannot load ECB time zone, take CET", "error", err.Error())
+		location = time.FixedZone("CET", 3600)
+	}
+
+	return &ECBClient{conf: conf, client: client, logger: logger, location: location}
+}
+
+func (e *ECBClient) KeyRequired() bool {
+	return false
+}
+
+func (e *ECBClient) FetchPrice(symbols []string) (common.Prices, error) {
+	res, err := e.client.Conn.Request(e.conf.Scheme, e.buildURL())
+	if err != nil {
+		e.logger.Error("https request", "error", err.Error())
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if err = common.CheckHTTPStatusCode(res.StatusCode); err != nil {
+		e.logger.Error("data source return error", "error", err.Error())
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		e.logger.Error("io read", "error", err.Error())
+		return nil, err
+	}
+
+	var result Envelope
+	if err = xml.Unmarshal(body, &result); err != nil {
+		e.logger.Error("unmarshal reference rates", "error", err.Error())
+		return nil, err
+	}
+
+	ts, rates, err := e.parse(&result)
+	if err != nil {
+		e.logger.Error("parse reference rates", "error", err.Error())
+		return nil, err
+	}
+
+	var prices common.Prices
+	for _, s := range symbols {
+		p, err := symbolToPrice(s, rates, ts)
+		if err != nil {
+			e.logger.Error("symbol to price", "error", err.Error())
+			continue
+		}
+		prices = append(prices, p)
+	}
+	return prices, nil
+}
+
+// parse returns the publication TS and the rates of the daily reference rates, the EUR is added with rate 1.
+func (e *ECBClient) parse(res *Envelope) (int64, map[string]decimal.Decimal, error) {
+	date, err := time.ParseInLocation(dateLayout, res.Cube.Time, e.location)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid reference date %q: %w", res.Cube.Time, err)
+	}
+
+	rates := map[string]decimal.Decimal{"EUR": decimal.NewFromInt(1)}
+	for _, r := range res.Cube.Rates {
+		rate, err := decimal.NewFromString(r.Rate)
+		if err != nil || !rate.IsPositive() {
+			return 0, nil, fmt.Errorf("invalid rate %q of %s", r.Rate, r.Currency)
+		}
+		rates[r.Currency] = rate
+	}
+	return date.Add(publishAt).Unix(), rates, nil
+}
+
+// symbolToPrice converts the rates per EUR to the price of the symbol, e.g. JPY-USD == USD per EUR / JPY per EUR.
+func symbolToPrice(s string, rates map[string]decimal.Decimal, ts int64) (common.Price, error) {
+	var price common.Price
+	codes := strings.Split(s, common.ResolveSeparator(s))
+	if len(codes) != 2 {
+		return price, fmt.Errorf("invalid symbol %s", s)
+	}
+
+	from, ok := rates[codes[0]]
+	if !ok {
+		return price, fmt.Errorf("unknown symbol %s", s)
+	}
+	to, ok := rates[codes[1]]
+	if !ok {
+		return price, fmt.Errorf("unknown symbol %s", s)
+	}
+
+	price.Symbol = s
+	price.Price = to.Div(from).String()
+	price.Volume = types.DefaultVolume.String()
+	price.Timestamp = ts
+	return price, nil
+}
+
+// AvailableSymbols returns the adapted symbols for current data source.
+func (e *ECBClient) AvailableSymbols() ([]string, error) {
+	return common.DefaultForexSymbols, nil
+}
+
+func (e *ECBClient) Close() {
+	e.client.Conn.Close()
+}
+
+func (e *ECBClient) buildURL() *url.URL {
+	endpoint := &url.URL{}
+	endpoint.Path = path
+	return endpoint
+}
+
+func main() {
+	conf := common.ResolveConf(os.Args[0], &defaultConfig)
+	adapter := common.NewPlugin(conf, NewECBClient(conf), version, types.SrcAnchor, nil)
+	defer adapter.Close()
+	common.PluginServe(adapter)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"autonity-oracle/plugins/common"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestECBClientFetchPrice(t *testing.T) {
+	body, err := os.ReadFile("testdata/eurofxref-daily.xml")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(body) // nolint
+	}))
+	defer server.Close()
+
+	conf := defaultConfig
+	conf.Scheme = "http"
+	conf.Endpoint = strings.TrimPrefix(server.URL, "http://")
+	client := NewECBClient(&conf)
+	defer client.Close()
+
+	prices, err := client.FetchPrice([]string{"EUR-USD", "JPY-USD", "GBP-USD", "AUD-USD", "CAD-USD", "SEK-USD", "XAU-USD"})
+	require.NoError(t, err)
+	require.Equal(t, len(common.DefaultForexSymbols), len(prices))
+
+	expected := map[string]string{
+		"EUR-USD": "1.0866",
+		"JPY-USD": "0.0066863577625992",
+		"GBP-USD": "1.3058840496106144",
+		"AUD-USD": "0.6695834360364802",
+		"CAD-USD": "0.7250283579101888",
+		"SEK-USD": "0.0953032495724247",
+	}
+	for _, p := range prices {
+		require.Equal(t, expected[p.Symbol], p.Price, p.Symbol)
+		// published at 16:00 CEST of the reference date.
+		require.Equal(t, int64(1729260000), p.Timestamp)
+	}
+}
+
+func TestECBClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	conf := defaultConfig
+	conf.Scheme = "http"
+	conf.Endpoint = strings.TrimPrefix(server.URL, "http://")
+	client := NewECBClient(&conf)
+	defer client.Close()
+	_, err := client.FetchPrice(common.DefaultForexSymbols)
+	require.Error(t, err)
+}
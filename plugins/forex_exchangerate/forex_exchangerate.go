@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/shopspring/decimal"
@@ -58,7 +57,7 @@ type EXClient struct {
 }
 
 func NewEXClient(conf *config.PluginConfig) *EXClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewClientFromConf(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   "ExchangeClient",
 		Level:  hclog.Info,
@@ -142,6 +141,7 @@ func (ex *EXClient) symbolsToPrice(s string, res *EXResult) (common.Price, error
 
 	price.Symbol = s
 	price.Volume = types.DefaultVolume.String()
+	price.Timestamp = res.TimeLastUpdateUnix
 	switch from {
 	case "EUR":
 		price.Price = decimal.NewFromInt(1).Div(res.Rates.EUR).String()
@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/shopspring/decimal"
@@ -37,7 +36,7 @@ type ForexRateAPIClient struct {
 }
 
 func NewForexRateAPIClient(conf *config.PluginConfig) *ForexRateAPIClient {
-	client := common.NewClient("", time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewClientFromConf(conf)
 	if client == nil {
 		panic("cannot create common client")
 	}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	simConfig "autonity-oracle/data_source_simulator/binance_simulator/config"
+	"autonity-oracle/data_source_simulator/binance_simulator/generator_manager"
+	"autonity-oracle/data_source_simulator/binance_simulator/httpsrv"
 	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -14,3 +19,27 @@ func TestNewOXClient(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 6, len(prices))
 }
+
+func TestOXClientWithSimulator(t *testing.T) {
+	gm := generator_manager.NewScenarioGeneratorManager(&simConfig.Scenario{Symbols: map[string]*simConfig.SymbolScenario{
+		"EUR-USD": {Start: 1.25}, "JPY-USD": {Start: 0.008}, "GBP-USD": {Start: 1.25}, "AUD-USD": {Start: 0.625},
+		"CAD-USD": {Start: 0.8}, "SEK-USD": {Start: 0.1},
+	}}, 0)
+	srv := httptest.NewServer(httpsrv.NewHttpServer(gm, 0, 0, 0).Handler)
+	defer srv.Close()
+
+	conf := defaultConfig
+	conf.Key = "test"
+	conf.Scheme = "http"
+	conf.Endpoint = strings.TrimPrefix(srv.URL, "http://")
+	client := NewOXClient(&conf)
+	defer client.Close()
+	prices, err := client.FetchPrice([]string{"EUR-USD", "JPY-USD", "GBP-USD", "AUD-USD", "CAD-USD", "SEK-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 6, len(prices))
+	for _, p := range prices {
+		if p.Symbol == "JPY-USD" {
+			require.Equal(t, "0.008", p.Price)
+		}
+	}
+}
@@ -18,7 +18,8 @@ import (
 )
 
 const (
-	version = "v0.2.7"
+	version        = "v0.2.7"
+	wiseTimeLayout = "2006-01-02T15:04:05-0700" // the layout of the rate's time given by wise, e.g. 2019-03-05T11:08:10+0000.
 )
 
 var defaultConfig = config.PluginConfig{
@@ -60,7 +61,7 @@ func (wc *WiseClient) buildURL(source, target string) *url.URL {
 }
 
 func NewWiseClient(conf *config.PluginConfig) *WiseClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewClientFromConf(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Info,
@@ -157,6 +158,9 @@ func (wc *WiseClient) symbolsToPrice(s string, res *WRResult) (common.Price, err
 
 	price.Symbol = s
 	price.Volume = types.DefaultVolume.String()
+	if quoteTime, err := time.Parse(wiseTimeLayout, res.Time); err == nil {
+		price.Timestamp = quoteTime.Unix()
+	}
 	switch from {
 	case "EUR":
 		price.Price = decimal.NewFromInt(1).Div(res.Rate).String()
@@ -190,7 +190,7 @@ type OutlierClient struct {
 }
 
 func NewOutlierClient(conf *config.PluginConfig) *OutlierClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewClientFromConf(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Debug,
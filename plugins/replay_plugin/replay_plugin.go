@@ -0,0 +1,158 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	samplerecorder "autonity-oracle/sample_recorder"
+	"autonity-oracle/types"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+)
+
+const (
+	version = "v0.2.7"
+)
+
+var defaultConfig = config.PluginConfig{
+	Name:               "replay_plugin",
+	Endpoint:           samplerecorder.DirName, // the directory of the recorded samples.
+	Timeout:            10,                     //10s
+	DataUpdateInterval: 30,                     //30s
+}
+
+// ReplayPlugin serves the price reports recorded by the sample recorder of the oracle server. It replays the records of
+// the plugin that it is named after, thus to replay a plugin, the binary of the replay plugin is placed in the plugin
+// directory with the name of the recorded plugin, and the endpoint of its config is set to the recorded samples' dir.
+// The records are replayed one by one on each fetching with the recorded latency and errors, while the timestamps of
+// the prices are shifted by the time passed since the recorded sampling, thus the prices are fresh for the server.
+type ReplayPlugin struct {
+	lock             sync.Mutex
+	logger           hclog.Logger
+	conf             *config.PluginConfig
+	name             string // the name of the recorded plugin.
+	records          []samplerecorder.Record
+	next             int
+	version          string
+	srcType          types.DataSourceType
+	availableSymbols []string
+}
+
+func NewReplayPlugin(conf *config.PluginConfig, name string, records []samplerecorder.Record) *ReplayPlugin {
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:       conf.Name,
+		Level:      hclog.Info,
+		Output:     os.Stderr, // logging into stderr thus the go-plugin can redirect the logs to plugin server.
+		JSONFormat: true,
+	})
+
+	r := &ReplayPlugin{
+		logger:  logger,
+		conf:    conf,
+		name:    name,
+		version: version,
+		srcType: types.SrcCEX,
+	}
+
+	symbols := make(map[string]struct{})
+	for _, rec := range records {
+		if rec.Plugin != name {
+			continue
+		}
+		if len(r.records) == 0 {
+			r.version = rec.Version
+			r.srcType = rec.Source
+		}
+		for _, p := range rec.Report.Prices {
+			symbols[p.Symbol] = struct{}{}
+		}
+		r.records = append(r.records, rec)
+	}
+
+	for s := range symbols {
+		r.availableSymbols = append(r.availableSymbols, s)
+	}
+	sort.Strings(r.availableSymbols)
+	return r
+}
+
+// FetchPrices replays the next record, the prices of the symbols not asked are filtered out.
+func (r *ReplayPlugin) FetchPrices(symbols []string) (types.PluginPriceReport, error) {
+	var report types.PluginPriceReport
+
+	r.lock.Lock()
+	if r.next >= len(r.records) {
+		r.lock.Unlock()
+		return report, common.ErrDataNotAvailable
+	}
+	rec := r.records[r.next]
+	r.next++
+	r.lock.Unlock()
+
+	time.Sleep(time.Duration(rec.Latency) * time.Millisecond)
+	if len(rec.Error) != 0 {
+		return rec.Report, errors.New(rec.Error)
+	}
+
+	asked := make(map[string]struct{})
+	for _, s := range symbols {
+		asked[s] = struct{}{}
+	}
+
+	shift := time.Now().Unix() - rec.TS
+	reported := make(map[string]struct{})
+	for _, p := range rec.Report.Prices {
+		if _, ok := asked[p.Symbol]; !ok {
+			continue
+		}
+		p.Timestamp += shift
+		report.Prices = append(report.Prices, p)
+		reported[p.Symbol] = struct{}{}
+	}
+
+	for _, s := range symbols {
+		if _, ok := reported[s]; !ok {
+			report.UnRecognizableSymbols = append(report.UnRecognizableSymbols, s)
+		}
+	}
+	return report, nil
+}
+
+func (r *ReplayPlugin) State(_ int64) (types.PluginStatement, error) {
+	var state types.PluginStatement
+	if len(r.records) == 0 {
+		return state, fmt.Errorf("no records of plugin %s in %s", r.name, r.conf.Endpoint)
+	}
+
+	state.Version = r.version
+	state.AvailableSymbols = r.availableSymbols
+	state.DataSource = "replay://" + r.conf.Endpoint
+	state.DataSourceType = r.srcType
+	return state, nil
+}
+
+func main() {
+	conf := common.ResolveConf(os.Args[0], &defaultConfig)
+	records, err := samplerecorder.ReadRecords(conf.Endpoint)
+	if err != nil {
+		println("cannot read records: ", err.Error(), conf.Endpoint)
+		os.Exit(-1)
+	}
+	adapter := NewReplayPlugin(conf, filepath.Base(os.Args[0]), records)
+
+	var pluginMap = map[string]plugin.Plugin{
+		"adapter": &types.AdapterPlugin{Impl: adapter},
+	}
+
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: types.HandshakeConfig,
+		Plugins:         pluginMap,
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	samplerecorder "autonity-oracle/sample_recorder"
+	"autonity-oracle/types"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayPlugin(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := samplerecorder.NewRecorder(dir, 1024*1024, 2)
+	require.NoError(t, err)
+
+	ts := time.Now().Unix() - 3600
+	price := types.Price{Timestamp: ts - 1, Symbol: "NTN-USD", Price: decimal.RequireFromString("1.5")}
+	other := types.Price{Timestamp: ts - 1, Symbol: "ATN-USD", Price: decimal.RequireFromString("0.5")}
+	records := []*samplerecorder.Record{
+		{Plugin: "crypto_kraken", Version: "v1.0.0", Source: types.SrcCEX, TS: ts, Latency: 10,
+			Report: types.PluginPriceReport{Prices: []types.Price{price, other}}},
+		{Plugin: "crypto_okx", Version: "v2.0.0", Source: types.SrcCEX, TS: ts,
+			Report: types.PluginPriceReport{Prices: []types.Price{price}}},
+		{Plugin: "crypto_kraken", Version: "v1.0.0", Source: types.SrcCEX, TS: ts + 10,
+			Error: types.ErrFetchTimeout.Error()},
+	}
+	for _, rec := range records {
+		require.NoError(t, recorder.Record(rec))
+	}
+	require.NoError(t, recorder.Close())
+
+	recs, err := samplerecorder.ReadRecords(dir)
+	require.NoError(t, err)
+	p := NewReplayPlugin(&defaultConfig, "crypto_kraken", recs)
+
+	state, err := p.State(0)
+	require.NoError(t, err)
+	require.Equal(t, "v1.0.0", state.Version)
+	require.Equal(t, []string{"ATN-USD", "NTN-USD"}, state.AvailableSymbols)
+
+	start := time.Now()
+	report, err := p.FetchPrices([]string{"NTN-USD", "EUR-USD"})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	require.Len(t, report.Prices, 1)
+	require.True(t, price.Price.Equal(report.Prices[0].Price))
+	// the quote is as old as it was on the recorded sampling.
+	require.InDelta(t, time.Now().Unix()-1, report.Prices[0].Timestamp, 1)
+	require.Equal(t, []string{"EUR-USD"}, report.UnRecognizableSymbols)
+
+	_, err = p.FetchPrices([]string{"NTN-USD"})
+	require.EqualError(t, err, types.ErrFetchTimeout.Error())
+
+	_, err = p.FetchPrices([]string{"NTN-USD"})
+	require.Error(t, err)
+
+	_, err = NewReplayPlugin(&defaultConfig, "crypto_coinbase", recs).State(0)
+	require.Error(t, err)
+}
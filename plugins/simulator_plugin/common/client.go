@@ -8,7 +8,6 @@ import (
 	"io"
 	"net/url"
 	"os"
-	"time"
 
 	"github.com/hashicorp/go-hclog"
 )
@@ -26,7 +25,7 @@ type SIMClient struct {
 }
 
 func NewSIMClient(conf *config.PluginConfig) *SIMClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewClientFromConf(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Info,
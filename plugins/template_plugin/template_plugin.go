@@ -193,7 +193,7 @@ type TemplateClient struct {
 }
 
 func NewTemplateClient(conf *config.PluginConfig) *TemplateClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewClientFromConf(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Debug,
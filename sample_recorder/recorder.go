@@ -0,0 +1,200 @@
+package samplerecorder
+
+import (
+	"autonity-oracle/types"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DirName    = "samples" // the directory of the logs under the profile directory of the oracle server.
+	filePrefix = "samples-"
+	fileSuffix = ".jsonl.gz"
+)
+
+// Record is a price fetching of a plugin, it carries the report or the error returned by the plugin.
+type Record struct {
+	Plugin  string                  `json:"plugin"`
+	Version string                  `json:"version"`
+	Source  types.DataSourceType    `json:"source"`
+	TS      int64                   `json:"ts"`      // the sampling TS of the fetching.
+	Symbols []string                `json:"symbols"` // the symbols asked by the oracle server.
+	Latency int64                   `json:"latency"` // the milliseconds that the plugin took to return.
+	Error   string                  `json:"error,omitempty"`
+	Report  types.PluginPriceReport `json:"report"`
+}
+
+// countingWriter counts the bytes written into the file.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// Recorder logs the records in JSON lines into the gzip files of a directory, the file is rotated once it exceeds the
+// max size, and the oldest files are removed to keep at most the max files. Each record is flushed into the file, thus
+// the records in the file being written are readable until the last flush.
+type Recorder struct {
+	mutex    sync.Mutex
+	dir      string
+	maxSize  int64
+	maxFiles int
+
+	file    *os.File
+	counter *countingWriter
+	gz      *gzip.Writer
+}
+
+// NewRecorder creates the recorder on the directory, the max size is in bytes.
+func NewRecorder(dir string, maxSize int64, maxFiles int) (*Recorder, error) {
+	if maxSize <= 0 || maxFiles <= 0 {
+		return nil, fmt.Errorf("invalid max size %d or max files %d", maxSize, maxFiles)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir, maxSize: maxSize, maxFiles: maxFiles}, nil
+}
+
+// Record appends the record into the current file.
+func (r *Recorder) Record(rec *Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.gz == nil || r.counter.n >= r.maxSize {
+		if err = r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err = r.gz.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return r.gz.Flush()
+}
+
+// rotate closes the current file and opens a new one, then it removes the oldest files out of the max files.
+func (r *Recorder) rotate() error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+
+	// the files are named by the nanoseconds of creation, thus they are sorted by names.
+	name := filepath.Join(r.dir, fmt.Sprintf("%s%020d%s", filePrefix, time.Now().UnixNano(), fileSuffix))
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	r.file = f
+	r.counter = &countingWriter{w: f}
+	r.gz = gzip.NewWriter(r.counter)
+
+	files, err := LogFiles(r.dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > r.maxFiles {
+		if err = os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+func (r *Recorder) closeFile() error {
+	if r.gz == nil {
+		return nil
+	}
+	err := r.gz.Close()
+	if e := r.file.Close(); err == nil {
+		err = e
+	}
+	r.gz = nil
+	r.file = nil
+	return err
+}
+
+// Close flushes and closes the current file.
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.closeFile()
+}
+
+// LogFiles lists the log files of the directory from the oldest to the latest.
+func LogFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, filePrefix+"*"+fileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadRecords reads the records of the log files of the directory from the oldest to the latest, the records of the
+// file being written are read until the last flush.
+func ReadRecords(dir string) ([]Record, error) {
+	files, err := LogFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, file := range files {
+		recs, err := readFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		records = append(records, recs...)
+	}
+	return records, nil
+}
+
+func readFile(file string) ([]Record, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err = json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	// the file being written has no gzip footer yet.
+	if err = scanner.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,72 @@
+package samplerecorder
+
+import (
+	"autonity-oracle/types"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewRecorder(dir, 0, 1)
+	require.Error(t, err)
+
+	// a tiny max size rotates the file on each record.
+	r, err := NewRecorder(dir, 1, 3)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		rec := &Record{
+			Plugin:  "forex_ecb",
+			Version: "v0.0.1",
+			Source:  types.SrcCEX,
+			TS:      int64(i),
+			Symbols: []string{"EUR-USD", "XAU-USD"},
+			Latency: 15,
+			Report: types.PluginPriceReport{
+				Prices: []types.Price{{Timestamp: int64(i), Symbol: "EUR-USD",
+					Price: decimal.RequireFromString("1.1"), Volume: big.NewInt(1)}},
+				UnRecognizableSymbols: []string{"XAU-USD"},
+			},
+		}
+		if i == 4 {
+			rec.Error = "fetch timeout"
+			rec.Report = types.PluginPriceReport{}
+		}
+		require.NoError(t, r.Record(rec))
+	}
+
+	// the oldest files are removed, and the file being written is readable.
+	files, err := LogFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	records, err := ReadRecords(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, int64(2), records[0].TS)
+	require.Equal(t, "1.1", records[0].Report.Prices[0].Price.String())
+	require.Equal(t, []string{"XAU-USD"}, records[0].Report.UnRecognizableSymbols)
+	require.Equal(t, "fetch timeout", records[2].Error)
+
+	require.NoError(t, r.Close())
+	records, err = ReadRecords(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	// the records are appended into the same file under the max size.
+	dir = t.TempDir()
+	r, err = NewRecorder(dir, 1024*1024, 3)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, r.Record(&Record{Plugin: fmt.Sprintf("p%d", i)}))
+	}
+	require.NoError(t, r.Close())
+	files, err = LogFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	records, err = ReadRecords(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 10)
+}
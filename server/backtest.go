@@ -0,0 +1,432 @@
+package server
+
+import (
+	"autonity-oracle/config"
+	pWrapper "autonity-oracle/plugin_wrapper"
+	samplerecorder "autonity-oracle/sample_recorder"
+	"autonity-oracle/types"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultOutlierThreshold is the deviation in basis points from the median that a report is taken as an outlier.
+var DefaultOutlierThreshold int64 = 300
+
+// DefaultBacktestStrategies compare the default sampling config with the nearest sample and the VWAP of AMM samples.
+var DefaultBacktestStrategies = []BacktestStrategy{
+	{
+		Name:        "default",
+		AMMSampling: config.DefaultAMMSamplingConfig,
+		CEXSampling: config.DefaultSamplingConfig,
+	},
+	{
+		Name:        "nearest",
+		AMMSampling: config.DefaultSamplingConfig,
+		CEXSampling: config.DefaultSamplingConfig,
+	},
+	{
+		Name: "amm-vwap",
+		AMMSampling: config.SamplingConfig{
+			SampleTTL: config.DefaultAMMSamplingConfig.SampleTTL,
+			Window:    config.DefaultAMMSamplingConfig.Window,
+			Strategy:  config.AggregationStrategyVWAP,
+		},
+		CEXSampling: config.DefaultSamplingConfig,
+	},
+}
+
+// BacktestStrategy is a set of the aggregation and confidence configs to be replayed.
+type BacktestStrategy struct {
+	Name               string                `json:"name" yaml:"name"`
+	ConfidenceStrategy int                   `json:"confidenceStrategy" yaml:"confidenceStrategy"`
+	MaxSampleAge       int                   `json:"maxSampleAge" yaml:"maxSampleAge"`
+	AMMSampling        config.SamplingConfig `json:"ammSampling" yaml:"ammSampling"`
+	CEXSampling        config.SamplingConfig `json:"cexSampling" yaml:"cexSampling"`
+}
+
+// BacktestConfig is the schema of the backtesting config, the defaults are taken for the absent fields.
+type BacktestConfig struct {
+	Strategies       []BacktestStrategy   `json:"strategies" yaml:"strategies"`
+	SymbolRoutes     []config.SymbolRoute `json:"symbolRoutes" yaml:"symbolRoutes"`
+	OutlierThreshold int64                `json:"outlierThreshold" yaml:"outlierThreshold"` // in basis points.
+}
+
+// BacktestSample is a data point recorded from a plugin at the sampling timestamp.
+type BacktestSample struct {
+	Plugin  string
+	SrcType types.DataSourceType
+	TS      int64
+	Price   types.Price
+}
+
+// RoundMedian is the historic median price of a symbol of a round on the oracle contract.
+type RoundMedian struct {
+	Round  uint64
+	TS     int64 // the sampling timestamp of the round, it is the timestamp of the round block.
+	Symbol string
+	Price  decimal.Decimal
+}
+
+// BacktestResult is the distribution of the deviations from the medians of a symbol with a strategy, the deviations
+// are in basis points.
+type BacktestResult struct {
+	Strategy       string
+	Symbol         string
+	Rounds         int // the rounds that a price is produced to compare with the median.
+	Missing        int // the rounds that no price is produced.
+	Outliers       int
+	OutlierRate    float64
+	MeanConfidence float64
+	Mean           float64
+	P50            float64
+	P90            float64
+	P99            float64
+	Max            float64
+	deviations     []float64
+	confidences    uint64
+}
+
+// LoadBacktestConfig reads the backtesting config from a yaml file and fills the defaults.
+func LoadBacktestConfig(file string) (*BacktestConfig, error) {
+	conf := &BacktestConfig{}
+	if len(file) != 0 {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err = yaml.Unmarshal(data, conf); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(conf.Strategies) == 0 {
+		conf.Strategies = DefaultBacktestStrategies
+	}
+	if conf.SymbolRoutes == nil {
+		conf.SymbolRoutes = config.DefaultSymbolRoutes
+	}
+	if conf.OutlierThreshold == 0 {
+		conf.OutlierThreshold = DefaultOutlierThreshold
+	}
+	for i, s := range conf.Strategies {
+		if len(s.Name) == 0 {
+			return nil, fmt.Errorf("strategy %d has no name", i)
+		}
+		serverConf := config.DefaultConfig
+		serverConf.AMMSampling = s.AMMSampling
+		serverConf.CEXSampling = s.CEXSampling
+		if err := config.ValidateSamplingConfigs(&serverConf); err != nil {
+			return nil, fmt.Errorf("strategy %s: %w", s.Name, err)
+		}
+	}
+	return conf, nil
+}
+
+// readCSV reads the records of a csv file with a header line, the records are returned as the maps of the columns.
+func readCSV(file string, required ...string) ([]map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	for _, c := range required {
+		var found bool
+		for _, h := range header {
+			found = found || h == c
+		}
+		if !found {
+			return nil, fmt.Errorf("missing column %s in %s", c, file)
+		}
+	}
+
+	var records []map[string]string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		record := make(map[string]string)
+		for i, v := range row {
+			if i < len(header) {
+				record[header[i]] = strings.TrimSpace(v)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// LoadBacktestSamples reads the samples from a csv file with the columns: timestamp, plugin, source, symbol, price,
+// and the optional volume and quoteTimestamp. The source is either amm or cex.
+func LoadBacktestSamples(file string) ([]BacktestSample, error) {
+	records, err := readCSV(file, "timestamp", "plugin", "source", "symbol", "price")
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]BacktestSample, 0, len(records))
+	for i, r := range records {
+		ts, err := strconv.ParseInt(r["timestamp"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid timestamp: %w", i+2, err)
+		}
+		price, err := decimal.NewFromString(r["price"])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price: %w", i+2, err)
+		}
+
+		var srcType types.DataSourceType
+		switch strings.ToLower(r["source"]) {
+		case "amm":
+			srcType = types.SrcAMM
+		case "cex":
+			srcType = types.SrcCEX
+		case "anchor":
+			srcType = types.SrcAnchor
+		default:
+			return nil, fmt.Errorf("line %d: unknown source %s", i+2, r["source"])
+		}
+
+		volume := types.DefaultVolume
+		if v := r["volume"]; len(v) != 0 {
+			vol, ok := new(big.Int).SetString(v, 10)
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid volume %s", i+2, v)
+			}
+			volume = vol
+		}
+
+		quoteTS := ts
+		if q := r["quoteTimestamp"]; len(q) != 0 {
+			if quoteTS, err = strconv.ParseInt(q, 10, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid quote timestamp: %w", i+2, err)
+			}
+		}
+
+		samples = append(samples, BacktestSample{
+			Plugin:  r["plugin"],
+			SrcType: srcType,
+			TS:      ts,
+			Price: types.Price{
+				Timestamp: quoteTS,
+				Symbol:    r["symbol"],
+				Price:     price,
+				Volume:    volume,
+			},
+		})
+	}
+	return samples, nil
+}
+
+// LoadRecordedSamples reads the samples from the price reports logged by the sample recorder in the directory, the
+// failed fetching are skipped.
+func LoadRecordedSamples(dir string) ([]BacktestSample, error) {
+	records, err := samplerecorder.ReadRecords(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []BacktestSample
+	for _, r := range records {
+		if len(r.Error) != 0 {
+			continue
+		}
+		for _, p := range r.Report.Prices {
+			if p.Volume == nil {
+				p.Volume = types.DefaultVolume
+			}
+			samples = append(samples, BacktestSample{Plugin: r.Plugin, SrcType: r.Source, TS: r.TS, Price: p})
+		}
+	}
+	return samples, nil
+}
+
+// LoadRoundMedians reads the historic medians from a csv file with the columns: round, timestamp, symbol, price.
+func LoadRoundMedians(file string) ([]RoundMedian, error) {
+	records, err := readCSV(file, "round", "timestamp", "symbol", "price")
+	if err != nil {
+		return nil, err
+	}
+
+	medians := make([]RoundMedian, 0, len(records))
+	for i, r := range records {
+		round, err := strconv.ParseUint(r["round"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid round: %w", i+2, err)
+		}
+		ts, err := strconv.ParseInt(r["timestamp"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid timestamp: %w", i+2, err)
+		}
+		price, err := decimal.NewFromString(r["price"])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price: %w", i+2, err)
+		}
+		medians = append(medians, RoundMedian{Round: round, TS: ts, Symbol: r["symbol"], Price: price})
+	}
+	return medians, nil
+}
+
+// Backtest replays the samples through the price aggregation of the oracle server with each strategy, and it compares
+// the prices produced on the rounds with the medians of the rounds. The results are sorted by strategy and symbol.
+func Backtest(conf *BacktestConfig, samples []BacktestSample, medians []RoundMedian) ([]*BacktestResult, error) {
+	router, err := newSymbolRouter(conf.SymbolRoutes)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]BacktestSample, len(samples))
+	copy(sorted, samples)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].TS < sorted[j].TS })
+
+	// group the medians by rounds.
+	rounds := make(map[uint64][]RoundMedian)
+	var roundIDs []uint64
+	for _, m := range medians {
+		if _, ok := rounds[m.Round]; !ok {
+			roundIDs = append(roundIDs, m.Round)
+		}
+		rounds[m.Round] = append(rounds[m.Round], m)
+	}
+	sort.Slice(roundIDs, func(i, j int) bool { return roundIDs[i] < roundIDs[j] })
+
+	var results []*BacktestResult
+	for _, strategy := range conf.Strategies {
+		os := &Server{
+			logger: hclog.NewNullLogger(),
+			conf: &config.Config{
+				ConfidenceStrategy: strategy.ConfidenceStrategy,
+				MaxSampleAge:       strategy.MaxSampleAge,
+				AMMSampling:        strategy.AMMSampling,
+				CEXSampling:        strategy.CEXSampling,
+			},
+			router:         router,
+			runningPlugins: make(map[string]*pWrapper.PluginWrapper),
+			voteRecords:    make(map[uint64]*types.VoteRecord),
+			pricePrecision: decimal.NewFromBigInt(common.Big1, int32(OracleDecimals)),
+		}
+
+		stats := make(map[string]*BacktestResult)
+		next := 0
+		for _, id := range roundIDs {
+			round := rounds[id]
+			target := round[0].TS
+
+			// feed the samples taken until the round, and GC the expired ones as the server does.
+			for ; next < len(sorted) && sorted[next].TS <= target; next++ {
+				s := sorted[next]
+				plugin, ok := os.runningPlugins[s.Plugin]
+				if !ok {
+					plugin = pWrapper.NewSampleWrapper(s.Plugin, s.SrcType, nil)
+					os.runningPlugins[s.Plugin] = plugin
+				}
+				plugin.AddSample([]types.Price{s.Price}, s.TS)
+			}
+			for _, plugin := range os.runningPlugins {
+				plugin.GCSamplesBefore(target - int64(os.samplingConfig(plugin.DataSourceType()).SampleTTL))
+			}
+
+			os.curRound = id
+			os.curSampleTS = target
+			os.protocolSymbols = nil
+			for _, m := range round {
+				os.protocolSymbols = append(os.protocolSymbols, m.Symbol)
+			}
+
+			prices, err := os.aggregateProtocolSymbolPrices(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			os.voteRecords[id] = &types.VoteRecord{RoundID: id, Symbols: os.protocolSymbols, Prices: prices}
+			os.gcVoteRecords()
+
+			for _, m := range round {
+				r, ok := stats[m.Symbol]
+				if !ok {
+					r = &BacktestResult{Strategy: strategy.Name, Symbol: m.Symbol}
+					stats[m.Symbol] = r
+				}
+				p, ok := prices[m.Symbol]
+				if !ok || m.Price.IsZero() {
+					r.Missing++
+					continue
+				}
+				// the deviation is compared in the precision of the reports.
+				reported := p.Price.Mul(os.pricePrecision).Truncate(0).Div(os.pricePrecision)
+				deviation := reported.Sub(m.Price).Abs().Div(m.Price).Mul(decimal.NewFromInt(10000)).InexactFloat64()
+				r.deviations = append(r.deviations, deviation)
+				r.confidences += uint64(p.Confidence)
+				if deviation > float64(conf.OutlierThreshold) {
+					r.Outliers++
+				}
+			}
+		}
+
+		for _, r := range stats {
+			r.summarize()
+			results = append(results, r)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Strategy != results[j].Strategy {
+			return results[i].Strategy < results[j].Strategy
+		}
+		return results[i].Symbol < results[j].Symbol
+	})
+	return results, nil
+}
+
+// summarize computes the distribution of the deviations.
+func (r *BacktestResult) summarize() {
+	r.Rounds = len(r.deviations)
+	if r.Rounds == 0 {
+		return
+	}
+	sort.Float64s(r.deviations)
+	var sum float64
+	for _, d := range r.deviations {
+		sum += d
+	}
+	r.Mean = sum / float64(r.Rounds)
+	r.P50 = r.percentile(0.5)
+	r.P90 = r.percentile(0.9)
+	r.P99 = r.percentile(0.99)
+	r.Max = r.deviations[r.Rounds-1]
+	r.OutlierRate = float64(r.Outliers) / float64(r.Rounds)
+	r.MeanConfidence = float64(r.confidences) / float64(r.Rounds)
+}
+
+// percentile takes the nearest rank of the sorted deviations.
+func (r *BacktestResult) percentile(p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(r.deviations)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return r.deviations[rank]
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"autonity-oracle/config"
+	samplerecorder "autonity-oracle/sample_recorder"
+	"autonity-oracle/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBacktest(t *testing.T) {
+	dir := t.TempDir()
+	samplesFile := filepath.Join(dir, "samples.csv")
+	require.NoError(t, os.WriteFile(samplesFile, []byte(`timestamp,plugin,source,symbol,price,volume
+100,forex_a,cex,EUR-USD,1.10,
+100,forex_b,cex,EUR-USD,1.12,
+100,usdc,cex,USDC-USD,1.0,
+100,amm,amm,ATN-USDC,2.0,100
+100,amm,amm,NTN-USDC,10.0,100
+130,forex_a,cex,EUR-USD,1.20,
+130,forex_b,cex,EUR-USD,1.20,
+`), 0600))
+	mediansFile := filepath.Join(dir, "medians.csv")
+	require.NoError(t, os.WriteFile(mediansFile, []byte(`round,timestamp,symbol,price
+1,100,EUR-USD,1.11
+1,100,ATN-USD,2.0
+1,100,NTN-ATN,5.0
+2,130,EUR-USD,1.11
+3,160,EUR-USD,1.20
+3,160,GBP-USD,1.30
+`), 0600))
+	confFile := filepath.Join(dir, "backtest.yml")
+	require.NoError(t, os.WriteFile(confFile, []byte(`strategies:
+  - name: linear
+    confidenceStrategy: 0
+    cexSampling: {sampleTTL: 1200}
+    ammSampling: {sampleTTL: 1200, window: 1200}
+  - name: fixed
+    confidenceStrategy: 1
+    cexSampling: {sampleTTL: 1200}
+    ammSampling: {sampleTTL: 1200, window: 1200}
+`), 0600))
+
+	conf, err := LoadBacktestConfig(confFile)
+	require.NoError(t, err)
+	require.Equal(t, DefaultOutlierThreshold, conf.OutlierThreshold)
+	require.Equal(t, config.DefaultSymbolRoutes, conf.SymbolRoutes)
+	samples, err := LoadBacktestSamples(samplesFile)
+	require.NoError(t, err)
+	require.Len(t, samples, 7)
+	medians, err := LoadRoundMedians(mediansFile)
+	require.NoError(t, err)
+	require.Len(t, medians, 6)
+
+	results, err := Backtest(conf, samples, medians)
+	require.NoError(t, err)
+	require.Len(t, results, 8)
+
+	byKey := make(map[string]*BacktestResult)
+	for _, r := range results {
+		byKey[r.Strategy+"/"+r.Symbol] = r
+	}
+
+	eur := byKey["fixed/EUR-USD"]
+	require.Equal(t, 3, eur.Rounds)
+	require.Equal(t, 1, eur.Outliers)
+	require.InDelta(t, 1.0/3, eur.OutlierRate, 1e-9)
+	require.Equal(t, float64(0), eur.P50)
+	require.InDelta(t, 810.81, eur.Max, 0.01)
+	require.Equal(t, float64(MaxConfidence), eur.MeanConfidence)
+	require.Less(t, byKey["linear/EUR-USD"].MeanConfidence, float64(MaxConfidence))
+
+	// the bridged symbols are derived from the conversion paths.
+	for _, s := range []string{"ATN-USD", "NTN-ATN"} {
+		r := byKey["fixed/"+s]
+		require.Equal(t, 1, r.Rounds, s)
+		require.Equal(t, float64(0), r.Max, s)
+	}
+
+	gbp := byKey["fixed/GBP-USD"]
+	require.Equal(t, 0, gbp.Rounds)
+	require.Equal(t, 1, gbp.Missing)
+
+	_, err = LoadBacktestConfig(filepath.Join(dir, "missing.yml"))
+	require.Error(t, err)
+	conf, err = LoadBacktestConfig("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultBacktestStrategies, conf.Strategies)
+}
+
+func TestLoadRecordedSamples(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := samplerecorder.NewRecorder(dir, 1024*1024, 2)
+	require.NoError(t, err)
+	price := types.Price{Timestamp: 99, Symbol: "EUR-USD", Price: decimal.RequireFromString("1.1")}
+	require.NoError(t, recorder.Record(&samplerecorder.Record{Plugin: "forex_a", Source: types.SrcCEX, TS: 100,
+		Report: types.PluginPriceReport{Prices: []types.Price{price}}}))
+	require.NoError(t, recorder.Record(&samplerecorder.Record{Plugin: "forex_b", Source: types.SrcCEX, TS: 100,
+		Error: types.ErrFetchTimeout.Error()}))
+	require.NoError(t, recorder.Close())
+
+	samples, err := LoadRecordedSamples(dir)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	require.Equal(t, "forex_a", samples[0].Plugin)
+	require.Equal(t, int64(100), samples[0].TS)
+	require.Equal(t, int64(99), samples[0].Price.Timestamp)
+	require.Equal(t, types.DefaultVolume, samples[0].Price.Volume)
+}
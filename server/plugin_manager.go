@@ -110,6 +110,9 @@ func (os *Server) setupNewPlugin(name string, conf *config.PluginConfig) (*pWrap
 	}
 
 	pluginWrapper := pWrapper.NewPluginWrapper(os.conf.LoggingLevel, name, os.conf.PluginDIR, os, conf)
+	if os.recorder != nil {
+		pluginWrapper.SetRecorder(os.recorder)
+	}
 	if err := pluginWrapper.Initialize(os.chainID); err != nil {
 		// if the plugin states that a service key is missing, then we mark it down, thus the runtime discovery can
 		// skip those plugins without a key configured.
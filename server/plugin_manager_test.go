@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/event"
@@ -40,17 +41,23 @@ func TestPluginManagement(t *testing.T) {
 	require.NoError(t, err)
 
 	conf := &config.Config{
-		ConfigFile:         "../test_data/oracle_config.yml",
-		LoggingLevel:       hclog.Level(config.DefaultConfig.LoggingLevel), //nolint
-		GasTipCap:          config.DefaultConfig.GasTipCap,
-		VoteBuffer:         config.DefaultConfig.VoteBuffer,
-		Key:                key,
-		AutonityWSUrl:      config.DefaultConfig.AutonityWSUrl,
-		PluginDIR:          "../plugins/template_plugin/bin",
-		ProfileDir:         ".",
-		ConfidenceStrategy: 0,
-		PluginConfigs:      nil,
-		MetricConfigs:      config.MetricConfig{},
+		ConfigFile:          "../test_data/oracle_config.yml",
+		LoggingLevel:        hclog.Level(config.DefaultConfig.LoggingLevel), //nolint
+		GasTipCap:           config.DefaultConfig.GasTipCap,
+		VoteBuffer:          config.DefaultConfig.VoteBuffer,
+		Key:                 key,
+		AutonityWSUrl:       config.DefaultConfig.AutonityWSUrl,
+		PluginDIR:           "../plugins/template_plugin/bin",
+		ProfileDir:          ".",
+		ConfidenceStrategy:  0,
+		PreSamplingRange:    config.DefaultConfig.PreSamplingRange,
+		SamplingInterval:    time.Duration(config.DefaultConfig.SamplingInterval) * time.Second,
+		HealthCheckInterval: time.Duration(config.DefaultConfig.HealthCheckInterval) * time.Second,
+		AMMSampling:         config.DefaultAMMSamplingConfig,
+		CEXSampling:         config.DefaultSamplingConfig,
+		SymbolRoutes:        config.DefaultSymbolRoutes,
+		PluginConfigs:       nil,
+		MetricConfigs:       config.MetricConfig{},
 	}
 
 	t.Run("test plugin runtime management, add new plugin", func(t *testing.T) {
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"autonity-oracle/config"
+	contract "autonity-oracle/contract_binder/contract"
+	"autonity-oracle/contract_binder/contract/simulated"
+	"autonity-oracle/types"
+	"autonity-oracle/types/mock"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScenarioCommitReveal runs the oracle server against the simulated L1, the server commits on the 2nd round and
+// reveals on the 3rd round, the revealed prices are aggregated on-chain by the end of the 3rd round.
+func TestScenarioCommitReveal(t *testing.T) {
+	key, err := config.LoadKey(testKeyFile, config.DefaultConfig.KeyPassword)
+	require.NoError(t, err)
+
+	hasher, err := NewCommitmentHashComputer()
+	require.NoError(t, err)
+	simConf := simulated.DefaultConfig(key.Address)
+	simConf.VotePeriod = 5
+	backend, err := simulated.NewBackend(simConf, hasher)
+	require.NoError(t, err)
+
+	conf := &config.Config{
+		ConfigFile:          "../test_data/oracle_config.yml",
+		LoggingLevel:        hclog.Level(config.DefaultConfig.LoggingLevel), //nolint
+		GasTipCap:           config.DefaultConfig.GasTipCap,
+		VoteBuffer:          config.DefaultConfig.VoteBuffer,
+		Key:                 key,
+		AutonityWSUrl:       config.DefaultConfig.AutonityWSUrl,
+		PluginDIR:           "../plugins/template_plugin/bin",
+		ProfileDir:          t.TempDir(),
+		PreSamplingRange:    config.DefaultConfig.PreSamplingRange,
+		SamplingInterval:    time.Second,
+		HealthCheckInterval: time.Second,
+		AMMSampling:         config.DefaultAMMSamplingConfig,
+		CEXSampling:         config.DefaultSamplingConfig,
+		SymbolRoutes:        config.DefaultSymbolRoutes,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := NewServer(conf, mock.NewMockDialer(ctrl), backend, backend)
+	go srv.Start()
+	backend.StartMining(time.Second)
+	defer func() {
+		backend.StopMining()
+		srv.Stop()
+	}()
+
+	require.Eventually(t, func() bool {
+		data, err := backend.LatestRoundData(nil, "NTN-USD")
+		return err == nil && data.Success
+	}, 40*time.Second, 500*time.Millisecond)
+
+	filterer, err := contract.NewOracleFilterer(types.OracleContractAddress, backend)
+	require.NoError(t, err)
+	votes, err := filterer.FilterSuccessfulVote(&bind.FilterOpts{}, []common.Address{key.Address})
+	require.NoError(t, err)
+	defer votes.Close()
+	var voted int
+	for votes.Next() {
+		voted++
+	}
+	require.GreaterOrEqual(t, voted, 2)
+	require.Equal(t, uint64(0), backend.NonRevealCount(key.Address))
+
+	// the server recovers the subscriptions after the L1 connection is dropped, and keeps voting in the later rounds.
+	round, err := backend.GetRound(nil)
+	require.NoError(t, err)
+	backend.DropSubscriptions()
+	require.Eventually(t, func() bool {
+		data, err := backend.LatestRoundData(nil, "NTN-USD")
+		return err == nil && data.Success && data.Round.Cmp(round) > 0
+	}, 40*time.Second, 500*time.Millisecond)
+}
@@ -5,8 +5,10 @@ import (
 	contract "autonity-oracle/contract_binder/contract"
 	"autonity-oracle/helpers"
 	"autonity-oracle/monitor"
+	"autonity-oracle/notifier"
 	pWrapper "autonity-oracle/plugin_wrapper"
 	common2 "autonity-oracle/plugins/common"
+	samplerecorder "autonity-oracle/sample_recorder"
 	"autonity-oracle/types"
 	"context"
 	"crypto/rand"
@@ -30,20 +32,15 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/modern-go/reflect2"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-var BridgedSymbols = map[string]string{
-	ATNUSD: ATNUSDC,
-	NTNUSD: NTNUSDC,
-}
-
 var (
-	saltRange       = new(big.Int).SetUint64(math.MaxInt64)
-	alertBalance    = new(big.Int).SetUint64(2000000000000) // 2000 Gwei, 0.000002 Ether
-	invalidPrice    = big.NewInt(0)
-	invalidSalt     = big.NewInt(0)
-	tenSecsInterval = 10 * time.Second // ticker to check L2 connectivity and gc round data.
-	oneSecsInterval = 1 * time.Second  // sampling interval during data pre-sampling period.
+	saltRange    = new(big.Int).SetUint64(math.MaxInt64)
+	alertBalance = new(big.Int).SetUint64(2000000000000) // 2000 Gwei, 0.000002 Ether
+	invalidPrice = big.NewInt(0)
+	invalidSalt  = big.NewInt(0)
 )
 
 const (
@@ -51,6 +48,8 @@ const (
 	ATNUSD              = "ATN-USD"
 	NTNUSD              = "NTN-USD"
 	USDCUSD             = "USDC-USD"
+	USDCUSDT            = "USDC-USDT"
+	USDTUSD             = "USDT-USD"
 	ATNUSDC             = "ATN-USDC"
 	NTNUSDC             = "NTN-USDC"
 	MaxConfidence       = 100
@@ -67,11 +66,12 @@ type Server struct {
 	conf   *config.Config
 
 	doneCh        chan struct{}
-	regularTicker *time.Ticker // the clock source to trigger the 10s interval job.
-	psTicker      *time.Ticker // the pre-sampling ticker in 1s.
+	regularTicker *time.Ticker // the clock source to trigger the health checking interval job.
+	psTicker      *time.Ticker // the pre-sampling ticker of the sampling interval.
 
 	runningPlugins  map[string]*pWrapper.PluginWrapper // the plugin clients that connect with different adapters.
 	samplingSymbols []string                           // the symbols for data fetching in oracle service, can be different from the required protocol symbols.
+	router          *symbolRouter                      // the router to derive symbols' prices from the conversion paths.
 
 	keyRequiredPlugins map[string]struct{} // saving those plugins which require a key granted by data provider
 
@@ -81,10 +81,14 @@ type Server struct {
 	client         types.Blockchain
 	abi            abi.ABI
 
-	curRound       uint64 //round ID.
-	votePeriod     uint64 //vote period.
-	curSampleTS    int64  //the data sample TS of the current round.
-	curRoundHeight uint64 //The block height on which the last round rotation happens.
+	curRound       uint64    //round ID.
+	votePeriod     uint64    //vote period.
+	curSampleTS    int64     //the data sample TS of the current round.
+	curRoundHeight uint64    //The block height on which the last round rotation happens.
+	curRoundTime   time.Time //The time on which the last round event is received.
+
+	roundCtx    context.Context        // the tracing context of the last round.
+	roundTraces map[uint64]*roundTrace // the traces of the recent rounds, the receipts of their votes are traced into them.
 
 	protocolSymbols []string //symbols required for the voting on the oracle contract protocol.
 	pricePrecision  decimal.Decimal
@@ -114,10 +118,13 @@ type Server struct {
 	lastSampledTS   int64
 
 	sampleEventFeed        event.Feed
-	lostSync               bool // set to true if the connectivity with L1 Autonity network is dropped during runtime.
+	lostSync               bool      // set to true if the connectivity with L1 Autonity network is dropped during runtime.
+	lostSyncAt             time.Time // the time on which the connectivity with L1 Autonity network was dropped.
 	commitmentHashComputer *CommitmentHashComputer
 
 	memories Memories
+	recorder *samplerecorder.Recorder // the recorder of the plugins' price reports, nil if the recording is disabled.
+	notifier *notifier.Notifier       // the notifier of the critical conditions, nil if the alerting is disabled.
 
 	configWatcher  *fsnotify.Watcher // config file watcher which watches the config changes.
 	pluginsWatcher *fsnotify.Watcher // plugins watcher which watches the changes of plugins and the plugins' configs.
@@ -126,6 +133,15 @@ type Server struct {
 
 func NewServer(conf *config.Config, dialer types.Dialer, client types.Blockchain,
 	oc contract.ContractAPI) *Server {
+	// the tickers panic on the non-positive intervals, fall back to the defaults for the configs not made by MakeConfig.
+	healthCheckInterval := conf.HealthCheckInterval
+	if healthCheckInterval <= 0 {
+		healthCheckInterval = time.Duration(config.DefaultConfig.HealthCheckInterval) * time.Second
+	}
+	samplingInterval := conf.SamplingInterval
+	if samplingInterval <= 0 {
+		samplingInterval = time.Duration(config.DefaultConfig.SamplingInterval) * time.Second
+	}
 	os := &Server{
 		conf:               conf,
 		dialer:             dialer,
@@ -135,8 +151,8 @@ func NewServer(conf *config.Config, dialer types.Dialer, client types.Blockchain
 		runningPlugins:     make(map[string]*pWrapper.PluginWrapper),
 		keyRequiredPlugins: make(map[string]struct{}),
 		doneCh:             make(chan struct{}),
-		regularTicker:      time.NewTicker(tenSecsInterval),
-		psTicker:           time.NewTicker(oneSecsInterval),
+		regularTicker:      time.NewTicker(healthCheckInterval),
+		psTicker:           time.NewTicker(samplingInterval),
 		pricePrecision:     decimal.NewFromBigInt(common.Big1, int32(OracleDecimals)),
 	}
 
@@ -168,6 +184,13 @@ func NewServer(conf *config.Config, dialer types.Dialer, client types.Blockchain
 	}
 	os.commitmentHashComputer = commitmentHashComputer
 
+	router, err := newSymbolRouter(conf.SymbolRoutes)
+	if err != nil {
+		os.logger.Error("invalid symbol routes", "error", err)
+		o.Exit(1)
+	}
+	os.router = router
+
 	// load memories from persistence.
 	os.memories = Memories{dataDir: conf.ProfileDir}
 	os.memories.init(os.logger)
@@ -176,6 +199,25 @@ func NewServer(conf *config.Config, dialer types.Dialer, client types.Blockchain
 		os.logger.Info("loaded vote records from persistence", "records", len(os.voteRecords))
 	}
 
+	if conf.SampleRecorder.Enabled {
+		dir := filepath.Join(conf.ProfileDir, samplerecorder.DirName)
+		maxSize := int64(conf.SampleRecorder.MaxSize) * 1024 * 1024
+		os.recorder, err = samplerecorder.NewRecorder(dir, maxSize, conf.SampleRecorder.MaxFiles)
+		if err != nil {
+			os.logger.Error("cannot create sample recorder", "error", err)
+			o.Exit(1)
+		}
+		os.logger.Info("recording plugins' price reports", "dir", dir)
+	}
+
+	if conf.Alerting.Enabled {
+		os.notifier, err = notifier.NewNotifier(conf.Alerting, conf.Key.Address.Hex(), os.logger.Named("notifier"))
+		if err != nil {
+			os.logger.Error("cannot create alert notifier", "error", err)
+			o.Exit(1)
+		}
+	}
+
 	// discover plugins from plugin dir at startup.
 	binaries, err := helpers.ListPlugins(conf.PluginDIR)
 	if len(binaries) == 0 || err != nil {
@@ -318,6 +360,8 @@ func (os *Server) Start() {
 			os.logger.Info("received invalid vote", "cause", invalidVote.Cause, "expected",
 				invalidVote.ExpValue.String(), "actual", invalidVote.ActualValue.String(), "txn", invalidVote.Raw.TxHash)
 			os.setVoteMined(invalidVote.Raw.TxHash, invalidVote.Cause)
+			os.notifier.Notify(notifier.InvalidVote, nil, "vote is invalid", "cause", invalidVote.Cause,
+				"expected", invalidVote.ExpValue.String(), "actual", invalidVote.ActualValue.String(), "txn", invalidVote.Raw.TxHash)
 			if metrics.Enabled {
 				metrics.GetOrRegisterCounter(monitor.InvalidVoteMetric, nil).Inc(1)
 			}
@@ -339,6 +383,8 @@ func (os *Server) Start() {
 			if metrics.Enabled {
 				metrics.GetOrRegisterGauge(monitor.NoRevealVoteMetric, nil).Update(noRevealEvent.MissedReveal.Int64())
 			}
+			os.notifier.Notify(notifier.NoReveal, noRevealEvent.MissedReveal, "missed the reveal of the committed vote",
+				"round", noRevealEvent.Round.Uint64(), "height", noRevealEvent.Raw.BlockNumber)
 
 		case penalizeEvent := <-os.chPenalizedEvent:
 			if err := os.handlePenaltyEvent(penalizeEvent); err != nil {
@@ -373,9 +419,14 @@ func (os *Server) Start() {
 			// IMPORTANT! sync the round and vote period carries by the round event, and store the reference points for
 			// next presampling and the target for sample selection.
 			os.curRound = roundEvent.Round.Uint64()
-			os.votePeriod = roundEvent.VotePeriod.Uint64()
+			if os.votePeriod != roundEvent.VotePeriod.Uint64() {
+				os.votePeriod = roundEvent.VotePeriod.Uint64()
+				os.checkPreSamplingRange()
+			}
 			os.curRoundHeight = roundEvent.Raw.BlockNumber
+			os.curRoundTime = time.Now()
 			os.curSampleTS = roundEvent.Timestamp.Int64()
+			span := os.startRoundSpan(roundEvent.Round, os.curRoundHeight, os.curSampleTS, os.votePeriod)
 
 			// vote for latest protocol symbols.
 			err := os.vote()
@@ -384,8 +435,12 @@ func (os *Server) Start() {
 			}
 			// after vote, reset sampling symbols with the latest protocol symbols.
 			os.resetSamplingSymbols(os.protocolSymbols)
-			os.printLatestRoundData(os.curRound)
+			roundData := os.printLatestRoundData(os.curRound)
+			if metrics.Enabled {
+				os.updateDeviationMetrics(os.curRound, roundData)
+			}
 			os.gcStaleSamples()
+			monitor.EndSpan(span, err)
 		case newSymbolEvent := <-os.chSymbolsEvent:
 			// New symbols are added, add them into the sampling set to prepare data in advance for the coming round's vote.
 			os.logger.Info("handle new symbols", "new symbols", newSymbolEvent.Symbols, "activate at round", newSymbolEvent.Round)
@@ -416,6 +471,12 @@ func (os *Server) Stop() {
 		p := c
 		p.Close()
 	}
+
+	if os.recorder != nil {
+		if err := os.recorder.Close(); err != nil {
+			os.logger.Warn("failed to close sample recorder", "error", err.Error())
+		}
+	}
 }
 
 // trackVoteState works in a pull mode to track if the vote was mined by L1 although there is already a push mode
@@ -447,6 +508,7 @@ func (os *Server) trackVoteState() {
 		vote.Mined = true
 		update = true
 		os.logger.Info("last vote get mined", "txn", vote.TxHash, "receipt", receipt)
+		os.observeMinedVote(r, vote, receipt)
 	}
 
 	if update {
@@ -472,6 +534,7 @@ func (os *Server) setVoteMined(hash common.Hash, err string) {
 					vote.Mined = true
 					vote.Error = err
 					update = true
+					os.observeMinedVote(r, vote, nil)
 					break
 				}
 				// not state change, just skip the flushing.
@@ -493,6 +556,82 @@ func (os *Server) setVoteMined(hash common.Hash, err string) {
 	os.logger.Warn("cannot find the round vote with TXN hash", "current round", os.curRound, "hash", hash)
 }
 
+// observeMinedVote measures and traces the mined vote of the round in the background, as the receipt, the tx and the
+// block of the vote are queried from L1 which should not hold the event loop. The receipt is queried if it is nil.
+func (os *Server) observeMinedVote(round uint64, vote *types.VoteRecord, receipt *tp.Receipt) {
+	rt := os.popRoundTrace(round)
+	if !metrics.Enabled && rt == nil {
+		return
+	}
+
+	// the vote record is kept updating by the event loop, take a copy of it.
+	mined := *vote
+	go func() {
+		if receipt == nil {
+			r, err := os.client.TransactionReceipt(context.Background(), mined.TxHash)
+			if err != nil {
+				os.logger.Debug("cannot get vote receipt", "txn", mined.TxHash, "error", err.Error())
+			}
+			receipt = r
+		}
+		if metrics.Enabled && receipt != nil {
+			os.updateMinedVoteMetrics(&mined, receipt)
+		}
+		rt.traceVoteReceipt(&mined, receipt)
+	}()
+}
+
+// updateMinedVoteMetrics measures the blocks taken by the vote to get mined since the round started, and its fee.
+func (os *Server) updateMinedVoteMetrics(vote *types.VoteRecord, receipt *tp.Receipt) {
+	if receipt.BlockNumber != nil && receipt.BlockNumber.Uint64() >= vote.RoundHeight {
+		monitor.GetOrRegisterHistogram(monitor.VoteMinedBlocksMetric).Update(int64(receipt.BlockNumber.Uint64() - vote.RoundHeight))
+	}
+
+	// the receipt doesn't carry the effective gas price, resolve it from the tx and the base fee of the block.
+	tx, _, err := os.client.TransactionByHash(context.Background(), receipt.TxHash)
+	if err != nil {
+		os.logger.Debug("cannot get vote tx", "txn", receipt.TxHash, "error", err.Error())
+		return
+	}
+	header, err := os.client.HeaderByNumber(context.Background(), receipt.BlockNumber)
+	if err != nil || header.BaseFee == nil {
+		os.logger.Debug("cannot get the base fee of the vote's block", "height", receipt.BlockNumber)
+		return
+	}
+	tip, err := tx.EffectiveGasTip(header.BaseFee)
+	if err != nil {
+		return
+	}
+	price := new(big.Int).Add(header.BaseFee, tip)
+	fee := new(big.Int).Mul(price, new(big.Int).SetUint64(receipt.GasUsed))
+	feeFloat, _ := fee.Float64()
+	metrics.GetOrRegisterGaugeFloat64(monitor.VoteFeeMetric, nil).Update(feeFloat)
+}
+
+// updateDeviationMetrics measures the deviation of our reports from the medians of the round closed by the new round,
+// the round data of the closed round is the one already queried by printLatestRoundData. The reports aggregated in the
+// median of a round are revealed in the round, thus they were committed in the round before it.
+func (os *Server) updateDeviationMetrics(newRound uint64, roundData map[string]contract.IOracleRoundData) {
+	if newRound < 2 {
+		return
+	}
+	vote, ok := os.voteRecords[newRound-2]
+	if !ok || len(vote.Reports) != len(vote.Symbols) {
+		return
+	}
+
+	for i, s := range vote.Symbols {
+		rd, ok := roundData[s]
+		if !ok || !rd.Success || rd.Price == nil || rd.Price.Sign() == 0 || vote.Reports[i].Price == nil {
+			continue
+		}
+
+		median := decimal.NewFromBigInt(rd.Price, 0)
+		deviation := decimal.NewFromBigInt(vote.Reports[i].Price, 0).Sub(median).Abs().Div(median).Mul(decimal.NewFromInt(100))
+		monitor.UpdateSymbolDeviation(s, deviation.InexactFloat64())
+	}
+}
+
 func (os *Server) handleConfigEvent(ev fsnotify.Event) {
 	// filter unwatched files in the dir.
 	if filepath.Base(ev.Name) != filepath.Base(os.conf.ConfigFile) {
@@ -542,6 +681,9 @@ func (os *Server) handlePenaltyEvent(penalizeEvent *contract.OraclePenalized) er
 		"reported value", penalizeEvent.Reported.String(), "block", penalizeEvent.Raw.BlockNumber, "slashed amount", penalizeEvent.SlashingAmount.Uint64())
 	os.logger.Warn("your next vote will be postponed", "in blocks", os.conf.VoteBuffer)
 	os.logger.Warn("IMPORTANT: please repair your data setups for data precision before getting penalized again")
+	os.notifier.Notify(notifier.OutlierPenalty, nil, "penalized as an outlier", "symbol", penalizeEvent.Symbol,
+		"median", penalizeEvent.Median.String(), "reported", penalizeEvent.Reported.String(),
+		"slashed", penalizeEvent.SlashingAmount.String(), "block", penalizeEvent.Raw.BlockNumber)
 
 	if metrics.Enabled {
 		metrics.GetOrRegisterCounter(monitor.OutlierSlashTimesMetric, nil).Inc(1)
@@ -581,6 +723,9 @@ func (os *Server) sync() error {
 		return err
 	}
 
+	// validate the configured pre-sampling range against the synced vote period.
+	os.checkPreSamplingRange()
+
 	// reset sampling symbols with the latest protocol symbols, it adds bridger symbols by according to the protocol symbols.
 	os.resetSamplingSymbols(os.protocolSymbols)
 
@@ -706,7 +851,32 @@ func (os *Server) syncRoundState() (uint64, uint64, []string, uint64, error) {
 
 func (os *Server) gcStaleSamples() {
 	for _, plugin := range os.runningPlugins {
-		plugin.GCExpiredSamples()
+		plugin.GCExpiredSamples(os.samplingConfig(plugin.DataSourceType()).SampleTTL)
+	}
+}
+
+// samplingConfig returns the sampling config of the data source type.
+func (os *Server) samplingConfig(srcType types.DataSourceType) config.SamplingConfig {
+	if srcType == types.SrcAMM {
+		return os.conf.AMMSampling
+	}
+	return os.conf.CEXSampling
+}
+
+// preSamplingRange resolves the pre-sampling range in blocks, it is capped under the on-chain vote period since a
+// pre-sampling range which covers the whole round would sample data points for the previous round.
+func (os *Server) preSamplingRange() uint64 {
+	if os.votePeriod > 0 && os.conf.PreSamplingRange >= os.votePeriod {
+		return os.votePeriod - 1
+	}
+	return os.conf.PreSamplingRange
+}
+
+// checkPreSamplingRange warns the operator if the configured pre-sampling range does not fit into the vote period.
+func (os *Server) checkPreSamplingRange() {
+	if os.votePeriod > 0 && os.conf.PreSamplingRange >= os.votePeriod {
+		os.logger.Warn("configured pre-sampling range is not shorter than the vote period, it is capped",
+			"preSamplingRange", os.conf.PreSamplingRange, "votePeriod", os.votePeriod, "applied", os.preSamplingRange())
 	}
 }
 
@@ -719,9 +889,13 @@ func (os *Server) gcVoteRecords() {
 			}
 		}
 	}
+	os.gcRoundTraces()
 }
 
 func (os *Server) handleConnectivityError() {
+	if !os.lostSync {
+		os.lostSyncAt = time.Now()
+	}
 	os.lostSync = true
 }
 
@@ -733,6 +907,9 @@ func (os *Server) checkHealth() {
 			if metrics.Enabled {
 				metrics.GetOrRegisterCounter(monitor.L1ConnectivityMetric, nil).Inc(1)
 			}
+			lost := new(big.Int).SetInt64(int64(time.Since(os.lostSyncAt).Seconds()))
+			os.notifier.Notify(notifier.LostSync, lost, "lost the connectivity with the Autonity L1 node",
+				"ws", os.conf.AutonityWSUrl, "error", err.Error())
 			return
 		}
 		os.lostSync = false
@@ -741,7 +918,9 @@ func (os *Server) checkHealth() {
 }
 
 func (os *Server) isVoter() (bool, error) {
+	_, span := monitor.Tracer().Start(os.roundContext(), "isVoter")
 	voters, err := os.oracleContract.GetVoters(nil)
+	monitor.EndSpan(span, err)
 	if err != nil {
 		os.logger.Error("get voters", "error", err.Error())
 		return false, err
@@ -755,13 +934,17 @@ func (os *Server) isVoter() (bool, error) {
 	return false, nil
 }
 
-func (os *Server) printLatestRoundData(newRound uint64) {
+// printLatestRoundData prints the round data of the round closed by the new round and the latest round data, the
+// round data of the closed round is returned by symbol for the metrics.
+func (os *Server) printLatestRoundData(newRound uint64) map[string]contract.IOracleRoundData {
+	roundData := make(map[string]contract.IOracleRoundData, len(os.protocolSymbols))
 	for _, s := range os.protocolSymbols {
 		rd, err := os.oracleContract.GetRoundData(nil, new(big.Int).SetUint64(newRound-1), s)
 		if err != nil {
 			os.logger.Error("get round data", "error", err.Error())
-			return
+			return roundData
 		}
+		roundData[s] = rd
 
 		os.logger.Debug("get round price", "round", newRound-1, "symbol", s, "Price",
 			rd.Price.String(), "success", rd.Success)
@@ -771,7 +954,7 @@ func (os *Server) printLatestRoundData(newRound uint64) {
 		rd, err := os.oracleContract.LatestRoundData(nil, s)
 		if err != nil {
 			os.logger.Error("get latest round price", "error", err.Error())
-			return
+			return roundData
 		}
 
 		price, err := decimal.NewFromString(rd.Price.String())
@@ -782,6 +965,7 @@ func (os *Server) printLatestRoundData(newRound uint64) {
 		os.logger.Debug("latest round price", "round", rd.Round.Uint64(), "symbol", s, "price",
 			price.Div(os.pricePrecision).String(), "success", rd.Success)
 	}
+	return roundData
 }
 
 func (os *Server) samplingFirstRound(ts int64) error {
@@ -796,7 +980,7 @@ func (os *Server) samplingFirstRound(ts int64) error {
 		return nil
 	}
 
-	if nextRoundHeight-curHeight > uint64(config.PreSamplingRange) { //nolint
+	if nextRoundHeight-curHeight > os.preSamplingRange() {
 		return nil
 	}
 
@@ -821,7 +1005,7 @@ func (os *Server) handlePreSampling(preSampleTS int64) error {
 		os.logger.Error("handle pre-sampling", "error", err.Error())
 		return err
 	}
-	if nextRoundHeight-curHeight > uint64(config.PreSamplingRange) { //nolint
+	if nextRoundHeight-curHeight > os.preSamplingRange() {
 		return nil
 	}
 
@@ -833,7 +1017,10 @@ func (os *Server) handlePreSampling(preSampleTS int64) error {
 
 func (os *Server) isBlockchainSynced() bool {
 	// if the autonity node is on peer synchronization state, just skip the reporting.
+	_, span := monitor.Tracer().Start(os.roundContext(), "isBlockchainSynced")
 	syncing, err := os.client.SyncProgress(context.Background())
+	span.SetAttributes(attribute.Bool("syncing", syncing != nil))
+	monitor.EndSpan(span, err)
 	if err != nil {
 		os.logger.Error("vote get SyncProgress", "error", err.Error())
 		return false
@@ -850,7 +1037,10 @@ func (os *Server) isBlockchainSynced() bool {
 func (os *Server) syncProtocolSymbols() error {
 	// get latest symbols from oracle.
 	var err error
+	_, span := monitor.Tracer().Start(os.roundContext(), "syncProtocolSymbols")
 	os.protocolSymbols, err = os.oracleContract.GetSymbols(nil)
+	span.SetAttributes(attribute.StringSlice("symbols", os.protocolSymbols))
+	monitor.EndSpan(span, err)
 	if err != nil {
 		os.logger.Error("vote get symbols", "error", err.Error())
 		return err
@@ -885,7 +1075,13 @@ func (os *Server) unpackLog(out interface{}, event string, log tp.Log) error {
 	return abi.ParseTopics(out, indexed, log.Topics[1:])
 }
 
-func (os *Server) checkOutlierSlashing() bool {
+func (os *Server) checkOutlierSlashing() (slashing bool) {
+	_, span := monitor.Tracer().Start(os.roundContext(), "checkOutlierSlashing")
+	defer func() {
+		span.SetAttributes(attribute.Bool("slashing", slashing))
+		span.End()
+	}()
+
 	// filer log with the topic of penalized event with self address.
 	var participants []interface{}
 	participants = append(participants, os.conf.Key.Address)
@@ -1025,6 +1221,7 @@ func (os *Server) reportWithCommitment(curVoteRecord, lastVote *types.VoteRecord
 	}
 
 	if metrics.Enabled {
+		monitor.GetOrRegisterHistogram(monitor.VoteLatencyMetric).Update(time.Since(os.curRoundTime).Milliseconds())
 		metrics.GetOrRegisterGauge(monitor.BalanceMetric, nil).Update(balance.Int64())
 	}
 
@@ -1032,6 +1229,7 @@ func (os *Server) reportWithCommitment(curVoteRecord, lastVote *types.VoteRecord
 	if balance.Cmp(alertBalance) <= 0 {
 		os.logger.Warn("oracle account has too less balance left for data reporting", "balance", balance.String())
 	}
+	os.notifier.Notify(notifier.LowBalance, balance, "oracle account has too less balance left for data reporting")
 
 	// round data was successfully assembled, save current round data.
 	curVoteRecord.TxHash = tx.Hash()
@@ -1055,6 +1253,9 @@ func (os *Server) reportWithoutCommitment(lastVoteRecord *types.VoteRecord) erro
 		return err
 	}
 	os.logger.Info("reported last round data and without current round commitment", "TX hash", tx.Hash(), "Nonce", tx.Nonce())
+	if metrics.Enabled {
+		monitor.GetOrRegisterHistogram(monitor.VoteLatencyMetric).Update(time.Since(os.curRoundTime).Milliseconds())
+	}
 
 	// save current vote record even though there is no commitment as the voter is leaving the committee.
 	curVoteRecord := &types.VoteRecord{
@@ -1073,49 +1274,24 @@ func (os *Server) reportWithoutCommitment(lastVoteRecord *types.VoteRecord) erro
 	return nil
 }
 
-// resetSamplingSymbols reset the latest sampling symbol set with the protocol symbol set.
+// resetSamplingSymbols reset the latest sampling symbol set with the protocol symbol set, and the symbols required to
+// derive the protocol symbols from their conversion paths.
 func (os *Server) resetSamplingSymbols(protocolSymbols []string) {
-	os.samplingSymbols = protocolSymbols
-
-	// check if we need to add bridger symbols on demand.
-	bridged := false
-	for _, s := range protocolSymbols {
-		if bridger, ok := BridgedSymbols[s]; ok {
-			bridged = true
-			os.samplingSymbols = append(os.samplingSymbols, bridger)
+	symbols := os.router.samplingSymbols(protocolSymbols)
+	// clean up the series of the symbols that are no longer sampled.
+	if metrics.Enabled {
+		for _, s := range os.samplingSymbols {
+			if !slices.Contains(symbols, s) {
+				monitor.RemoveSymbolMetrics(s)
+			}
 		}
 	}
-
-	if bridged {
-		os.samplingSymbols = append(os.samplingSymbols, USDCUSD)
-	}
+	os.samplingSymbols = symbols
 }
 
 // addNewSymbols adds new symbols to the local symbol set for data fetching, duplicated one is not added.
 func (os *Server) addNewSymbols(newSymbols []string) {
-	var symbolsMap = make(map[string]struct{})
-	for _, s := range os.samplingSymbols {
-		symbolsMap[s] = struct{}{}
-	}
-
-	// check if we need to add bridger symbols on demand.
-	bridged := false
-	for _, newS := range newSymbols {
-		if _, ok := symbolsMap[newS]; !ok {
-			os.samplingSymbols = append(os.samplingSymbols, newS)
-			// if the new symbol requires a bridger symbol, add it too.
-			if bridger, ok := BridgedSymbols[newS]; ok {
-				bridged = true
-				if _, ok := symbolsMap[bridger]; !ok {
-					os.samplingSymbols = append(os.samplingSymbols, bridger)
-				}
-			}
-		}
-	}
-
-	if _, ok := symbolsMap[USDCUSD]; !ok && bridged {
-		os.samplingSymbols = append(os.samplingSymbols, USDCUSD)
-	}
+	os.samplingSymbols = os.router.samplingSymbols(append(slices.Clone(os.samplingSymbols), newSymbols...))
 }
 
 func (os *Server) resolveGasTipCap() *big.Int {
@@ -1133,7 +1309,17 @@ func (os *Server) resolveGasTipCap() *big.Int {
 	return configured
 }
 
-func (os *Server) doReport(curRoundCommitmentHash common.Hash, lastVoteRecord *types.VoteRecord) (*tp.Transaction, error) {
+func (os *Server) doReport(curRoundCommitmentHash common.Hash, lastVoteRecord *types.VoteRecord) (tx *tp.Transaction, err error) {
+	_, span := monitor.Tracer().Start(os.roundContext(), "doReport", trace.WithAttributes(
+		attribute.Bool("commitment", curRoundCommitmentHash != common.Hash{}), attribute.Bool("reveal", lastVoteRecord != nil)))
+	defer func() {
+		if err == nil && tx != nil {
+			span.SetAttributes(attribute.String("txn", tx.Hash().Hex()), attribute.Int64("nonce", int64(tx.Nonce()))) //nolint
+			os.keepRoundTrace()
+		}
+		monitor.EndSpan(span, err)
+	}()
+
 	chainID, err := os.client.ChainID(context.Background())
 	if err != nil {
 		os.logger.Error("get chain id", "error", err.Error())
@@ -1175,12 +1361,17 @@ func (os *Server) doReport(curRoundCommitmentHash common.Hash, lastVoteRecord *t
 	return os.oracleContract.Vote(auth, new(big.Int).SetBytes(curRoundCommitmentHash.Bytes()), lastVoteRecord.Reports, lastVoteRecord.Salt, config.Version)
 }
 
-func (os *Server) buildVoteRecord(round uint64) (*types.VoteRecord, error) {
+func (os *Server) buildVoteRecord(round uint64) (_ *types.VoteRecord, err error) {
+	ctx, span := monitor.Tracer().Start(os.roundContext(), "buildVoteRecord", trace.WithAttributes(attribute.Int64("round", int64(round)))) //nolint
+	defer func() {
+		monitor.EndSpan(span, err)
+	}()
+
 	if len(os.protocolSymbols) == 0 {
 		return nil, types.ErrNoSymbolsObserved
 	}
 
-	prices, err := os.aggregateProtocolSymbolPrices()
+	prices, err := os.aggregateProtocolSymbolPrices(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1192,41 +1383,49 @@ func (os *Server) buildVoteRecord(round uint64) (*types.VoteRecord, error) {
 		return nil, err
 	}
 	os.logger.Info("assembled round report data", "current round", round, "prices", voteRecord)
+	if metrics.Enabled {
+		for s, p := range voteRecord.Prices {
+			monitor.UpdateSymbolReport(s, p.Price.InexactFloat64(), p.Confidence)
+		}
+	}
 	return voteRecord, nil
 }
 
-func (os *Server) aggregateProtocolSymbolPrices() (types.PriceBySymbol, error) {
+// aggregateProtocolSymbolPrices aggregates the prices of the protocol symbols, each symbol's aggregation is traced as a
+// child span of the ctx.
+func (os *Server) aggregateProtocolSymbolPrices(ctx context.Context) (types.PriceBySymbol, error) {
 	prices := make(types.PriceBySymbol)
 
-	// if we need a bridger pair USDC-USD to convert ATN-USD or NTN-USD from ATN-USDC or NTN-USDC,
-	// then we need to aggregate USDC-USD data point first.
-	var usdcPrice *types.Price
-	var err error
-	if slices.Contains(os.protocolSymbols, ATNUSD) || slices.Contains(os.protocolSymbols, NTNUSD) {
-		usdcPrice, err = os.aggregatePrice(USDCUSD, os.curSampleTS)
+	// the legs of conversion paths can be shared by multiple symbols, thus aggregate each of them once per round.
+	type aggregated struct {
+		price *types.Price
+		err   error
+	}
+	cache := make(map[string]aggregated)
+	aggregate := func(s string, target int64) (*types.Price, error) {
+		if r, ok := cache[s]; ok {
+			if r.err != nil {
+				return nil, r.err
+			}
+			cp := *r.price
+			return &cp, nil
+		}
+		_, span := monitor.Tracer().Start(ctx, "aggregatePrice", trace.WithAttributes(attribute.String("symbol", s)))
+		p, err := os.aggregatePrice(s, target)
+		if p != nil {
+			span.SetAttributes(attribute.String("price", p.Price.String()), attribute.Int64("confidence", int64(p.Confidence)))
+		}
+		monitor.EndSpan(span, err)
+		cache[s] = aggregated{price: p, err: err}
 		if err != nil {
-			os.logger.Error("aggregate USDC-USD price", "error", err.Error())
+			return nil, err
 		}
+		cp := *p
+		return &cp, nil
 	}
 
 	for _, s := range os.protocolSymbols {
-		// aggregate bridged symbols
-		if s == ATNUSD || s == NTNUSD {
-			if usdcPrice == nil {
-				continue
-			}
-
-			p, e := os.aggBridgedPrice(s, os.curSampleTS, usdcPrice)
-			if e != nil {
-				os.logger.Error("aggregate bridged price", "error", e.Error(), "symbol", s)
-				continue
-			}
-			prices[s] = *p
-			continue
-		}
-
-		// aggregate none bridged symbols
-		p, e := os.aggregatePrice(s, os.curSampleTS)
+		p, e := os.router.resolve(s, os.curSampleTS, aggregate)
 		if e != nil {
 			os.logger.Debug("no data for aggregation", "reason", e.Error(), "symbol", s)
 			continue
@@ -1234,31 +1433,6 @@ func (os *Server) aggregateProtocolSymbolPrices() (types.PriceBySymbol, error) {
 		prices[s] = *p
 	}
 
-	// edge case: if NTN-ATN price was not computable from inside plugin,
-	// try to compute it from NTNprice and ATNprice across from different plugins.
-	if _, ok := prices[common2.NTNATNSymbol]; !ok {
-		ntnPrice, ntnExist := prices[NTNUSD]
-		atnPrice, atnExist := prices[ATNUSD]
-		if ntnExist && atnExist {
-			ntnATNPrice, err := common2.ComputeDerivedPrice(ntnPrice.Price.String(), atnPrice.Price.String()) //nolint
-			if err == nil {
-				p, err := decimal.NewFromString(ntnATNPrice.Price) // nolint
-				if err == nil {
-					prices[common2.NTNATNSymbol] = types.Price{
-						Timestamp:  time.Now().Unix(),
-						Price:      p,
-						Symbol:     common2.NTNATNSymbol,
-						Confidence: ntnPrice.Confidence,
-					}
-				} else {
-					os.logger.Error("cannot parse NTN-ATN price in decimal", "error", err.Error())
-				}
-			} else {
-				os.logger.Error("failed to compute NTN-ATN price", "error", err.Error())
-			}
-		}
-	}
-
 	return prices, nil
 }
 
@@ -1321,46 +1495,51 @@ func (os *Server) handleNewSymbolsEvent(symbols []string) {
 	os.addNewSymbols(symbols)
 }
 
-// aggBridgedPrice aggregates ATN-USD or NTN-USD from bridged ATN-USDC or NTN-USDC with USDC-USD price,
-// it assumes the input usdcPrice is not nil.
-func (os *Server) aggBridgedPrice(srcSymbol string, target int64, usdcPrice *types.Price) (*types.Price, error) {
-	var bridgedSymbol string
-	if srcSymbol == ATNUSD {
-		bridgedSymbol = ATNUSDC
+// maxSampleAge returns the max age of the plugin's data points, the plugin's config overrides the server's one.
+func (os *Server) maxSampleAge(plugin *pWrapper.PluginWrapper) int {
+	if conf := plugin.Config(); conf != nil && conf.MaxSampleAge != 0 {
+		return conf.MaxSampleAge
 	}
-
-	if srcSymbol == NTNUSD {
-		bridgedSymbol = NTNUSDC
-	}
-
-	p, err := os.aggregatePrice(bridgedSymbol, target)
-	if err != nil {
-		os.logger.Error("aggregate bridged price", "error", err.Error(), "symbol", bridgedSymbol)
-		return nil, err
-	}
-
-	// reset the symbol with source symbol,
-	// and update price with: ATN-USD=ATN-USDC*USDC-USD / NTN-USD=NTN-USDC*USDC-USD
-	// the confidence of ATN-USD and NTN-USD are inherit from ATN-USDC and NTN-USDC.
-	p.Symbol = srcSymbol
-	p.Price = p.Price.Mul(usdcPrice.Price)
-	return p, nil
+	return os.conf.MaxSampleAge
 }
 
 // aggregatePrice takes the symbol's aggregated data points from all the supported plugins, if there are multiple
-// markets' datapoint, it will do a final VWAP aggregation to form the final reporting value.
+// markets' datapoint, it will do a final VWAP aggregation to form the final reporting value. The data points of the
+// anchors do not join the aggregation, thus a stale anchor cannot move it, they only check its deviation.
 func (os *Server) aggregatePrice(s string, target int64) (*types.Price, error) {
-	var prices []decimal.Decimal
-	var volumes []*big.Int
+	var prices, anchors []decimal.Decimal
+	var volumes, anchorVolumes []*big.Int
 	for _, plugin := range os.runningPlugins {
-		p, err := plugin.AggregatedPrice(s, target)
+		p, err := plugin.AggregatedPrice(s, target, os.samplingConfig(plugin.DataSourceType()))
 		if err != nil {
 			continue
 		}
+
+		// reject the data point that was quoted by the data source too long before the round's sampling TS.
+		if maxAge := os.maxSampleAge(plugin); maxAge > 0 && target-p.Timestamp > int64(maxAge) {
+			os.logger.Debug("skip stale data point", "plugin", plugin.Name(), "symbol", s, "quoteTS", p.Timestamp,
+				"target", target)
+			continue
+		}
+
+		if plugin.DataSourceType() == types.SrcAnchor {
+			anchors = append(anchors, p.Price)
+			anchorVolumes = append(anchorVolumes, p.Volume)
+			continue
+		}
 		prices = append(prices, p.Price)
 		volumes = append(volumes, p.Volume)
 	}
 
+	// the anchors price the symbol only if no other data source does, rather than the price of the historic rounds.
+	if len(prices) == 0 {
+		prices, volumes, anchors = anchors, anchorVolumes, nil
+	}
+
+	if metrics.Enabled {
+		monitor.UpdateSymbolSamples(s, len(prices))
+	}
+
 	if len(prices) == 0 {
 		copyHistoricPrice, err := os.queryHistoricRoundPrice(s)
 		if err != nil {
@@ -1390,6 +1569,7 @@ func (os *Server) aggregatePrice(s string, target int64) (*types.Price, error) {
 		}
 		price.Price = p
 		price.Volume = types.DefaultVolume
+		os.checkAnchors(s, price.Price, anchors)
 		return price, nil
 	}
 
@@ -1403,9 +1583,33 @@ func (os *Server) aggregatePrice(s string, target int64) (*types.Price, error) {
 		price.Volume = vol
 	}
 
+	os.checkAnchors(s, price.Price, anchors)
 	return price, nil
 }
 
+// checkAnchors warns if the aggregated price of the symbol deviates from the data points of the anchors over the
+// anchor deviation in basis points, a zero anchor deviation disables the check.
+func (os *Server) checkAnchors(s string, price decimal.Decimal, anchors []decimal.Decimal) bool {
+	if os.conf.AnchorDeviation <= 0 {
+		return false
+	}
+
+	deviated := false
+	threshold := decimal.NewFromInt(int64(os.conf.AnchorDeviation))
+	for _, anchor := range anchors {
+		if anchor.IsZero() {
+			continue
+		}
+		deviation := price.Sub(anchor).Abs().Div(anchor).Mul(decimal.NewFromInt(10000))
+		if deviation.GreaterThan(threshold) {
+			deviated = true
+			os.logger.Warn("aggregated price deviates from the anchor", "symbol", s, "price", price.String(),
+				"anchor", anchor.String(), "deviation bps", deviation.StringFixed(0))
+		}
+	}
+	return deviated
+}
+
 // queryHistoricRoundPrice queries the last available price for a given symbol from the historic rounds.
 func (os *Server) queryHistoricRoundPrice(symbol string) (types.Price, error) {
 
@@ -1446,6 +1650,7 @@ func (os *Server) samplePrice(symbols []string, ts int64) {
 	e := &types.SampleEvent{
 		Symbols: cpSymbols,
 		TS:      ts,
+		Round:   trace.SpanContextFromContext(os.roundContext()),
 	}
 	nListener := os.sampleEventFeed.Send(e)
 	os.logger.Debug("sample event is sent to", "num of plugins", nListener)
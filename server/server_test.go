@@ -5,8 +5,11 @@ import (
 	contract "autonity-oracle/contract_binder/contract"
 	cMock "autonity-oracle/contract_binder/contract/mock"
 	"autonity-oracle/helpers"
+	"autonity-oracle/monitor"
+	pWrapper "autonity-oracle/plugin_wrapper"
 	"autonity-oracle/types"
 	"autonity-oracle/types/mock"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -14,14 +17,21 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	tp "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/golang/mock/gomock"
 	"github.com/hashicorp/go-hclog"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
-var BridgerSymbols = []string{NTNUSDC, ATNUSDC, USDCUSD}
-var DefaultSampledSymbols = []string{"AUD-USD", "CAD-USD", "EUR-USD", "GBP-USD", "JPY-USD", "SEK-USD", "ATN-USD", "NTN-USD", "NTN-ATN", "ATN-USDC", "NTN-USDC", "USDC-USD"}
+var BridgerSymbols = []string{NTNUSDC, ATNUSDC, USDCUSD, USDCUSDT, USDTUSD}
+var DefaultSampledSymbols = []string{"AUD-USD", "CAD-USD", "EUR-USD", "GBP-USD", "JPY-USD", "SEK-USD", "ATN-USD", "NTN-USD", "NTN-ATN", "ATN-USDC", "USDC-USD", "USDC-USDT",
+	"USDT-USD", "NTN-USDC"}
 var ChainIDPiccadilly = big.NewInt(65_100_004)
 var testKeyFile = "../test_data/keystore/UTC--2023-02-27T09-10-19.592765887Z--b749d3d83376276ab4ddef2d9300fb5ce70ebafe"
 
@@ -50,17 +60,23 @@ func TestOracleServer(t *testing.T) {
 	require.NoError(t, err)
 
 	conf := &config.Config{
-		ConfigFile:         "../test_data/oracle_config.yml",
-		LoggingLevel:       hclog.Level(config.DefaultConfig.LoggingLevel), //nolint
-		GasTipCap:          config.DefaultConfig.GasTipCap,
-		VoteBuffer:         config.DefaultConfig.VoteBuffer,
-		Key:                key,
-		AutonityWSUrl:      config.DefaultConfig.AutonityWSUrl,
-		PluginDIR:          "../plugins/template_plugin/bin",
-		ProfileDir:         ".",
-		ConfidenceStrategy: 0,
-		PluginConfigs:      nil,
-		MetricConfigs:      config.MetricConfig{},
+		ConfigFile:          "../test_data/oracle_config.yml",
+		LoggingLevel:        hclog.Level(config.DefaultConfig.LoggingLevel), //nolint
+		GasTipCap:           config.DefaultConfig.GasTipCap,
+		VoteBuffer:          config.DefaultConfig.VoteBuffer,
+		Key:                 key,
+		AutonityWSUrl:       config.DefaultConfig.AutonityWSUrl,
+		PluginDIR:           "../plugins/template_plugin/bin",
+		ProfileDir:          ".",
+		ConfidenceStrategy:  0,
+		PreSamplingRange:    config.DefaultConfig.PreSamplingRange,
+		SamplingInterval:    time.Duration(config.DefaultConfig.SamplingInterval) * time.Second,
+		HealthCheckInterval: time.Duration(config.DefaultConfig.HealthCheckInterval) * time.Second,
+		AMMSampling:         config.DefaultAMMSamplingConfig,
+		CEXSampling:         config.DefaultSamplingConfig,
+		SymbolRoutes:        config.DefaultSymbolRoutes,
+		PluginConfigs:       nil,
+		MetricConfigs:       config.MetricConfig{},
 	}
 
 	t.Run("test init oracle server with oracle contract states", func(t *testing.T) {
@@ -94,6 +110,37 @@ func TestOracleServer(t *testing.T) {
 		srv.runningPlugins["template_plugin"].Close()
 	})
 
+	t.Run("test init oracle server with zero intervals", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dialerMock := mock.NewMockDialer(ctrl)
+		contractMock := cMock.NewMockContractAPI(ctrl)
+		contractMock.EXPECT().GetRound(nil).Return(currentRound, nil)
+		contractMock.EXPECT().GetLastRoundBlock(nil).Return(currentRoundHeight, nil)
+		contractMock.EXPECT().GetSymbols(nil).Return(helpers.DefaultSymbols, nil)
+		contractMock.EXPECT().GetVotePeriod(nil).Return(votePeriod, nil)
+		contractMock.EXPECT().WatchNewRound(gomock.Any(), gomock.Any()).Return(subRoundEvent, nil)
+		contractMock.EXPECT().WatchNewSymbols(gomock.Any(), gomock.Any()).Return(subSymbolsEvent, nil)
+		contractMock.EXPECT().WatchPenalized(gomock.Any(), gomock.Any(), gomock.Any()).Return(subPenalizeEvent, nil)
+		contractMock.EXPECT().WatchSuccessfulVote(gomock.Any(), gomock.Any(), gomock.Any()).Return(subVoteEvent, nil)
+		contractMock.EXPECT().WatchInvalidVote(gomock.Any(), gomock.Any(), gomock.Any()).Return(subInvalidVoteEvent, nil)
+		contractMock.EXPECT().WatchTotalOracleRewards(gomock.Any(), gomock.Any()).Return(subReportedEvent, nil)
+		contractMock.EXPECT().WatchNoRevealPenalty(gomock.Any(), gomock.Any(), gomock.Any()).Return(subNoRevealEvent, nil)
+
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		l1Mock.EXPECT().ChainID(gomock.Any()).Return(ChainIDPiccadilly, nil)
+		zeroConf := *conf
+		zeroConf.SamplingInterval = 0
+		zeroConf.HealthCheckInterval = 0
+		srv := NewServer(&zeroConf, dialerMock, l1Mock, contractMock)
+		require.NotNil(t, srv.regularTicker)
+		require.NotNil(t, srv.psTicker)
+		srv.regularTicker.Stop()
+		srv.psTicker.Stop()
+		srv.runningPlugins["template_plugin"].Close()
+	})
+
 	t.Run("test pre-sampling happy case", func(t *testing.T) {
 		roundID := currentRound.Uint64() + 1
 		ctrl := gomock.NewController(t)
@@ -281,3 +328,230 @@ func TestOracleServer(t *testing.T) {
 
 	})
 }
+
+func TestAggregatePriceWithStaleSamples(t *testing.T) {
+	target := time.Now().Unix()
+	fresh := pWrapper.NewPluginWrapper(hclog.Info, "fresh_plugin", ".", nil, &config.PluginConfig{})
+	fresh.AddSample([]types.Price{{Timestamp: target - 1, Symbol: "EUR-USD", Price: decimal.RequireFromString("1.08")}}, target)
+	stale := pWrapper.NewPluginWrapper(hclog.Info, "stale_plugin", ".", nil, &config.PluginConfig{})
+	stale.AddSample([]types.Price{{Timestamp: target - 300, Symbol: "EUR-USD", Price: decimal.RequireFromString("1.02")}}, target)
+
+	srv := &Server{
+		logger:         hclog.NewNullLogger(),
+		conf:           &config.Config{MaxSampleAge: 60},
+		voteRecords:    make(map[uint64]*types.VoteRecord),
+		runningPlugins: map[string]*pWrapper.PluginWrapper{"fresh_plugin": fresh, "stale_plugin": stale},
+	}
+
+	// the stale data point is rejected, only the fresh one is aggregated.
+	price, err := srv.aggregatePrice("EUR-USD", target)
+	require.NoError(t, err)
+	require.Equal(t, "1.08", price.Price.String())
+
+	// with no limit, both data points are aggregated with median.
+	srv.conf.MaxSampleAge = 0
+	price, err = srv.aggregatePrice("EUR-USD", target)
+	require.NoError(t, err)
+	require.Equal(t, "1.05", price.Price.String())
+
+	// if all the data points are stale, there is no price.
+	srv.conf.MaxSampleAge = 60
+	delete(srv.runningPlugins, "fresh_plugin")
+	_, err = srv.aggregatePrice("EUR-USD", target)
+	require.ErrorIs(t, err, types.ErrNoDataRound)
+
+	// the plugin's own limit overrides the server's one, e.g. for the low-frequency anchors.
+	stale.Config().MaxSampleAge = 3 * 24 * 3600
+	price, err = srv.aggregatePrice("EUR-USD", target)
+	require.NoError(t, err)
+	require.Equal(t, "1.02", price.Price.String())
+
+	stale.Config().MaxSampleAge = -1
+	srv.conf.MaxSampleAge = 0
+	_, err = srv.aggregatePrice("EUR-USD", target)
+	require.NoError(t, err)
+}
+
+func TestAggregatePriceWithAnchors(t *testing.T) {
+	target := time.Now().Unix()
+	newPlugin := func(name string, srcType types.DataSourceType, ts int64, price string,
+		conf *config.PluginConfig) *pWrapper.PluginWrapper {
+		plugin := pWrapper.NewSampleWrapper(name, srcType, conf)
+		plugin.AddSample([]types.Price{{Timestamp: ts, Symbol: "EUR-USD", Price: decimal.RequireFromString(price)}}, target)
+		return plugin
+	}
+
+	// the anchor, whatever its binary is named, quoted the rate of days ago, it is still taken as it overrides the
+	// server's max sample age.
+	anchor := newPlugin("renamed_ecb", types.SrcAnchor, target-3*24*3600, "1.50",
+		&config.PluginConfig{MaxSampleAge: 4 * 24 * 3600})
+	srv := &Server{
+		logger:      hclog.NewNullLogger(),
+		conf:        &config.Config{MaxSampleAge: 60, AnchorDeviation: 500},
+		voteRecords: make(map[uint64]*types.VoteRecord),
+		runningPlugins: map[string]*pWrapper.PluginWrapper{
+			"fresh_plugin": newPlugin("fresh_plugin", types.SrcCEX, target-1, "1.08", &config.PluginConfig{}),
+			"renamed_ecb":  anchor,
+		},
+	}
+
+	// the stale anchor cannot move the price of a single data source.
+	price, err := srv.aggregatePrice("EUR-USD", target)
+	require.NoError(t, err)
+	require.Equal(t, "1.08", price.Price.String())
+
+	// nor the median of the data sources, and it does not count for the confidence.
+	srv.runningPlugins["other_plugin"] = newPlugin("other_plugin", types.SrcCEX, target-1, "1.10", &config.PluginConfig{})
+	price, err = srv.aggregatePrice("EUR-USD", target)
+	require.NoError(t, err)
+	require.Equal(t, "1.09", price.Price.String())
+	require.Equal(t, computeConfidence("EUR-USD", 2, srv.conf.ConfidenceStrategy), price.Confidence)
+
+	// the deviation from the anchors is checked with the configured threshold in basis points.
+	anchors := []decimal.Decimal{decimal.RequireFromString("1.50")}
+	require.True(t, srv.checkAnchors("EUR-USD", price.Price, anchors))
+	srv.conf.AnchorDeviation = 4000
+	require.False(t, srv.checkAnchors("EUR-USD", price.Price, anchors))
+	srv.conf.AnchorDeviation = 0
+	require.False(t, srv.checkAnchors("EUR-USD", price.Price, anchors))
+
+	// the anchors price the symbol if no other data source does.
+	delete(srv.runningPlugins, "fresh_plugin")
+	delete(srv.runningPlugins, "other_plugin")
+	srv.runningPlugins["forex_boc"] = newPlugin("forex_boc", types.SrcAnchor, target-1, "1.10", &config.PluginConfig{})
+	price, err = srv.aggregatePrice("EUR-USD", target)
+	require.NoError(t, err)
+	require.Equal(t, "1.3", price.Price.String())
+	require.Equal(t, computeConfidence("EUR-USD", 2, srv.conf.ConfidenceStrategy), price.Confidence)
+}
+
+func TestPreSamplingRange(t *testing.T) {
+	srv := &Server{
+		logger: hclog.NewNullLogger(),
+		conf:   &config.Config{PreSamplingRange: 6},
+	}
+
+	// vote period is not synced yet.
+	require.Equal(t, uint64(6), srv.preSamplingRange())
+
+	srv.votePeriod = 30
+	require.Equal(t, uint64(6), srv.preSamplingRange())
+
+	// the range is capped under the vote period.
+	srv.votePeriod = 5
+	require.Equal(t, uint64(4), srv.preSamplingRange())
+	srv.votePeriod = 6
+	require.Equal(t, uint64(5), srv.preSamplingRange())
+}
+
+func TestVoteMetrics(t *testing.T) {
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = false }()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the reports committed in round 8 are revealed in round 9, and aggregated in the median of round 9.
+	median := &contract.IOracleRoundData{Price: big.NewInt(1000), Success: true}
+	contractMock := cMock.NewMockContractAPI(ctrl)
+	contractMock.EXPECT().GetRoundData(nil, big.NewInt(9), "NTN-USD").Return(*median, nil)
+	contractMock.EXPECT().GetRoundData(nil, big.NewInt(9), "ATN-USD").Return(contract.IOracleRoundData{}, nil)
+	contractMock.EXPECT().LatestRoundData(nil, "NTN-USD").Return(contract.IOracleRoundData{}, errors.New("unavailable"))
+
+	tx := tp.NewTx(&tp.DynamicFeeTx{GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(3)})
+	l1Mock := mock.NewMockBlockchain(ctrl)
+	l1Mock.EXPECT().TransactionByHash(gomock.Any(), tx.Hash()).Return(tx, false, nil)
+	l1Mock.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(103)).Return(&tp.Header{BaseFee: big.NewInt(1)}, nil)
+
+	vote := &types.VoteRecord{
+		RoundID:     8,
+		RoundHeight: 100,
+		Symbols:     []string{"NTN-USD", "ATN-USD"},
+		Reports: []contract.IOracleReport{
+			{Price: big.NewInt(1020)},
+			{Price: big.NewInt(500)},
+		},
+	}
+	srv := &Server{
+		logger:          hclog.NewNullLogger(),
+		client:          l1Mock,
+		oracleContract:  contractMock,
+		voteRecords:     map[uint64]*types.VoteRecord{8: vote},
+		protocolSymbols: []string{"NTN-USD", "ATN-USD"},
+	}
+
+	// the medians are the ones fetched for the round data printing, no more L1 calls.
+	srv.updateDeviationMetrics(10, srv.printLatestRoundData(10))
+	deviation := metrics.GetOrRegisterGaugeFloat64(monitor.SymbolDeviationPercentMetric("NTN-USD"), nil)
+	require.InDelta(t, 2.0, deviation.Value(), 1e-9)
+
+	// the mined vote is measured off the event loop.
+	vote.TxHash = tx.Hash()
+	l1Mock.EXPECT().TransactionReceipt(gomock.Any(), tx.Hash()).Return(&tp.Receipt{TxHash: tx.Hash(), GasUsed: 100,
+		BlockNumber: big.NewInt(103)}, nil)
+	srv.observeMinedVote(8, vote, nil)
+	require.Eventually(t, func() bool {
+		return metrics.GetOrRegisterGaugeFloat64(monitor.VoteFeeMetric, nil).Value() == float64(200)
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(3), monitor.GetOrRegisterHistogram(monitor.VoteMinedBlocksMetric).Max())
+}
+
+func TestRoundTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key, err := config.LoadKey(testKeyFile, config.DefaultConfig.KeyPassword)
+	require.NoError(t, err)
+
+	contractMock := cMock.NewMockContractAPI(ctrl)
+	contractMock.EXPECT().GetVoters(nil).Return([]common.Address{key.Address}, nil)
+	l1Mock := mock.NewMockBlockchain(ctrl)
+	l1Mock.EXPECT().SyncProgress(gomock.Any()).Return(nil, nil)
+
+	srv := &Server{
+		logger:         hclog.NewNullLogger(),
+		conf:           &config.Config{Key: key},
+		client:         l1Mock,
+		oracleContract: contractMock,
+		curRound:       10,
+		voteRecords:    make(map[uint64]*types.VoteRecord),
+	}
+
+	span := srv.startRoundSpan(big.NewInt(10), 100, 1000, 30)
+	require.True(t, srv.isBlockchainSynced())
+	isVoter, err := srv.isVoter()
+	require.NoError(t, err)
+	require.True(t, isVoter)
+	_, err = srv.buildVoteRecord(10)
+	require.ErrorIs(t, err, types.ErrNoSymbolsObserved)
+
+	// the plugins' fetching are linked to the round by the sampling event.
+	ch := make(chan *types.SampleEvent, 1)
+	sub := srv.WatchSampleEvent(ch)
+	defer sub.Unsubscribe()
+	srv.samplePrice([]string{"NTN-USD"}, 1001)
+	require.Equal(t, span.SpanContext(), (<-ch).Round)
+
+	// the receipt of the round's vote is traced into the round after the round's handling.
+	srv.keepRoundTrace()
+	span.End()
+	vote := &types.VoteRecord{RoundID: 10, TxHash: common.HexToHash("0x01")}
+	srv.popRoundTrace(10).traceVoteReceipt(vote, &tp.Receipt{Status: 1, BlockNumber: big.NewInt(102)})
+	require.Empty(t, srv.roundTraces)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	require.Len(t, spans, 5)
+	root := spans["NewRound"]
+	for _, name := range []string{"isBlockchainSynced", "isVoter", "buildVoteRecord", "VoteReceipt"} {
+		require.Equal(t, root.SpanContext().SpanID(), spans[name].Parent().SpanID(), name)
+		require.Equal(t, root.SpanContext().TraceID(), spans[name].SpanContext().TraceID(), name)
+	}
+	require.Equal(t, codes.Error, spans["buildVoteRecord"].Status().Code)
+}
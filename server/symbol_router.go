@@ -0,0 +1,271 @@
+package server
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/helpers"
+	common2 "autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// leg is a hop of a conversion path, its price is the symbol's price or the reciprocal of it.
+type leg struct {
+	symbol  string
+	inverse bool
+}
+
+// aggregator resolves the aggregated price of a symbol at the target TS.
+type aggregator func(symbol string, target int64) (*types.Price, error)
+
+// symbolRouter derives the price of symbols from the conversion paths declared in the config.
+type symbolRouter struct {
+	routes map[string][][]leg // the conversion paths of derived symbols in the order of preference.
+	merged map[string]bool    // the derived symbols whose priced paths are aggregated together.
+}
+
+func newSymbolRouter(routes []config.SymbolRoute) (*symbolRouter, error) {
+	r := &symbolRouter{routes: make(map[string][][]leg), merged: make(map[string]bool)}
+	for _, route := range routes {
+		if _, ok := r.routes[route.Symbol]; ok {
+			return nil, fmt.Errorf("duplicated route of symbol %s", route.Symbol)
+		}
+
+		base, quote, err := splitSymbol(route.Symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(route.Paths) == 0 {
+			return nil, fmt.Errorf("no conversion path of symbol %s", route.Symbol)
+		}
+
+		for _, p := range route.Paths {
+			legs, err := resolveLegs(base, quote, p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %v of symbol %s: %w", p, route.Symbol, err)
+			}
+			r.routes[route.Symbol] = append(r.routes[route.Symbol], legs)
+		}
+		r.merged[route.Symbol] = route.Merge
+	}
+
+	for s := range r.routes {
+		if err := r.checkCycle(s, make(map[string]bool)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// resolveLegs chains the path's symbols from the base to the quote currency, and resolves the direction of each leg.
+func resolveLegs(base, quote string, path []string) ([]leg, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	legs := make([]leg, 0, len(path))
+	cur := base
+	for _, s := range path {
+		b, q, err := splitSymbol(s)
+		if err != nil {
+			return nil, err
+		}
+
+		switch cur {
+		case b:
+			legs = append(legs, leg{symbol: s})
+			cur = q
+		case q:
+			legs = append(legs, leg{symbol: s, inverse: true})
+			cur = b
+		default:
+			return nil, fmt.Errorf("symbol %s does not chain with currency %s", s, cur)
+		}
+	}
+
+	if cur != quote {
+		return nil, fmt.Errorf("path ends with currency %s rather than %s", cur, quote)
+	}
+	return legs, nil
+}
+
+func splitSymbol(symbol string) (string, string, error) {
+	parts := strings.Split(symbol, "-")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || parts[0] == parts[1] {
+		return "", "", fmt.Errorf("invalid symbol %s", symbol)
+	}
+	return parts[0], parts[1], nil
+}
+
+// checkCycle checks that the symbol is not derived from itself through the routes of its legs.
+func (r *symbolRouter) checkCycle(symbol string, visiting map[string]bool) error {
+	if visiting[symbol] {
+		return fmt.Errorf("cyclic conversion path of symbol %s", symbol)
+	}
+
+	visiting[symbol] = true
+	for _, p := range r.routes[symbol] {
+		for _, l := range p {
+			if _, ok := r.routes[l.symbol]; !ok || l.symbol == symbol {
+				continue
+			}
+			if err := r.checkCycle(l.symbol, visiting); err != nil {
+				return err
+			}
+		}
+	}
+	visiting[symbol] = false
+	return nil
+}
+
+// samplingSymbols returns the input symbols with the legs of their conversion paths, duplicated one is not added.
+func (r *symbolRouter) samplingSymbols(symbols []string) []string {
+	var result []string
+	seen := make(map[string]struct{})
+	var add func(s string)
+	add = func(s string) {
+		if _, ok := seen[s]; ok {
+			return
+		}
+		seen[s] = struct{}{}
+		result = append(result, s)
+	}
+
+	var expand func(s string)
+	expand = func(s string) {
+		for _, p := range r.routes[s] {
+			for _, l := range p {
+				_, visited := seen[l.symbol]
+				add(l.symbol)
+				if !visited && l.symbol != s {
+					expand(l.symbol)
+				}
+			}
+		}
+	}
+
+	for _, s := range symbols {
+		add(s)
+	}
+	for _, s := range symbols {
+		expand(s)
+	}
+	return result
+}
+
+// resolve derives the price of the symbol from the first conversion path with all of its legs priced, the confidence
+// of the derived price is the lowest confidence along the path, and the volume is inherited from the first leg. The
+// prices of all the priced paths of a merged route are aggregated together rather than taking the first one.
+func (r *symbolRouter) resolve(symbol string, target int64, aggregate aggregator) (*types.Price, error) {
+	paths, ok := r.routes[symbol]
+	if !ok {
+		return aggregate(symbol, target)
+	}
+
+	var lastErr error
+	var prices []*types.Price
+	for _, p := range paths {
+		price, err := r.resolvePath(symbol, p, target, aggregate)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if !r.merged[symbol] {
+			return price, nil
+		}
+		prices = append(prices, price)
+	}
+
+	if len(prices) == 0 {
+		return nil, lastErr
+	}
+	return mergePrices(symbol, prices)
+}
+
+// mergePrices aggregates the prices of the paths of a symbol as its samples, the median for the forex symbols and the
+// VWAP for the others as the oracle server aggregates the plugins' samples, and it takes the highest confidence.
+func mergePrices(symbol string, prices []*types.Price) (*types.Price, error) {
+	if len(prices) == 1 {
+		return prices[0], nil
+	}
+
+	result := *prices[0]
+	values := make([]decimal.Decimal, 0, len(prices))
+	volumes := make([]*big.Int, 0, len(prices))
+	for _, p := range prices {
+		values = append(values, p.Price)
+		volume := types.DefaultVolume
+		if p.Volume != nil {
+			volume = p.Volume
+		}
+		volumes = append(volumes, volume)
+		if p.Confidence > result.Confidence {
+			result.Confidence = p.Confidence
+		}
+	}
+
+	if _, isForex := common2.ForexCurrencies[symbol]; isForex {
+		median, err := helpers.Median(values)
+		if err != nil {
+			return nil, err
+		}
+		result.Price = median
+		result.Volume = types.DefaultVolume
+		return &result, nil
+	}
+
+	vwap, volume, err := helpers.VWAP(values, volumes)
+	if err != nil {
+		return nil, err
+	}
+	result.Price = vwap
+	result.Volume = volume
+	return &result, nil
+}
+
+func (r *symbolRouter) resolvePath(symbol string, path []leg, target int64, aggregate aggregator) (*types.Price, error) {
+	var result *types.Price
+	for _, l := range path {
+		var p *types.Price
+		var err error
+		if l.symbol == symbol {
+			p, err = aggregate(l.symbol, target)
+		} else {
+			p, err = r.resolve(l.symbol, target, aggregate)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		legPrice := p.Price
+		if l.inverse {
+			if legPrice.IsZero() {
+				return nil, fmt.Errorf("zero price of symbol %s to be inverted", l.symbol)
+			}
+			legPrice = decimal.NewFromInt(1).Div(legPrice)
+		}
+
+		if result == nil {
+			result = &types.Price{
+				Timestamp:  target,
+				Symbol:     symbol,
+				Price:      legPrice,
+				Confidence: p.Confidence,
+			}
+			if p.Volume != nil {
+				result.Volume = new(big.Int).Set(p.Volume)
+			}
+			continue
+		}
+
+		result.Price = result.Price.Mul(legPrice)
+		if p.Confidence < result.Confidence {
+			result.Confidence = p.Confidence
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"autonity-oracle/config"
+	pWrapper "autonity-oracle/plugin_wrapper"
+	"autonity-oracle/types"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSymbolRouter(t *testing.T) {
+	t.Run("default routes", func(t *testing.T) {
+		r, err := newSymbolRouter(config.DefaultSymbolRoutes)
+		require.NoError(t, err)
+		require.Equal(t, []leg{{symbol: NTNUSD}, {symbol: ATNUSD, inverse: true}}, r.routes["NTN-ATN"][1])
+	})
+
+	t.Run("invalid routes", func(t *testing.T) {
+		invalids := [][]config.SymbolRoute{
+			{{Symbol: "ATNUSD", Paths: [][]string{{ATNUSDC, USDCUSD}}}},
+			{{Symbol: ATNUSD}},
+			{{Symbol: ATNUSD, Paths: [][]string{{}}}},
+			{{Symbol: ATNUSD, Paths: [][]string{{ATNUSDC, "EUR-USD"}}}},
+			{{Symbol: ATNUSD, Paths: [][]string{{ATNUSDC}}}},
+			{{Symbol: ATNUSD, Paths: [][]string{{ATNUSDC, USDCUSD}}}, {Symbol: ATNUSD, Paths: [][]string{{ATNUSD}}}},
+			{{Symbol: "A-B", Paths: [][]string{{"A-C", "C-B"}}}, {Symbol: "C-B", Paths: [][]string{{"C-A", "A-B"}}}},
+		}
+		for _, routes := range invalids {
+			_, err := newSymbolRouter(routes)
+			require.Error(t, err, routes)
+		}
+	})
+}
+
+func TestSymbolRouterSamplingSymbols(t *testing.T) {
+	r, err := newSymbolRouter([]config.SymbolRoute{
+		{Symbol: "XAU-USD", Paths: [][]string{{"XAU-EUR", "EUR-USD"}}},
+		{Symbol: ATNUSD, Paths: [][]string{{ATNUSDC, USDCUSD}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"EUR-USD", "XAU-USD", ATNUSD, "XAU-EUR", ATNUSDC, USDCUSD},
+		r.samplingSymbols([]string{"EUR-USD", "XAU-USD", ATNUSD}))
+}
+
+func TestSymbolRouterResolve(t *testing.T) {
+	target := time.Now().Unix()
+	prices := map[string]types.Price{
+		ATNUSDC:   {Price: decimal.RequireFromString("2"), Confidence: 100, Volume: big.NewInt(10)},
+		NTNUSDC:   {Price: decimal.RequireFromString("10"), Confidence: 80, Volume: big.NewInt(20)},
+		USDCUSD:   {Price: decimal.RequireFromString("0.5"), Confidence: 60, Volume: big.NewInt(30)},
+		"EUR-USD": {Price: decimal.RequireFromString("1.25"), Confidence: 100},
+		"XAU-EUR": {Price: decimal.RequireFromString("2000"), Confidence: 70},
+	}
+	aggregate := func(s string, _ int64) (*types.Price, error) {
+		p, ok := prices[s]
+		if !ok {
+			return nil, types.ErrNoDataRound
+		}
+		p.Symbol = s
+		p.Timestamp = target
+		return &p, nil
+	}
+
+	r, err := newSymbolRouter(append(config.DefaultSymbolRoutes, config.SymbolRoute{
+		Symbol: "XAU-USD", Paths: [][]string{{"XAU-USD"}, {"XAU-EUR", "EUR-USD"}},
+	}))
+	require.NoError(t, err)
+
+	// the confidence of a derived price is the lowest one along the path, the volume is inherited from the first leg.
+	p, err := r.resolve(ATNUSD, target, aggregate)
+	require.NoError(t, err)
+	require.Equal(t, ATNUSD, p.Symbol)
+	require.Equal(t, "1", p.Price.String())
+	require.Equal(t, uint8(60), p.Confidence)
+	require.Equal(t, big.NewInt(10), p.Volume)
+
+	// without the direct quotes, NTN-ATN is derived from the derived NTN-USD and ATN-USD.
+	p, err = r.resolve("NTN-ATN", target, aggregate)
+	require.NoError(t, err)
+	require.Equal(t, "5", p.Price.String())
+	require.Equal(t, uint8(60), p.Confidence)
+
+	// the direct quotes are preferred once they are available.
+	prices["NTN-ATN"] = types.Price{Price: decimal.RequireFromString("4.9"), Confidence: 100}
+	p, err = r.resolve("NTN-ATN", target, aggregate)
+	require.NoError(t, err)
+	require.Equal(t, "4.9", p.Price.String())
+
+	p, err = r.resolve("XAU-USD", target, aggregate)
+	require.NoError(t, err)
+	require.Equal(t, "2500", p.Price.String())
+	require.Equal(t, uint8(70), p.Confidence)
+
+	// the symbols without routes are aggregated directly.
+	p, err = r.resolve("EUR-USD", target, aggregate)
+	require.NoError(t, err)
+	require.Equal(t, "1.25", p.Price.String())
+
+	// the USDC-USD converted from the USDT markets joins the direct quotes in the VWAP, weighed by the volumes.
+	prices[USDCUSDT] = types.Price{Price: decimal.RequireFromString("0.8"), Confidence: 100, Volume: big.NewInt(90)}
+	prices[USDTUSD] = types.Price{Price: decimal.RequireFromString("1.25"), Confidence: 90}
+	p, err = r.resolve(USDCUSD, target, aggregate)
+	require.NoError(t, err)
+	require.Equal(t, "0.875", p.Price.String())
+	require.Equal(t, uint8(90), p.Confidence)
+	require.Equal(t, big.NewInt(90), p.Volume)
+
+	p, err = r.resolve(ATNUSD, target, aggregate)
+	require.NoError(t, err)
+	require.Equal(t, "1.75", p.Price.String())
+
+	// so do the majors quoted in USDT.
+	prices["BTC-USDT"] = types.Price{Price: decimal.RequireFromString("50000"), Confidence: 100, Volume: big.NewInt(40)}
+	p, err = r.resolve("BTC-USD", target, aggregate)
+	require.NoError(t, err)
+	require.Equal(t, "62500", p.Price.String())
+
+	// without the direct quotes, USDC-USD is converted from the USDT markets with USDT-USD.
+	delete(prices, USDCUSD)
+	prices[USDCUSDT] = types.Price{Price: decimal.RequireFromString("0.4"), Confidence: 100, Volume: big.NewInt(40)}
+	prices[USDTUSD] = types.Price{Price: decimal.RequireFromString("1.25"), Confidence: 90}
+	p, err = r.resolve(ATNUSD, target, aggregate)
+	require.NoError(t, err)
+	require.Equal(t, "1", p.Price.String())
+	require.Equal(t, uint8(90), p.Confidence)
+
+	// a path with any leg missing is not taken.
+	delete(prices, USDTUSD)
+	_, err = r.resolve(ATNUSD, target, aggregate)
+	require.ErrorIs(t, err, types.ErrNoDataRound)
+}
+
+func TestAggregateProtocolSymbolPricesWithRoutes(t *testing.T) {
+	target := time.Now().Unix()
+	plugin := pWrapper.NewPluginWrapper(hclog.Info, "forex_plugin", ".", nil, &config.PluginConfig{})
+	plugin.AddSample([]types.Price{
+		{Timestamp: target, Symbol: "EUR-USD", Price: decimal.RequireFromString("1.25")},
+		{Timestamp: target, Symbol: "XAU-EUR", Price: decimal.RequireFromString("2000")},
+	}, target)
+
+	router, err := newSymbolRouter([]config.SymbolRoute{
+		{Symbol: "XAU-USD", Paths: [][]string{{"XAU-EUR", "EUR-USD"}}},
+		{Symbol: "USD-EUR", Paths: [][]string{{"EUR-USD"}}},
+	})
+	require.NoError(t, err)
+
+	srv := &Server{
+		logger:          hclog.NewNullLogger(),
+		conf:            &config.Config{CEXSampling: config.DefaultSamplingConfig},
+		voteRecords:     make(map[uint64]*types.VoteRecord),
+		runningPlugins:  map[string]*pWrapper.PluginWrapper{"forex_plugin": plugin},
+		router:          router,
+		protocolSymbols: []string{"EUR-USD", "XAU-USD", "USD-EUR"},
+		curSampleTS:     target,
+	}
+
+	prices, err := srv.aggregateProtocolSymbolPrices(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, len(prices))
+	require.Equal(t, "1.25", prices["EUR-USD"].Price.String())
+	require.Equal(t, "2500", prices["XAU-USD"].Price.String())
+	require.Equal(t, "0.8", prices["USD-EUR"].Price.String())
+	require.Equal(t, "USD-EUR", prices["USD-EUR"].Symbol)
+}
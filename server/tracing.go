@@ -0,0 +1,94 @@
+package server
+
+import (
+	"autonity-oracle/monitor"
+	"autonity-oracle/types"
+	"context"
+	"math/big"
+	"time"
+
+	tp "github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// roundTrace keeps the span of a round which has a vote reported, thus the receipt of the vote which is tracked after
+// the handling of the round event can be traced into the round's trace.
+type roundTrace struct {
+	span       trace.SpanContext
+	reportedAt time.Time
+}
+
+// startRoundSpan starts the root span of the new round's trace, it covers the handling of the round event.
+func (os *Server) startRoundSpan(round *big.Int, height uint64, sampleTS int64, votePeriod uint64) trace.Span {
+	ctx, span := monitor.Tracer().Start(context.Background(), "NewRound", trace.WithAttributes(
+		attribute.Int64("round", round.Int64()),
+		attribute.Int64("height", int64(height)), //nolint
+		attribute.Int64("sampleTS", sampleTS),
+		attribute.Int64("votePeriod", int64(votePeriod)), //nolint
+	))
+	os.roundCtx = ctx
+	return span
+}
+
+// roundContext returns the tracing context of the current round, the spans of the round's voting are its children.
+func (os *Server) roundContext() context.Context {
+	if os.roundCtx == nil {
+		return context.Background()
+	}
+	return os.roundCtx
+}
+
+// keepRoundTrace keeps the span of the current round once its vote is reported.
+func (os *Server) keepRoundTrace() {
+	sc := trace.SpanContextFromContext(os.roundContext())
+	if !sc.IsValid() {
+		return
+	}
+	if os.roundTraces == nil {
+		os.roundTraces = make(map[uint64]*roundTrace)
+	}
+	os.roundTraces[os.curRound] = &roundTrace{span: sc, reportedAt: time.Now()}
+}
+
+// popRoundTrace takes the kept trace of the round out, it is nil if the round has no vote traced.
+func (os *Server) popRoundTrace(round uint64) *roundTrace {
+	rt, ok := os.roundTraces[round]
+	if !ok {
+		return nil
+	}
+	delete(os.roundTraces, round)
+	return rt
+}
+
+// traceVoteReceipt traces the vote of the round from its reporting until its receipt is observed, the receipt can be
+// nil if the vote was mined but its receipt is not available.
+func (rt *roundTrace) traceVoteReceipt(vote *types.VoteRecord, receipt *tp.Receipt) {
+	if rt == nil {
+		return
+	}
+
+	ctx := trace.ContextWithSpanContext(context.Background(), rt.span)
+	_, span := monitor.Tracer().Start(ctx, "VoteReceipt", trace.WithTimestamp(rt.reportedAt),
+		trace.WithAttributes(attribute.String("txn", vote.TxHash.Hex())))
+	if receipt != nil {
+		span.SetAttributes(attribute.Int64("status", int64(receipt.Status)), attribute.Int64("gasUsed", int64(receipt.GasUsed))) //nolint
+		if receipt.BlockNumber != nil {
+			span.SetAttributes(attribute.Int64("block", receipt.BlockNumber.Int64()))
+		}
+	}
+	if len(vote.Error) != 0 {
+		span.SetStatus(codes.Error, vote.Error)
+	}
+	span.End()
+}
+
+// gcRoundTraces drops the traces of the rounds whose votes are out of the tracked range.
+func (os *Server) gcRoundTraces() {
+	for r := range os.roundTraces {
+		if r+MaxBufferedRounds <= os.curRound {
+			delete(os.roundTraces, r)
+		}
+	}
+}
@@ -14,8 +14,24 @@ type DataSourceType int
 const (
 	SrcAMM DataSourceType = iota
 	SrcCEX
+	// SrcAnchor is the source of the low-frequency reference rates, e.g. of central banks. Their data points do not join
+	// the aggregation of the other sources but check its deviation, they price a symbol only if no other source does.
+	SrcAnchor
 )
 
+func (t DataSourceType) String() string {
+	switch t {
+	case SrcAMM:
+		return "amm"
+	case SrcCEX:
+		return "cex"
+	case SrcAnchor:
+		return "anchor"
+	default:
+		return "unknown"
+	}
+}
+
 // HandshakeConfig are used to just do a basic handshake between
 // a plugin and host. If the handshake fails, a user-friendly error is shown.
 // This prevents users from executing bad plugins or executing a plugin
@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -25,11 +26,13 @@ var (
 	ErrNoSymbolsObserved = errors.New("no symbols observed from oracle contract")
 	ErrMissingDataPoint  = errors.New("missing data point")
 	ErrMissingServiceKey = errors.New("the key to access the data source is missing, please check the plugin config")
+	ErrFetchTimeout      = errors.New("plugin did not return prices before the deadline")
+	ErrSamplingOverlap   = errors.New("last sampling of the plugin is still in progress")
 )
 
 // Price is the structure contains the exchange rate of a symbol with a timestamp at which the sampling happens.
 type Price struct {
-	Timestamp  int64           `json:"timestamp"` // TS on when the data is being sampled in time's seconds since Jan 1 1970 (Unix time).
+	Timestamp  int64           `json:"timestamp"` // TS of the quote given by the data source, or the sampling TS if the source has no quote TS, in Unix time.
 	Symbol     string          `json:"symbol"`
 	Price      decimal.Decimal `json:"price"`
 	Confidence uint8           `json:"confidence"` // confidence of the data point is resolved by the oracle server.
@@ -77,4 +80,5 @@ type JSONRPCMessage struct {
 type SampleEvent struct {
 	Symbols []string
 	TS      int64
+	Round   trace.SpanContext // the span of the last round, the plugins' fetching spans are linked to it.
 }